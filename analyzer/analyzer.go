@@ -0,0 +1,96 @@
+// Package analyzer exposes godox as a golang.org/x/tools/go/analysis Analyzer.
+package analyzer
+
+import (
+	"context"
+	"flag"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/matoous/godox"
+	"github.com/matoous/godox/config"
+)
+
+const doc = `report comments containing keywords such as TODO, BUG or FIXME
+
+Godox searches for comments starting with the given keywords and reports
+them, or, in format mode, reports keyword comments that do not match the
+configured format rules.`
+
+// Analyzer is the godox analyzer with default settings.
+var Analyzer = New(&config.GoDoxSettings{})
+
+// New returns a new godox analyzer using given settings.
+// The settings can be further adjusted using the analyzer flags.
+func New(settings *config.GoDoxSettings) *analysis.Analyzer {
+	a := &analysis.Analyzer{
+		Name: "godox",
+		Doc:  doc,
+		Run: func(pass *analysis.Pass) (interface{}, error) {
+			return run(pass, settings)
+		},
+	}
+
+	a.Flags.Init("godox", flag.ExitOnError)
+	a.Flags.Var((*keywords)(&settings.Keywords), "keywords", "comma separated list of keywords")
+
+	return a
+}
+
+func run(pass *analysis.Pass, settings *config.GoDoxSettings) (interface{}, error) {
+	compiled, err := settings.Compile()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range pass.Files {
+		messages, err := godox.RunCompiledContext(context.Background(), file, pass.Fset, compiled)
+		if err != nil {
+			return nil, err
+		}
+
+		tf := pass.Fset.File(file.Pos())
+		for _, m := range messages {
+			if m.Suppressed {
+				continue
+			}
+
+			d := analysis.Diagnostic{Pos: tf.Pos(m.Pos.Offset), End: tf.Pos(m.End.Offset), Message: m.Description()}
+
+			if m.Fix != "" {
+				d.SuggestedFixes = []analysis.SuggestedFix{{
+					Message: "Rewrite to " + m.Fix,
+					TextEdits: []analysis.TextEdit{{
+						Pos:     d.Pos,
+						End:     tf.Pos(m.Pos.Offset + len(m.Text)),
+						NewText: []byte(m.Fix),
+					}},
+				}}
+			}
+
+			pass.Report(d)
+		}
+	}
+
+	return nil, nil
+}
+
+// keywords implements flag.Value for a comma separated list of keywords.
+type keywords []string
+
+func (k *keywords) String() string {
+	return strings.Join(*k, ",")
+}
+
+func (k *keywords) Set(s string) error {
+	*k = nil
+
+	for _, kw := range strings.Split(s, ",") {
+		if kw = strings.TrimSpace(kw); kw != "" {
+			*k = append(*k, kw)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package analyzer_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/matoous/godox/analyzer"
+	"github.com/matoous/godox/config"
+)
+
+func TestAnalyzer(t *testing.T) {
+	t.Parallel()
+
+	analysistest.Run(t, analysistest.TestData(), analyzer.Analyzer, "a")
+}
+
+func TestSuggestedFixes(t *testing.T) {
+	t.Parallel()
+
+	a := analyzer.New(&config.GoDoxSettings{
+		Format:      true,
+		FormatRules: []config.GoDoxFormatRule{{Keyword: "TODO", RegularExpression: `^TODO: \w`}},
+	})
+
+	results := analysistest.Run(t, analysistest.TestData(), a, "b")
+	if len(results) != 1 || len(results[0].Diagnostics) != 1 {
+		t.Fatalf("expected one diagnostic, got %v", results)
+	}
+
+	fixes := results[0].Diagnostics[0].SuggestedFixes
+	if len(fixes) != 1 || len(fixes[0].TextEdits) != 1 {
+		t.Fatalf("expected one suggested fix, got %v", fixes)
+	}
+
+	edit := fixes[0].TextEdits[0]
+	fset := results[0].Pass.Fset
+
+	if start, end := fset.Position(edit.Pos), fset.Position(edit.End); start.Line != 3 || start.Column != 4 || end.Column != 73 {
+		t.Errorf("unexpected edit range %s-%s", start, end)
+	}
+
+	if expected := "TODO: implement foo // want `^Line does not match the expected format`"; string(edit.NewText) != expected {
+		t.Errorf("not equal\nexpected: %s\nactual: %s", expected, edit.NewText)
+	}
+}
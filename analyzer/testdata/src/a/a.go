@@ -0,0 +1,9 @@
+package a
+
+// TODO: implement foo // want `^Line contains TODO/BUG/FIXME: "TODO: implement foo`
+func foo() {}
+
+// This comment is fine.
+func bar() {
+	// FIXME: handle errors // want `^Line contains TODO/BUG/FIXME: "FIXME: handle errors`
+}
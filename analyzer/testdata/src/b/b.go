@@ -0,0 +1,7 @@
+package b
+
+// todo implement foo // want `^Line does not match the expected format`
+func foo() {}
+
+// TODO: implement bar
+func bar() {}
@@ -0,0 +1,146 @@
+// Package baseline allows adopting godox in existing code bases by recording the current findings
+// and reporting only findings which are not part of the recorded baseline.
+package baseline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/matoous/godox"
+)
+
+// SchemaVersion is the version of the baseline file schema.
+const SchemaVersion = 1
+
+// DefaultFile is the default name of the baseline file.
+const DefaultFile = ".godox-baseline.json"
+
+// Baseline is a snapshot of findings.
+type Baseline struct {
+	SchemaVersion int     `json:"schema_version"`
+	Findings      []Entry `json:"findings"`
+}
+
+// Entry is a single recorded finding. Entries are matched by fingerprints which don't depend on
+// the line of the finding, so the baseline stays valid when the surrounding code changes.
+type Entry struct {
+	Fingerprint string `json:"fingerprint"`
+	File        string `json:"file"`
+	Keyword     string `json:"keyword"`
+	Text        string `json:"text"`
+	// Count is the number of findings with the fingerprint, e.g. the same comment repeated in a file.
+	Count int `json:"count"`
+}
+
+// New creates baseline from the messages, suppressed messages are left out.
+func New(messages []godox.Message) *Baseline {
+	index := make(map[string]int)
+	b := &Baseline{SchemaVersion: SchemaVersion, Findings: []Entry{}}
+
+	for _, m := range messages {
+		if m.Suppressed {
+			continue
+		}
+
+		fp := m.Fingerprint()
+		if i, ok := index[fp]; ok {
+			b.Findings[i].Count++
+			continue
+		}
+
+		index[fp] = len(b.Findings)
+		b.Findings = append(b.Findings, Entry{
+			Fingerprint: fp,
+			File:        filepath.ToSlash(filepath.Clean(m.Pos.Filename)),
+			Keyword:     m.Keyword,
+			Text:        m.Text,
+			Count:       1,
+		})
+	}
+
+	sort.SliceStable(b.Findings, func(i, j int) bool {
+		if b.Findings[i].File != b.Findings[j].File {
+			return b.Findings[i].File < b.Findings[j].File
+		}
+
+		return b.Findings[i].Fingerprint < b.Findings[j].Fingerprint
+	})
+
+	return b
+}
+
+// Read reads baseline from the reader.
+func Read(r io.Reader) (*Baseline, error) {
+	var b Baseline
+	if err := json.NewDecoder(r).Decode(&b); err != nil {
+		return nil, fmt.Errorf("decode baseline: %w", err)
+	}
+
+	if b.SchemaVersion != SchemaVersion {
+		return nil, fmt.Errorf("unsupported baseline schema version %d", b.SchemaVersion)
+	}
+
+	return &b, nil
+}
+
+// Load reads baseline from the file.
+func Load(path string) (*Baseline, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Read(f)
+}
+
+// Write writes the baseline to the writer.
+func (b *Baseline) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(b)
+}
+
+// Save writes the baseline to the file.
+func (b *Baseline) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := b.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// Filter returns messages which are not part of the baseline.
+func (b *Baseline) Filter(messages []godox.Message) []godox.Message {
+	known := make(map[string]int, len(b.Findings))
+	for _, e := range b.Findings {
+		known[e.Fingerprint] += e.Count
+	}
+
+	var filtered []godox.Message
+
+	for _, m := range messages {
+		if !m.Suppressed {
+			fp := m.Fingerprint()
+			if known[fp] > 0 {
+				known[fp]--
+				continue
+			}
+		}
+
+		filtered = append(filtered, m)
+	}
+
+	return filtered
+}
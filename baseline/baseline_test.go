@@ -0,0 +1,94 @@
+package baseline_test
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/matoous/godox"
+	"github.com/matoous/godox/baseline"
+	"github.com/matoous/godox/config"
+)
+
+func run(t *testing.T, src string) []godox.Message {
+	t.Helper()
+
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return godox.Run(f, fset, &config.GoDoxSettings{})
+}
+
+func TestBaseline(t *testing.T) {
+	t.Parallel()
+
+	old := run(t, `package main
+
+// TODO: first
+// TODO: repeated
+func main() {
+	// TODO: repeated
+}
+`)
+
+	b := baseline.New(old)
+	if len(b.Findings) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(b.Findings))
+	}
+
+	var buf bytes.Buffer
+	if err := b.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := baseline.Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the code was shifted by a new declaration and new comments were added
+	current := run(t, `package main
+
+var x = 1
+
+// TODO: first
+// TODO: repeated
+func main() {
+	// TODO: repeated
+	// TODO: repeated
+	// FIXME: new
+}
+`)
+
+	filtered := b.Filter(current)
+
+	expected := []string{
+		`main.go:9: Line contains TODO/BUG/FIXME: "TODO: repeated"`,
+		`main.go:10: Line contains TODO/BUG/FIXME: "FIXME: new"`,
+	}
+
+	if len(filtered) != len(expected) {
+		t.Fatalf("expected %d messages, got %d:\n%q", len(expected), len(filtered), filtered)
+	}
+
+	for i, m := range filtered {
+		if m.Message != expected[i] {
+			t.Errorf("not equal\nexpected: %s\nactual: %s", expected[i], m.Message)
+		}
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{`{`, `{"schema_version": 2, "findings": []}`} {
+		if _, err := baseline.Read(bytes.NewBufferString(input)); err == nil {
+			t.Errorf("expected error for %s", input)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+// Package blame annotates findings with the author and the date of the commit which introduced
+// their lines, using git blame.
+package blame
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/matoous/godox"
+	"github.com/matoous/godox/config"
+)
+
+// notCommitted is the commit of lines which were not committed yet.
+const notCommitted = "0000000000000000000000000000000000000000"
+
+// Line is the commit which introduced a line.
+type Line struct {
+	Commit string
+	Author string
+	Email  string
+	Time   time.Time
+}
+
+// Lines are the commits of the lines of a file, by line number. Lines which were not committed yet are missing.
+type Lines map[int]Line
+
+// Parse parses the output of git blame --line-porcelain.
+func Parse(r io.Reader) (Lines, error) {
+	lines := make(Lines)
+
+	var (
+		line   Line
+		number int
+	)
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1024*1024)
+
+	for sc.Scan() {
+		text := sc.Text()
+
+		switch {
+		case strings.HasPrefix(text, "\t"):
+			// content of the line ends its entry
+			if line.Commit != notCommitted {
+				lines[number] = line
+			}
+
+			line, number = Line{}, 0
+		case line.Commit == "":
+			fields := strings.Fields(text)
+			if len(fields) < 3 {
+				return nil, fmt.Errorf("invalid blame header %q", text)
+			}
+
+			n, err := strconv.Atoi(fields[2])
+			if err != nil {
+				return nil, fmt.Errorf("invalid blame header %q: %w", text, err)
+			}
+
+			line.Commit, number = fields[0], n
+		case strings.HasPrefix(text, "author "):
+			line.Author = strings.TrimPrefix(text, "author ")
+		case strings.HasPrefix(text, "author-mail "):
+			line.Email = strings.Trim(strings.TrimPrefix(text, "author-mail "), "<>")
+		case strings.HasPrefix(text, "author-time "):
+			sec, err := strconv.ParseInt(strings.TrimPrefix(text, "author-time "), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid author time %q: %w", text, err)
+			}
+
+			line.Time = time.Unix(sec, 0).UTC()
+		}
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+// File returns the commits of the lines of the file in its working tree.
+// Files which are not tracked by git have no lines.
+func File(ctx context.Context, filename string) (Lines, error) {
+	dir, name := filepath.Split(filename)
+	if dir == "" {
+		dir = "."
+	}
+
+	out, err := git(ctx, dir, "blame", "--line-porcelain", "--", name)
+	if err != nil {
+		if _, untracked := git(ctx, dir, "ls-files", "--error-unmatch", "--", name); untracked != nil {
+			return Lines{}, nil
+		}
+
+		return nil, err
+	}
+
+	return Parse(bytes.NewReader(out))
+}
+
+func git(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+
+	return out, nil
+}
+
+// Annotate sets the author, commit and introduction date of the messages from git blame of their files.
+// Messages on lines which were not committed yet are left as they are.
+func Annotate(ctx context.Context, messages []godox.Message) error {
+	files := make(map[string]Lines)
+
+	for i := range messages {
+		m := &messages[i]
+
+		lines, ok := files[m.Pos.Filename]
+		if !ok {
+			var err error
+			if lines, err = File(ctx, m.Pos.Filename); err != nil {
+				return err
+			}
+
+			files[m.Pos.Filename] = lines
+		}
+
+		if line, ok := lines[m.Line]; ok {
+			m.Author = line.Author
+			m.Commit = line.Commit
+			m.Introduced = line.Time
+		}
+	}
+
+	return nil
+}
+
+// Age leaves out messages introduced less than MinAge ago and escalates severity of messages introduced
+// more than EscalateAge ago to error, using the effective settings of their files. The messages have to
+// be annotated, messages without the introduction date are treated as new.
+func Age(messages []godox.Message, settings *config.CompiledSettings) ([]godox.Message, error) {
+	var aged []godox.Message
+
+	for _, m := range messages {
+		s, err := settings.ForFile(m.Pos.Filename)
+		if err != nil {
+			return nil, err
+		}
+
+		age := time.Duration(-1)
+		if !m.Introduced.IsZero() {
+			age = s.Now.Sub(m.Introduced)
+		}
+
+		if s.MinAge > 0 && age < s.MinAge {
+			continue
+		}
+
+		if s.EscalateAge > 0 && age >= s.EscalateAge {
+			m.Severity = godox.SeverityError
+		}
+
+		aged = append(aged, m)
+	}
+
+	return aged, nil
+}
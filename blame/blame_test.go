@@ -0,0 +1,174 @@
+package blame_test
+
+import (
+	"context"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matoous/godox"
+	"github.com/matoous/godox/blame"
+	"github.com/matoous/godox/config"
+)
+
+const porcelain = `10ddd3e42115c4544dbc57058caefa6d22704e65 1 1 2
+author Alice
+author-mail <alice@example.com>
+author-time 1600000000
+author-tz +0200
+committer Alice
+committer-mail <alice@example.com>
+committer-time 1600000000
+committer-tz +0200
+summary initial
+boundary
+filename main.go
+	package main
+10ddd3e42115c4544dbc57058caefa6d22704e65 2 2
+author Alice
+author-mail <alice@example.com>
+author-time 1600000000
+author-tz +0200
+committer Alice
+committer-mail <alice@example.com>
+committer-time 1600000000
+committer-tz +0200
+summary initial
+boundary
+filename main.go
+	// TODO: committed
+0000000000000000000000000000000000000000 3 3 1
+author Not Committed Yet
+author-mail <not.committed.yet>
+author-time 1700000000
+author-tz +0000
+committer Not Committed Yet
+committer-mail <not.committed.yet>
+committer-time 1700000000
+committer-tz +0000
+summary Version of main.go from main.go
+filename main.go
+	// TODO: not committed
+`
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	lines, err := blame.Parse(strings.NewReader(porcelain))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := blame.Line{
+		Commit: "10ddd3e42115c4544dbc57058caefa6d22704e65",
+		Author: "Alice",
+		Email:  "alice@example.com",
+		Time:   time.Unix(1600000000, 0).UTC(),
+	}
+
+	if len(lines) != 2 || lines[1] != expected || lines[2] != expected {
+		t.Errorf("not equal\nexpected: %v\nactual: %v", expected, lines)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	t.Parallel()
+
+	if _, err := blame.Parse(strings.NewReader("10ddd3e4 x y\n")); err == nil {
+		t.Error("expected error for invalid header")
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "godox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=Alice", "-c", "user.email=alice@example.com"}, args...)...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE=2020-09-13T12:26:40Z")
+
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+
+	main := filepath.Join(dir, "main.go")
+	if err := ioutil.WriteFile(main, []byte("package main\n\n// TODO: committed\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	git("init", "-q")
+	git("add", "main.go")
+	git("commit", "-q", "-m", "initial")
+
+	if err := ioutil.WriteFile(main, []byte("package main\n\n// TODO: committed\n// TODO: not committed\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	untracked := filepath.Join(dir, "untracked.go")
+	if err := ioutil.WriteFile(untracked, []byte("package main\n\n// TODO: untracked\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	messages := []godox.Message{
+		{Pos: token.Position{Filename: main}, Line: 3},
+		{Pos: token.Position{Filename: main}, Line: 4},
+		{Pos: token.Position{Filename: untracked}, Line: 3},
+	}
+
+	if err := blame.Annotate(context.Background(), messages); err != nil {
+		t.Fatal(err)
+	}
+
+	if m := messages[0]; m.Author != "Alice" || len(m.Commit) != 40 || !m.Introduced.Equal(time.Unix(1600000000, 0)) {
+		t.Errorf("unexpected annotation of committed line: %+v", m)
+	}
+
+	for _, m := range messages[1:] {
+		if m.Author != "" || m.Commit != "" || !m.Introduced.IsZero() {
+			t.Errorf("unexpected annotation of uncommitted line: %+v", m)
+		}
+	}
+}
+
+func TestAge(t *testing.T) {
+	t.Parallel()
+
+	settings, err := (&config.GoDoxSettings{OlderThan: "30d", EscalateAfter: "180d"}).Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	days := func(n int) time.Time { return settings.Now.Add(-time.Duration(n) * 24 * time.Hour) }
+
+	messages := []godox.Message{
+		{Line: 1, Severity: godox.SeverityWarning, Introduced: days(1)},
+		{Line: 2, Severity: godox.SeverityWarning, Introduced: days(60)},
+		{Line: 3, Severity: godox.SeverityInfo, Introduced: days(200)},
+		{Line: 4, Severity: godox.SeverityWarning},
+	}
+
+	aged, err := blame.Age(messages, settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(aged) != 2 || aged[0].Line != 2 || aged[1].Line != 3 {
+		t.Fatalf("unexpected messages: %+v", aged)
+	}
+
+	if aged[0].Severity != godox.SeverityWarning || aged[1].Severity != godox.SeverityError {
+		t.Errorf("unexpected severities: %s, %s", aged[0].Severity, aged[1].Severity)
+	}
+}
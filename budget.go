@@ -0,0 +1,41 @@
+package godox
+
+import (
+	"fmt"
+
+	"github.com/matoous/godox/config"
+)
+
+// BudgetError is a budget of findings exceeded by the files matching its pattern.
+type BudgetError struct {
+	Pattern string
+	// Max is the number of findings allowed and Count the number of findings of the files.
+	Max   int
+	Count int
+}
+
+func (e *BudgetError) Error() string {
+	return fmt.Sprintf("budget of %s exceeded: %d findings, at most %d allowed", e.Pattern, e.Count, e.Max)
+}
+
+// CheckBudgets counts the messages which are not suppressed within the budgets of the settings and returns
+// the exceeded budgets sorted by their patterns. Messages count to all budgets matching their files.
+func CheckBudgets(messages []Message, settings *config.CompiledSettings) []*BudgetError {
+	var exceeded []*BudgetError
+
+	for _, b := range settings.PathBudgets {
+		count := 0
+
+		for _, m := range messages {
+			if !m.Suppressed && b.Match(m.Pos.Filename) {
+				count++
+			}
+		}
+
+		if count > b.Max {
+			exceeded = append(exceeded, &BudgetError{Pattern: b.Pattern, Max: b.Max, Count: count})
+		}
+	}
+
+	return exceeded
+}
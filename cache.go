@@ -0,0 +1,123 @@
+package godox
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/matoous/godox/config"
+)
+
+// cacheVersion is part of the cache keys, bump it whenever the results for the same input change.
+const cacheVersion = "21"
+
+// cache stores messages found in files keyed by the hash of the file content and the settings.
+// Errors are ignored as the cache only speeds up repeated runs.
+type cache struct {
+	dir string
+	// keys are hashes of the settings by *config.CompiledSettings, files can have different
+	// settings when nested configuration files are used
+	keys sync.Map
+}
+
+// newCache returns cache in the directory for the settings or nil if caching is disabled.
+func newCache(settings *config.CompiledSettings) *cache {
+	if settings.CacheDir == "" {
+		return nil
+	}
+
+	return &cache{dir: settings.CacheDir}
+}
+
+// key returns hash of the settings.
+func (c *cache) key(settings *config.CompiledSettings) []byte {
+	if key, ok := c.keys.Load(settings); ok {
+		return key.([]byte)
+	}
+
+	h := sha256.New()
+	h.Write([]byte(cacheVersion))
+
+	// settings are encoded with sorted map keys, the roster is loaded from a file so its content is hashed,
+	// and the results depend on the current date through the deadlines
+	_ = json.NewEncoder(h).Encode(settings.GoDoxSettings)
+
+	roster := make([]string, 0, len(settings.Roster))
+	for owner := range settings.Roster {
+		roster = append(roster, owner)
+	}
+
+	sort.Strings(roster)
+	_ = json.NewEncoder(h).Encode(roster)
+
+	h.Write([]byte(settings.Now.Format("2006-01-02")))
+
+	key := h.Sum(nil)
+	c.keys.Store(settings, key)
+
+	return key
+}
+
+func (c *cache) path(settings *config.CompiledSettings, filename string, content []byte) string {
+	h := sha256.New()
+	h.Write(c.key(settings))
+	h.Write([]byte(filename))
+	h.Write([]byte{0})
+	h.Write(content)
+
+	sum := hex.EncodeToString(h.Sum(nil))
+
+	return filepath.Join(c.dir, sum[:2], sum)
+}
+
+func (c *cache) get(settings *config.CompiledSettings, filename string, content []byte) ([]Message, bool) {
+	data, err := ioutil.ReadFile(c.path(settings, filename, content))
+	if err != nil {
+		return nil, false
+	}
+
+	var messages []Message
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&messages); err != nil {
+		return nil, false
+	}
+
+	return messages, true
+}
+
+func (c *cache) put(settings *config.CompiledSettings, filename string, content []byte, messages []Message) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(messages); err != nil {
+		return
+	}
+
+	path := c.path(settings, filename, content)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+
+	// write to a temporary file first so concurrent runs never read partially written entries
+	tmp, err := ioutil.TempFile(filepath.Dir(path), "tmp-")
+	if err != nil {
+		return
+	}
+
+	_, err = tmp.Write(buf.Bytes())
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+}
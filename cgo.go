@@ -0,0 +1,146 @@
+package godox
+
+import (
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// cgoComments returns the comments of the file with the cgo preamble, the comment preceding import "C",
+// replaced by the C comments within it. The preamble is C code, so the keywords are looked up only in its
+// comments, e.g. // TODO: free the buffer, and not in the code or its string literals.
+func cgoComments(file *ast.File) []*ast.CommentGroup {
+	preamble := cgoPreamble(file)
+	if preamble == nil {
+		return file.Comments
+	}
+
+	comments := make([]*ast.CommentGroup, 0, len(file.Comments))
+
+	for _, c := range file.Comments {
+		if c != preamble {
+			comments = append(comments, c)
+			continue
+		}
+
+		if group := cComments(c); len(group.List) > 0 {
+			comments = append(comments, group)
+		}
+	}
+
+	return comments
+}
+
+// cgoPreamble returns the doc comment of the import of the C pseudo-package cgo reads the preamble from,
+// nil if the file doesn't import it.
+func cgoPreamble(file *ast.File) *ast.CommentGroup {
+	for _, decl := range file.Decls {
+		d, ok := decl.(*ast.GenDecl)
+		if !ok || d.Tok != token.IMPORT {
+			continue
+		}
+
+		for _, spec := range d.Specs {
+			s, ok := spec.(*ast.ImportSpec)
+			if !ok || s.Path == nil {
+				continue
+			}
+
+			if path, err := strconv.Unquote(s.Path.Value); err != nil || path != "C" {
+				continue
+			}
+
+			if s.Doc != nil {
+				return s.Doc
+			}
+
+			if !d.Lparen.IsValid() {
+				return d.Doc
+			}
+		}
+	}
+
+	return nil
+}
+
+// cComments returns the C comments within the Go comments of the group. A block comment continued from
+// a previous line comment of the group is returned as a line comment starting at the Go comment.
+func cComments(group *ast.CommentGroup) *ast.CommentGroup {
+	const markerSize = 2
+
+	var (
+		list  []*ast.Comment
+		block bool
+	)
+
+	for _, c := range group.List {
+		body := c.Text[markerSize:]
+		if strings.HasPrefix(c.Text, "/*") {
+			body = strings.TrimSuffix(body, "*/")
+		}
+
+		base := c.Slash + markerSize
+
+		for i := 0; i < len(body); {
+			if block {
+				end := strings.Index(body[i:], "*/")
+				if end < 0 {
+					end = len(body) - i
+				} else {
+					block = false
+				}
+
+				list = append(list, &ast.Comment{Slash: c.Slash, Text: "//" + body[i:i+end]})
+				i += end + markerSize
+
+				continue
+			}
+
+			switch {
+			case strings.HasPrefix(body[i:], "//"):
+				end := strings.IndexByte(body[i:], '\n')
+				if end < 0 {
+					end = len(body) - i
+				}
+
+				list = append(list, &ast.Comment{Slash: base + token.Pos(i), Text: body[i : i+end]})
+				i += end
+			case strings.HasPrefix(body[i:], "/*"):
+				end := strings.Index(body[i+markerSize:], "*/")
+				if end < 0 {
+					// the comment continues on the next line comments of the group
+					end, block = len(body)-i-markerSize*2, true
+				}
+
+				list = append(list, &ast.Comment{Slash: base + token.Pos(i), Text: body[i : i+end+markerSize*2]})
+				i += end + markerSize*2
+			case body[i] == '"' || body[i] == '\'':
+				i = skipCLiteral(body, i)
+			default:
+				i++
+			}
+		}
+	}
+
+	return &ast.CommentGroup{List: list}
+}
+
+// skipCLiteral returns the index after the C string or character literal starting at the index,
+// unterminated literals end at the end of the line.
+func skipCLiteral(body string, start int) int {
+	quote := body[start]
+
+	for i := start + 1; i < len(body); i++ {
+		switch body[i] {
+		case '\\':
+			i++
+		case quote:
+			return i + 1
+		case '\n':
+			return i
+		}
+	}
+
+	return len(body)
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/matoous/godox"
+)
+
+// badge is the shields.io endpoint badge, see https://shields.io/badges/endpoint-badge.
+type badge struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// runBadge writes the shields.io endpoint JSON with the number of findings, colored by the thresholds.
+func runBadge(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("godox badge", "[flags] [packages]", stderr)
+
+	label := flags.String("label", "TODOs", "label of the badge")
+
+	var lf lintFlags
+	lf.register(flags)
+
+	out := flags.String("out", "", "file to write the badge JSON to (default standard output)")
+	yellow := flags.Int("yellow", 1, "number of findings from which the badge is yellow instead of green")
+	red := flags.Int("red", 50, "number of findings from which the badge is red instead of yellow")
+
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	if *yellow > *red {
+		return fail(stderr, fmt.Errorf("yellow threshold %d is above the red threshold %d", *yellow, *red))
+	}
+
+	ctx, cancel := lf.context()
+	defer cancel()
+
+	messages, err := lf.lint(ctx, flags.Args())
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	total := godox.Stats(messages).Total
+
+	b := badge{SchemaVersion: 1, Label: *label, Message: strconv.Itoa(total), Color: "brightgreen"}
+
+	switch {
+	case total >= *red:
+		b.Color = "red"
+	case total >= *yellow:
+		b.Color = "yellow"
+	}
+
+	w := stdout
+
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return fail(stderr, err)
+		}
+		defer f.Close()
+
+		w = f
+	}
+
+	if err := json.NewEncoder(w).Encode(b); err != nil {
+		return fail(stderr, err)
+	}
+
+	return exitOK
+}
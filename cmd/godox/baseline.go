@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/matoous/godox/baseline"
+)
+
+func runBaseline(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] != "write" {
+		return fail(stderr, errors.New("usage: godox baseline write [flags] [packages]"))
+	}
+
+	flags := newFlagSet("godox baseline write", "[flags] [packages]", stderr)
+
+	var lf lintFlags
+	lf.register(flags)
+
+	output := flags.String("o", baseline.DefaultFile, "baseline file to write")
+
+	if err := flags.Parse(args[1:]); err != nil {
+		return exitError
+	}
+
+	ctx, cancel := lf.context()
+	defer cancel()
+
+	messages, err := lf.lint(ctx, flags.Args())
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	b := baseline.New(messages)
+	if err := b.Save(*output); err != nil {
+		return fail(stderr, err)
+	}
+
+	var count int
+	for _, e := range b.Findings {
+		count += e.Count
+	}
+
+	fmt.Fprintf(stdout, "baseline with %d findings written to %s\n", count, *output)
+
+	return exitOK
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/matoous/godox/config"
+	"gopkg.in/yaml.v2"
+)
+
+func runConfig(args []string, stdout, stderr io.Writer) int {
+	if len(args) > 0 {
+		switch args[0] {
+		case "show":
+			return runConfigShow(args[1:], stdout, stderr)
+		case "validate":
+			return runConfigValidate(args[1:], stdout, stderr)
+		case "init":
+			return runConfigInit(args[1:], stdout, stderr)
+		case "schema":
+			return runConfigSchema(args[1:], stdout, stderr)
+		}
+	}
+
+	return fail(stderr, errors.New("usage: godox config show|validate|init|schema [flags]"))
+}
+
+// runConfigShow writes the effective settings of the files and the configuration files they come from.
+func runConfigShow(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("godox config show", "[flags] files", stderr)
+
+	var lf lintFlags
+	lf.register(flags)
+
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	if flags.NArg() == 0 {
+		return fail(stderr, errors.New("usage: godox config show [flags] files"))
+	}
+
+	settings, err := lf.settings()
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	compiled, err := settings.Compile()
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	for i, file := range flags.Args() {
+		effective, loaded, err := compiled.Hierarchy().Settings(file)
+		if err != nil {
+			return fail(stderr, err)
+		}
+
+		if lf.configFile != "" {
+			loaded = append([]string{lf.configFile}, loaded...)
+		}
+
+		if i > 0 {
+			fmt.Fprintln(stdout)
+		}
+
+		fmt.Fprintf(stdout, "# %s\n", file)
+
+		for _, path := range loaded {
+			fmt.Fprintf(stdout, "# loaded %s\n", displayPath(path))
+		}
+
+		out, err := yaml.Marshal(settingsMap(reflect.ValueOf(effective)))
+		if err != nil {
+			return fail(stderr, err)
+		}
+
+		if _, err := stdout.Write(out); err != nil {
+			return fail(stderr, err)
+		}
+	}
+
+	return exitOK
+}
+
+// runConfigValidate loads the configuration, compiles its regular expressions and checks its keywords,
+// writing the effective settings if there are no problems and the problems otherwise.
+func runConfigValidate(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("godox config validate", "[flags]", stderr)
+
+	var lf lintFlags
+	lf.register(flags)
+
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return exitError
+	}
+
+	settings, err := lf.settings()
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	if problems := settings.Validate(); len(problems) > 0 {
+		for _, problem := range problems {
+			fmt.Fprintf(stderr, "godox: %v\n", problem)
+		}
+
+		return exitError
+	}
+
+	compiled, err := settings.Compile()
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	if lf.configFile != "" {
+		fmt.Fprintf(stdout, "# loaded %s\n", displayPath(lf.configFile))
+	}
+
+	out, err := yaml.Marshal(settingsMap(reflect.ValueOf(compiled.GoDoxSettings)))
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	if _, err := stdout.Write(out); err != nil {
+		return fail(stderr, err)
+	}
+
+	return exitOK
+}
+
+// runConfigInit writes the commented starter configuration file of the preset.
+func runConfigInit(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("godox config init", "[flags]", stderr)
+
+	presets := make([]string, 0, len(configPresets))
+	for name := range configPresets {
+		presets = append(presets, name)
+	}
+
+	sort.Strings(presets)
+
+	preset := flags.String("preset", "relaxed", "preset of the configuration, one of: "+strings.Join(presets, ", "))
+	out := flags.String("out", config.DefaultFiles[0], "file to write the configuration to, - for standard output")
+	force := flags.Bool("force", false, "overwrite the file if it exists")
+
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return exitError
+	}
+
+	content, ok := configPresets[*preset]
+	if !ok {
+		return fail(stderr, fmt.Errorf("unknown preset %q, available presets: %s", *preset, strings.Join(presets, ", ")))
+	}
+
+	if *out == "-" {
+		if _, err := io.WriteString(stdout, content); err != nil {
+			return fail(stderr, err)
+		}
+
+		return exitOK
+	}
+
+	if _, err := os.Stat(*out); err == nil && !*force {
+		return fail(stderr, fmt.Errorf("%s already exists, use -force to overwrite it", *out))
+	}
+
+	f, err := os.Create(*out)
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	if _, err := io.WriteString(f, content); err != nil {
+		f.Close()
+		return fail(stderr, err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fail(stderr, err)
+	}
+
+	fmt.Fprintf(stdout, "wrote %s, check it with godox config validate\n", *out)
+
+	return exitOK
+}
+
+// runConfigSchema writes the JSON Schema of the configuration files.
+func runConfigSchema(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("godox config schema", "", stderr)
+
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return exitError
+	}
+
+	enc := json.NewEncoder(stdout)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(config.Schema()); err != nil {
+		return fail(stderr, err)
+	}
+
+	return exitOK
+}
+
+// settingsMap converts the settings to values keyed by the mapstructure tags, the same as in
+// the configuration files. Fields which can't be configured by files are omitted.
+func settingsMap(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Struct:
+		m := yaml.MapSlice{}
+
+		for i := 0; i < v.NumField(); i++ {
+			key := v.Type().Field(i).Tag.Get("mapstructure")
+			if key == "-" {
+				continue
+			}
+
+			if key == "" {
+				key = strings.ToLower(v.Type().Field(i).Name)
+			}
+
+			m = append(m, yaml.MapItem{Key: key, Value: settingsMap(v.Field(i))})
+		}
+
+		return m
+	case reflect.Map:
+		// nil maps are left unset, unlike the empty ones
+		if v.IsNil() {
+			return nil
+		}
+
+		return v.Interface()
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+
+		list := make([]interface{}, v.Len())
+		for i := range list {
+			list[i] = settingsMap(v.Index(i))
+		}
+
+		return list
+	default:
+		return v.Interface()
+	}
+}
+
+// displayPath returns the path relative to the working directory if possible.
+func displayPath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+
+	rel, err := filepath.Rel(wd, abs)
+	if err != nil {
+		return path
+	}
+
+	return rel
+}
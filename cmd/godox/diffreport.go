@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/matoous/godox"
+	"github.com/matoous/godox/report"
+)
+
+// runDiffReport compares the findings of two JSON reports, e.g. of two sprints, and writes the added
+// and removed findings and the deltas per keyword.
+func runDiffReport(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("godox diff-report", "[flags] old.json new.json", stderr)
+
+	format := flags.String("format", "markdown", "output format, one of: markdown, json")
+
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return exitError
+	}
+
+	var write func(c *report.Comparison, w io.Writer) error
+
+	switch *format {
+	case "markdown":
+		write = (*report.Comparison).WriteMarkdown
+	case "json":
+		write = (*report.Comparison).WriteJSON
+	default:
+		return fail(stderr, fmt.Errorf("unknown format %q, available formats: markdown, json", *format))
+	}
+
+	before, err := readReport(flags.Arg(0))
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	after, err := readReport(flags.Arg(1))
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	if err := write(report.Compare(before, after), stdout); err != nil {
+		return fail(stderr, err)
+	}
+
+	return exitOK
+}
+
+// readReport reads the findings of the JSON report or JSON Lines file.
+func readReport(path string) ([]godox.Message, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	messages, err := report.ReadJSON(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return messages, nil
+}
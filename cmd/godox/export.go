@@ -0,0 +1,243 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/matoous/godox"
+	"github.com/matoous/godox/internal/edit"
+	"github.com/matoous/godox/report"
+	"github.com/matoous/godox/tracker"
+)
+
+const (
+	defaultTitleTemplate = `{{.Summary}}`
+	defaultBodyTemplate  = `{{.Text}}
+
+Found in {{if .URL}}[{{.File}}:{{.Line}}]({{.URL}}){{else}}{{.File}}:{{.Line}}{{end}}` +
+		`{{if .Author}}, added by {{.Author}} on {{.Introduced.Format "2006-01-02"}}{{end}}.
+`
+)
+
+// exportedIssue is the data of the issue templates.
+type exportedIssue struct {
+	godox.Message
+
+	// File is the slash separated file name.
+	File string
+	// Summary is the comment line without the keyword, owner and the colon.
+	Summary string
+	// URL links to the source line if the source URL template is set.
+	URL string
+}
+
+// runExport creates tracker issues from the comments which don't reference an issue yet.
+func runExport(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] != "issues" {
+		return fail(stderr, errors.New("usage: godox export issues [flags] [packages]"))
+	}
+
+	flags := newFlagSet("godox export issues", "[flags] [packages]", stderr)
+
+	var lf lintFlags
+	lf.register(flags)
+
+	trackerName := flags.String("tracker", "github", "tracker to create the issues in: github, gitlab or jira")
+	title := flags.String("title", defaultTitleTemplate, "text/template of the issue titles")
+	body := flags.String("body", defaultBodyTemplate, "text/template of the issue bodies")
+	labels := flags.String("labels", "", "comma separated list of labels of the issues")
+	sourceURL := flags.String("source-url", "", "template of links to source lines, e.g. https://github.com/owner/repo/blob/main/{path}#L{line}")
+	dryRun := flags.Bool("dry-run", false, "print the issues instead of creating them")
+	fix := flags.Bool("fix", false, "append references to the created issues to the comments, e.g. TODO: refactor [#456]")
+
+	if err := flags.Parse(args[1:]); err != nil {
+		return exitError
+	}
+
+	titleTemplate, err := template.New("title").Parse(*title)
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	bodyTemplate, err := template.New("body").Parse(*body)
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	settings, err := lf.settings()
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	compiled, err := settings.Compile()
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	creator, err := tracker.NewCreator(*trackerName, compiled)
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	ctx, cancel := lf.context()
+	defer cancel()
+
+	messages, err := lf.lint(ctx, flags.Args())
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	// references are written back even if creating some of the issues fails
+	refs := make(referenceEdits)
+
+	for _, m := range untracked(messages) {
+		data := exportedIssue{Message: m, File: filepath.ToSlash(filepath.Clean(m.Pos.Filename)), Summary: summary(m)}
+		data.URL = report.SourceURL(*sourceURL, data.File, m.Line, m.Column)
+
+		issue := tracker.Issue{Labels: splitList(*labels)}
+
+		if issue.Title, err = execute(titleTemplate, data); err != nil {
+			return fail(stderr, err)
+		}
+
+		if issue.Body, err = execute(bodyTemplate, data); err != nil {
+			return fail(stderr, err)
+		}
+
+		issue.Title = strings.TrimSpace(issue.Title)
+
+		if *dryRun {
+			fmt.Fprintf(stdout, "%s:%d: would create issue %q\n%s\n", data.File, m.Line, issue.Title, indent(issue.Body))
+			continue
+		}
+
+		ref, err := creator.Create(ctx, issue)
+		if err != nil {
+			if werr := refs.write(); werr != nil {
+				fmt.Fprintf(stderr, "godox: %v\n", werr)
+			}
+
+			return fail(stderr, fmt.Errorf("%s:%d: %w", data.File, m.Line, err))
+		}
+
+		fmt.Fprintf(stdout, "%s:%d: created issue %s\n", data.File, m.Line, ref)
+
+		if *fix {
+			if err := refs.add(m.Pos.Filename, m.Line, ref); err != nil {
+				return fail(stderr, err)
+			}
+		}
+	}
+
+	if err := refs.write(); err != nil {
+		return fail(stderr, err)
+	}
+
+	return exitOK
+}
+
+// referenceEdits are edits appending issue references to the comment lines, by file name.
+type referenceEdits map[string][]edit.Edit
+
+// add appends the reference in brackets to the comment line, the reference is then found by the issue patterns.
+func (r referenceEdits) add(filename string, line int, ref string) error {
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	e, err := edit.AppendToLine(content, line, " ["+ref+"]")
+	if err != nil {
+		return fmt.Errorf("%s:%d: %w", filename, line, err)
+	}
+
+	r[filename] = append(r[filename], e)
+
+	return nil
+}
+
+// write applies the edits to the files.
+func (r referenceEdits) write() error {
+	for filename, edits := range r {
+		if err := edit.ApplyFile(filename, edits); err != nil {
+			return err
+		}
+
+		delete(r, filename)
+	}
+
+	return nil
+}
+
+// untracked returns messages which don't reference an issue, one per comment line.
+func untracked(messages []godox.Message) []godox.Message {
+	var (
+		filtered []godox.Message
+		seen     = make(map[string]struct{})
+	)
+
+	for _, m := range messages {
+		if m.Issue != "" || m.Suppressed {
+			continue
+		}
+
+		key := fmt.Sprintf("%s:%d", m.Pos.Filename, m.Line)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+
+		seen[key] = struct{}{}
+		filtered = append(filtered, m)
+	}
+
+	return filtered
+}
+
+// summary returns the comment line without the leading keyword, the owner in parenthesis and the colon,
+// or the whole line if nothing is left.
+func summary(m godox.Message) string {
+	text := m.Text
+	if len(text) >= len(m.Keyword) && strings.EqualFold(text[:len(m.Keyword)], m.Keyword) {
+		text = text[len(m.Keyword):]
+	}
+
+	if strings.HasPrefix(text, "(") {
+		if end := strings.IndexByte(text, ')'); end >= 0 {
+			text = text[end+1:]
+		}
+	}
+
+	text = strings.TrimSpace(strings.TrimLeft(text, ":- "))
+	if text == "" {
+		return m.Text
+	}
+
+	return text
+}
+
+func execute(t *template.Template, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// indent indents the non-empty lines of the text.
+func indent(text string) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = "    " + line
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"io/ioutil"
+
+	"github.com/matoous/godox"
+	"github.com/matoous/godox/internal/edit"
+)
+
+// applyFixes rewrites the comment lines of the messages with suggested fixes and returns the messages
+// which were not fixed. Lines which changed since they were scanned are left as they are.
+func applyFixes(messages []godox.Message) ([]godox.Message, error) {
+	var (
+		remaining []godox.Message
+		edits     = make(map[string][]edit.Edit)
+		contents  = make(map[string][]byte)
+		fixed     = make(map[string]map[int]bool)
+	)
+
+	for _, m := range messages {
+		if m.Fix == "" || m.Suppressed {
+			remaining = append(remaining, m)
+			continue
+		}
+
+		name := m.Pos.Filename
+
+		content, ok := contents[name]
+		if !ok {
+			var err error
+			if content, err = ioutil.ReadFile(name); err != nil {
+				return nil, err
+			}
+
+			contents[name] = content
+			fixed[name] = make(map[int]bool)
+		}
+
+		start, end := m.Pos.Offset, m.Pos.Offset+len(m.Text)
+		if end > len(content) || string(content[start:end]) != m.Text {
+			remaining = append(remaining, m)
+			continue
+		}
+
+		if !fixed[name][start] {
+			fixed[name][start] = true
+			edits[name] = append(edits[name], edit.Edit{Start: start, End: end, New: m.Fix})
+		}
+	}
+
+	for name, e := range edits {
+		if err := edit.ApplyFile(name, e); err != nil {
+			return nil, err
+		}
+	}
+
+	return remaining, nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/matoous/godox/config"
+	"github.com/matoous/godox/history"
+)
+
+// runHistory scans the revisions of the git repository in the working directory checked in at every step
+// and writes the numbers of findings per keyword over time.
+func runHistory(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("godox history", "-since DATE [flags]", stderr)
+
+	var lf lintFlags
+	lf.register(flags)
+
+	since := flags.String("since", "", "date of the first revision, e.g. 2023-01-01")
+	until := flags.String("until", "", "date of the last revision (default now)")
+	step := flags.String("step", "1w", "time between the revisions, e.g. 1d, 1w or 30d")
+	rev := flags.String("rev", "HEAD", "revision whose first parent history is scanned")
+	format := flags.String("format", "text", "output format, one of: text, csv, json")
+
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	if *since == "" || flags.NArg() > 0 {
+		flags.Usage()
+		return exitError
+	}
+
+	var write func(s history.Series, w io.Writer) error
+
+	switch *format {
+	case "text":
+		write = history.Series.WriteText
+	case "csv":
+		write = history.Series.WriteCSV
+	case "json":
+		write = history.Series.WriteJSON
+	default:
+		return fail(stderr, fmt.Errorf("unknown format %q, available formats: text, csv, json", *format))
+	}
+
+	opts := history.Options{Rev: *rev}
+
+	var err error
+
+	if opts.Since, err = time.Parse("2006-01-02", *since); err != nil {
+		return fail(stderr, fmt.Errorf("invalid date %q", *since))
+	}
+
+	if *until != "" {
+		if opts.Until, err = time.Parse("2006-01-02", *until); err != nil {
+			return fail(stderr, fmt.Errorf("invalid date %q", *until))
+		}
+	}
+
+	if opts.Step, err = config.ParseAge(*step); err != nil || opts.Step == 0 {
+		return fail(stderr, fmt.Errorf("invalid step %q", *step))
+	}
+
+	settings, err := lf.settings()
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	ctx, cancel := lf.context()
+	defer cancel()
+
+	series, err := history.Run(ctx, opts, &settings)
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	if err := write(series, stdout); err != nil {
+		return fail(stderr, err)
+	}
+
+	return exitOK
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/matoous/godox/report"
+)
+
+// runLeaderboard writes the open debt per owner, or per author according to git blame for the findings without
+// owners, unlike the linter it doesn't fail when there are findings.
+func runLeaderboard(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("godox leaderboard", "[flags] [packages]", stderr)
+
+	var lf lintFlags
+	lf.register(flags)
+
+	format := flags.String("format", "markdown", "output format, one of: markdown, json")
+
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	var write func(b report.Leaderboard, w io.Writer) error
+
+	switch *format {
+	case "markdown":
+		write = report.Leaderboard.WriteMarkdown
+	case "json":
+		write = report.Leaderboard.WriteJSON
+	default:
+		return fail(stderr, fmt.Errorf("unknown format %q, available formats: markdown, json", *format))
+	}
+
+	ctx, cancel := lf.context()
+	defer cancel()
+
+	messages, err := lf.lint(ctx, flags.Args())
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	if err := write(report.NewLeaderboard(messages), stdout); err != nil {
+		return fail(stderr, err)
+	}
+
+	return exitOK
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"io"
+
+	"github.com/matoous/godox/lsp"
+)
+
+// runLSP runs the language server on the standard input and output.
+func runLSP(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := newFlagSet("godox lsp", "[flags]", stderr)
+
+	var lf lintFlags
+	lf.register(flags)
+
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	settings, err := lf.settings()
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	// invalid settings are reported right away instead of on every change of the documents
+	if _, err := settings.Compile(); err != nil {
+		return fail(stderr, err)
+	}
+
+	ctx, cancel := interruptContext()
+	defer cancel()
+
+	server := lsp.NewServer(&settings)
+	server.Timeout = lf.timeout
+
+	if err := server.Serve(ctx, stdin, stdout); err != nil {
+		return fail(stderr, err)
+	}
+
+	return exitOK
+}
@@ -0,0 +1,797 @@
+// Command godox reports comments containing keywords such as TODO, BUG or FIXME.
+//
+// Usage:
+//
+//	godox [flags] [packages]
+//	godox baseline write [flags] [packages]
+//	godox ratchet [flags] [packages]
+//	godox watch [flags] [paths]
+//	godox config show [flags] [files]
+//	godox config validate [flags]
+//	godox config init [flags]
+//	godox config schema
+//	godox report [flags] [packages]
+//	godox diff-report [flags] old.json new.json
+//	godox history -since DATE [flags]
+//	godox badge [flags] [packages]
+//	godox leaderboard [flags] [packages]
+//	godox snoozed [flags] [packages]
+//	godox export issues [flags] [packages]
+//	godox triage [flags] [packages]
+//	godox lsp [flags]
+//	godox serve [flags] [packages]
+//
+// Use -baseline or -diff to report only new findings, e.g. in pull requests.
+//
+// Packages are specified the same way as for the go command, e.g. ./... or std.
+// When no packages are given, the package in the current directory is checked.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/matoous/godox"
+	"github.com/matoous/godox/baseline"
+	"github.com/matoous/godox/blame"
+	"github.com/matoous/godox/config"
+	"github.com/matoous/godox/diff"
+	"github.com/matoous/godox/report"
+	"github.com/matoous/godox/tracker"
+)
+
+// Exit codes.
+const (
+	exitOK       = 0
+	exitFindings = 1
+	exitError    = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) > 0 {
+		switch args[0] {
+		case "baseline":
+			return runBaseline(args[1:], stdout, stderr)
+		case "ratchet":
+			return runRatchet(args[1:], stdout, stderr)
+		case "watch":
+			return runWatch(args[1:], stdout, stderr)
+		case "config":
+			return runConfig(args[1:], stdout, stderr)
+		case "report":
+			return runReport(args[1:], stdout, stderr)
+		case "diff-report":
+			return runDiffReport(args[1:], stdout, stderr)
+		case "history":
+			return runHistory(args[1:], stdout, stderr)
+		case "export":
+			return runExport(args[1:], stdout, stderr)
+		case "triage":
+			return runTriage(args[1:], os.Stdin, stdout, stderr)
+		case "leaderboard":
+			return runLeaderboard(args[1:], stdout, stderr)
+		case "snoozed":
+			return runSnoozed(args[1:], stdout, stderr)
+		case "badge":
+			return runBadge(args[1:], stdout, stderr)
+		case "serve":
+			return runServe(args[1:], stdout, stderr)
+		case "lsp":
+			return runLSP(args[1:], os.Stdin, stdout, stderr)
+		}
+	}
+
+	return runLint(args, stdout, stderr)
+}
+
+func runLint(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("godox", "[flags] [packages]", stderr)
+
+	var lf lintFlags
+	lf.register(flags)
+
+	format := flags.String("format", "text", "output format, one of: "+strings.Join(report.Formats(), ", "))
+	baselineFile := flags.String("baseline", "", "report only findings which are not part of the baseline file")
+	failOn := flags.String("fail-on", "info", "minimal severity of findings causing non-zero exit code: error, warning or info")
+	revRange := flags.String("diff", "", "report only findings on lines changed in the git revision range, e.g. origin/main...HEAD")
+	sourceURL := flags.String("source-url", "", "template of links to source lines in reports, e.g. https://github.com/owner/repo/blob/main/{path}#L{line}")
+	columns := flags.String("columns", "", "comma separated list of columns of the csv and tsv formats (default "+strings.Join(report.DefaultCSVColumns, ",")+")")
+	fix := flags.Bool("fix", false, "rewrite comments violating the format rules to the suggested fixes, reporting only the other findings")
+	maxIssues := flags.Int("max-issues", -1, "number of findings allowed before the exit code is non-zero, all of them are allowed if negative")
+	maxPerKeyword := flags.String("max-per-keyword", "", "comma separated list of numbers of findings allowed per keyword, e.g. TODO=50,FIXME=0")
+	exitZero := flags.Bool("exit-zero", false, "exit with zero exit code even if there are findings")
+	out := flags.String("out", "", "file to write the findings to instead of the standard output, the database of the sqlite format")
+	summary := flags.Bool("summary", false, "print counts of findings per keyword, package, file and owner instead of the findings, in text or json format")
+	top := flags.Int("top", report.DefaultTop, "number of files and packages listed by the top format")
+	sortBy := flags.String("sort", "position", "order of the findings: position or priority")
+	groupBy := flags.String("group-by", "", "group the findings in text format by one of: "+strings.Join(report.Groups, ", "))
+
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	if *summary && *format != "text" && *format != "json" {
+		return fail(stderr, fmt.Errorf("summary is not available in the %s format", *format))
+	}
+
+	if *sortBy != "position" && *sortBy != "priority" {
+		return fail(stderr, fmt.Errorf("unknown order %q", *sortBy))
+	}
+
+	if *groupBy != "" && *format != "text" {
+		return fail(stderr, fmt.Errorf("grouping is not available in the %s format", *format))
+	}
+
+	if *groupBy != "" && !contains(report.Groups, *groupBy) {
+		return fail(stderr, fmt.Errorf("unknown group %q, available groups: %s", *groupBy, strings.Join(report.Groups, ", ")))
+	}
+
+	opts := report.Options{SourceURL: *sourceURL, Columns: splitList(*columns), GroupBy: *groupBy, Top: *top}
+
+	reporter, err := report.NewWithOptions(*format, opts)
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	switch *failOn {
+	case config.SeverityError, config.SeverityWarning, config.SeverityInfo:
+	default:
+		return fail(stderr, fmt.Errorf("unknown severity %q", *failOn))
+	}
+
+	policy, err := newExitPolicy(*failOn, *maxIssues, *maxPerKeyword, *exitZero)
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	ctx, cancel := lf.context()
+	defer cancel()
+
+	// the findings are streamed only if they are written right away
+	stream := *format == "jsonl" && *out == "" && !*summary && *baselineFile == "" && *revRange == "" && !*fix && *sortBy == "position"
+
+	var messages []godox.Message
+
+	if stream {
+		messages, err = lf.lintFunc(ctx, flags.Args(), func(found []godox.Message) error {
+			return report.JSONL(stdout, found)
+		})
+	} else {
+		messages, err = lf.lint(ctx, flags.Args())
+	}
+
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	if *baselineFile != "" {
+		b, err := baseline.Load(*baselineFile)
+		if err != nil {
+			return fail(stderr, err)
+		}
+
+		messages = b.Filter(messages)
+	}
+
+	if *revRange != "" {
+		if messages, err = filterDiff(ctx, *revRange, messages); err != nil {
+			return fail(stderr, err)
+		}
+	}
+
+	if *fix {
+		if messages, err = applyFixes(messages); err != nil {
+			return fail(stderr, err)
+		}
+	}
+
+	if *sortBy == "priority" {
+		godox.SortByPriority(messages)
+	}
+
+	settings, err := lf.settings()
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	compiled, err := settings.Compile()
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	exceeded := godox.CheckBudgets(messages, compiled)
+	policy.budgeted = len(compiled.PathBudgets) > 0
+
+	v := policy.check(messages, exceeded)
+
+	var (
+		w    = stdout
+		file *os.File
+	)
+
+	fileReporter, writesFile := reporter.(report.FileReporter)
+
+	if *out != "" && !writesFile {
+		if file, err = os.Create(*out); err != nil {
+			return fail(stderr, err)
+		}
+
+		w = file
+	}
+
+	switch {
+	case *summary && *format == "json" && policy.limited():
+		err = summaryJSON(w, godox.Stats(messages), &v)
+	case *summary && *format == "json":
+		err = report.SummaryJSON(w, godox.Stats(messages))
+	case *summary:
+		if err = report.Summary(w, godox.Stats(messages)); err == nil && policy.limited() {
+			writeVerdict(w, v)
+		}
+	case *out != "" && writesFile:
+		err = fileReporter.ReportFile(*out, messages)
+	case stream:
+	default:
+		err = reporter.Report(w, messages)
+	}
+
+	if file != nil {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	if !*summary {
+		for _, e := range exceeded {
+			fmt.Fprintf(stderr, "godox: %v\n", e)
+		}
+	}
+
+	return policy.exitCode(v)
+}
+
+// lintFlags are the flags shared by all commands running the linter.
+type lintFlags struct {
+	flags *flag.FlagSet
+	// configFile is the configuration file loaded by settings, if any
+	configFile string
+
+	config       string
+	keywords     string
+	forbidden    string
+	severities   string
+	minLengths   string
+	aliases      string
+	tags         string
+	include      string
+	exclude      string
+	files        string
+	budgets      string
+	deadlineMode string
+	roster       string
+	cacheDir     string
+	concurrency  int
+	engine       string
+	dedupe       bool
+	tests        bool
+	skipGen      bool
+	skipLicense  bool
+	caseSens     bool
+	anywhere     bool
+	listMarkers  bool
+	allMatches   bool
+	multiline    bool
+	requireIssue bool
+	requireOwner bool
+	exportedDocs bool
+	scanStrings  bool
+	suppressed   bool
+	disabled     string
+	msgFormat    string
+	msgLength    int
+	blame        bool
+	olderThan    string
+	minPriority  string
+	label        string
+	// labelTaken is set if the -label flag was defined before by the command, e.g. by the badge command
+	// for the text of the badge, the labels can be configured only by the configuration file then
+	labelTaken  bool
+	escalate    string
+	github      string
+	checkIssues bool
+	missing     bool
+	timeout     time.Duration
+}
+
+func (lf *lintFlags) register(flags *flag.FlagSet) {
+	lf.flags = flags
+
+	flags.StringVar(&lf.config, "config", "", "configuration file (default "+strings.Join(config.DefaultFiles, ", ")+" found in the working directory or its parents)")
+	flags.StringVar(&lf.keywords, "keywords", "", "comma separated list of keywords (default TODO,BUG,FIXME)")
+	flags.StringVar(&lf.forbidden, "forbidden-keywords", "", "comma separated list of keywords reported as errors wherever they occur, e.g. FIXME,HACK")
+	flags.StringVar(&lf.severities, "severities", "", "comma separated list of keyword severities, e.g. FIXME=error,TODO=warning")
+	flags.StringVar(&lf.minLengths, "min-description-length", "", "comma separated list of minimum description lengths of keywords, e.g. TODO=10,FIXME=20")
+	flags.StringVar(&lf.aliases, "aliases", "", "comma separated list of keyword aliases, e.g. HACK=FIXME,WIP=TODO, builtin adds the built-in ones")
+	flags.BoolVar(&lf.anywhere, "anywhere", false, "match keywords anywhere in the comment lines, not only at their start")
+	flags.BoolVar(&lf.listMarkers, "trim-list-markers", false, "match keywords following list markers, e.g. - TODO or 1. FIXME")
+	flags.BoolVar(&lf.allMatches, "report-all-matches", false, "report every keyword in the comment lines and every format rule they violate")
+	flags.BoolVar(&lf.multiline, "multiline", false, "merge the lines continuing the keyword comment lines into single findings")
+	flags.BoolVar(&lf.caseSens, "case-sensitive", false, "match keywords only in the configured case")
+	flags.IntVar(&lf.concurrency, "j", 0, "number of files scanned in parallel (default GOMAXPROCS)")
+	flags.BoolVar(&lf.dedupe, "deduplicate", false, "report identical findings at the same position once")
+	flags.StringVar(&lf.engine, "engine", "", "engine reading the comments: parser (default) or fast, tokenizing only the comments")
+	flags.StringVar(&lf.cacheDir, "cache-dir", defaultCacheDir(), "directory of the results cache, empty to disable caching")
+	flags.BoolVar(&lf.tests, "tests", true, "include test files")
+	flags.StringVar(&lf.include, "include-paths", "", "comma separated list of glob patterns of files to scan, e.g. pkg/**")
+	flags.StringVar(&lf.exclude, "exclude-paths", "", "comma separated list of glob patterns of files to skip, e.g. vendor/**,**/testdata/**")
+	flags.StringVar(&lf.files, "include", "", "comma separated list of glob patterns of other than Go files to scan, e.g. *.proto,Dockerfile")
+	flags.StringVar(&lf.budgets, "budgets", "", "comma separated list of numbers of findings allowed in files matching glob patterns, e.g. pkg/payments/**=0")
+	flags.BoolVar(&lf.skipGen, "skip-generated", true, "skip generated files")
+	flags.BoolVar(&lf.skipLicense, "skip-license-headers", false, "skip the license headers preceding the package clause")
+	flags.StringVar(&lf.tags, "tags", "", "comma separated list of additional build tags")
+	flags.BoolVar(&lf.requireIssue, "require-issue-reference", false, "report only comments which don't reference an issue")
+	flags.BoolVar(&lf.requireOwner, "require-owner", false, "report only comments which don't specify an owner, e.g. TODO(alice)")
+	flags.BoolVar(&lf.exportedDocs, "forbid-exported-docs", false, "report keywords in doc comments of exported declarations and packages as errors")
+	flags.BoolVar(&lf.scanStrings, "scan-strings", false, "report keywords found in string literals with the string-literal rule")
+	flags.StringVar(&lf.roster, "owner-roster", "", "report only comments with owners missing in the roster file (CODEOWNERS, YAML or text list)")
+	flags.StringVar(&lf.deadlineMode, "deadline-mode", "", "handling of deadlines in comments: expired or escalate")
+	flags.BoolVar(&lf.checkIssues, "check-issues", false, "report only comments referencing closed issues, looking them up in their trackers")
+	flags.BoolVar(&lf.missing, "report-missing-issues", false, "report comments referencing issues which don't exist too when checking the issues")
+	flags.StringVar(&lf.github, "github-repository", "", "repository of GitHub issue references without one, e.g. #123 (default $GITHUB_REPOSITORY)")
+	flags.StringVar(&lf.msgFormat, "message-format", "", "text/template of the messages following the file name and line, e.g. '{{.Keyword}} in {{.Declaration}}: {{.Text}}'")
+	flags.IntVar(&lf.msgLength, "max-message-length", config.DefaultMaxMessageLength, "number of characters of the comments quoted in the messages, 0 to quote them whole")
+	flags.StringVar(&lf.disabled, "disable-rules", "", "comma separated list of IDs of format rules and built-in rules not to report, e.g. format-0,issue")
+	flags.BoolVar(&lf.suppressed, "report-suppressed", false, "report findings suppressed by nolint or godox:ignore directives")
+	flags.BoolVar(&lf.blame, "blame", false, "annotate findings with the author and date of the commit introducing them, using git blame")
+	flags.StringVar(&lf.minPriority, "min-priority", "", "report only findings with the priority or a higher one, e.g. P1 for P0 and P1, findings without priorities are left out")
+	flags.StringVar(&lf.olderThan, "older-than", "", "report only findings introduced longer ago than the age according to git blame, e.g. 180d")
+	flags.DurationVar(&lf.timeout, "timeout", 0, "stop scanning and looking up issues after the duration, e.g. 5m")
+	flags.StringVar(&lf.escalate, "escalate-after", "", "escalate severity of findings introduced longer ago than the age according to git blame to error, e.g. 90d")
+
+	// the badge command defines -label for the text of the badge
+	if lf.labelTaken = flags.Lookup("label") != nil; !lf.labelTaken {
+		flags.StringVar(&lf.label, "label", "", "comma separated labels, report only findings with any of them, e.g. perf for #perf or [perf]")
+	}
+}
+
+// flagFields copy the settings of the flags from src to dst.
+var flagFields = map[string]func(dst, src *config.GoDoxSettings){
+	"keywords":                func(dst, src *config.GoDoxSettings) { dst.Keywords = src.Keywords },
+	"forbidden-keywords":      func(dst, src *config.GoDoxSettings) { dst.ForbiddenKeywords = src.ForbiddenKeywords },
+	"severities":              func(dst, src *config.GoDoxSettings) { dst.Severities = src.Severities },
+	"min-description-length":  func(dst, src *config.GoDoxSettings) { dst.MinDescriptionLength = src.MinDescriptionLength },
+	"aliases":                 func(dst, src *config.GoDoxSettings) { dst.Aliases = src.Aliases },
+	"anywhere":                func(dst, src *config.GoDoxSettings) { dst.Anywhere = src.Anywhere },
+	"trim-list-markers":       func(dst, src *config.GoDoxSettings) { dst.TrimListMarkers = src.TrimListMarkers },
+	"report-all-matches":      func(dst, src *config.GoDoxSettings) { dst.ReportAllMatches = src.ReportAllMatches },
+	"multiline":               func(dst, src *config.GoDoxSettings) { dst.Multiline = src.Multiline },
+	"case-sensitive":          func(dst, src *config.GoDoxSettings) { dst.CaseSensitive = src.CaseSensitive },
+	"j":                       func(dst, src *config.GoDoxSettings) { dst.Concurrency = src.Concurrency },
+	"engine":                  func(dst, src *config.GoDoxSettings) { dst.Engine = src.Engine },
+	"deduplicate":             func(dst, src *config.GoDoxSettings) { dst.Deduplicate = src.Deduplicate },
+	"cache-dir":               func(dst, src *config.GoDoxSettings) { dst.CacheDir = src.CacheDir },
+	"tests":                   func(dst, src *config.GoDoxSettings) { dst.Tests = src.Tests },
+	"include-paths":           func(dst, src *config.GoDoxSettings) { dst.IncludePaths = src.IncludePaths },
+	"exclude-paths":           func(dst, src *config.GoDoxSettings) { dst.ExcludePaths = src.ExcludePaths },
+	"include":                 func(dst, src *config.GoDoxSettings) { dst.Include = src.Include },
+	"budgets":                 func(dst, src *config.GoDoxSettings) { dst.Budgets = src.Budgets },
+	"skip-generated":          func(dst, src *config.GoDoxSettings) { dst.SkipGenerated = src.SkipGenerated },
+	"skip-license-headers":    func(dst, src *config.GoDoxSettings) { dst.SkipLicenseHeaders = src.SkipLicenseHeaders },
+	"tags":                    func(dst, src *config.GoDoxSettings) { dst.BuildTags = src.BuildTags },
+	"require-issue-reference": func(dst, src *config.GoDoxSettings) { dst.RequireIssueReference = src.RequireIssueReference },
+	"require-owner":           func(dst, src *config.GoDoxSettings) { dst.RequireOwner = src.RequireOwner },
+	"forbid-exported-docs":    func(dst, src *config.GoDoxSettings) { dst.ForbidExportedDocs = src.ForbidExportedDocs },
+	"scan-strings":            func(dst, src *config.GoDoxSettings) { dst.ScanStrings = src.ScanStrings },
+	"owner-roster":            func(dst, src *config.GoDoxSettings) { dst.OwnerRoster = src.OwnerRoster },
+	"deadline-mode":           func(dst, src *config.GoDoxSettings) { dst.DeadlineMode = src.DeadlineMode },
+	"check-issues":            func(dst, src *config.GoDoxSettings) { dst.CheckIssues = src.CheckIssues },
+	"report-missing-issues":   func(dst, src *config.GoDoxSettings) { dst.ReportMissingIssues = src.ReportMissingIssues },
+	"github-repository":       func(dst, src *config.GoDoxSettings) { dst.GitHub.Repository = src.GitHub.Repository },
+	"older-than":              func(dst, src *config.GoDoxSettings) { dst.OlderThan = src.OlderThan },
+	"min-priority":            func(dst, src *config.GoDoxSettings) { dst.MinPriority = src.MinPriority },
+	"label":                   func(dst, src *config.GoDoxSettings) { dst.Labels = src.Labels },
+	"escalate-after":          func(dst, src *config.GoDoxSettings) { dst.EscalateAfter = src.EscalateAfter },
+	"message-format":          func(dst, src *config.GoDoxSettings) { dst.MessageTemplate = src.MessageTemplate },
+	"max-message-length":      func(dst, src *config.GoDoxSettings) { dst.MaxMessageLength = src.MaxMessageLength },
+	"disable-rules":           func(dst, src *config.GoDoxSettings) { dst.DisabledRules = src.DisabledRules },
+	"report-suppressed":       func(dst, src *config.GoDoxSettings) { dst.ReportSuppressed = src.ReportSuppressed },
+}
+
+// settings returns the settings from the configuration file, if there is one, overridden by the flags
+// which were set. The flag defaults are used for settings missing in both.
+func (lf *lintFlags) settings() (config.GoDoxSettings, error) {
+	flagSettings, err := lf.flagSettings()
+	if err != nil {
+		return config.GoDoxSettings{}, err
+	}
+
+	path := lf.config
+	if path == "" {
+		if path, err = config.Find("."); err != nil {
+			return config.GoDoxSettings{}, err
+		}
+	}
+
+	// the flags override the environment which overrides the configuration file, nested configuration files
+	// are looked up below the directory of the configuration file
+	settings := flagSettings
+	settings.ConfigRoot = "."
+
+	if path != "" {
+		if err := settings.LoadFile(path); err != nil {
+			return config.GoDoxSettings{}, err
+		}
+
+		settings.ConfigRoot = filepath.Dir(path)
+		lf.configFile = path
+	}
+
+	if err := settings.LoadEnv(os.LookupEnv); err != nil {
+		return config.GoDoxSettings{}, err
+	}
+
+	lf.flags.Visit(func(f *flag.Flag) {
+		if copyField, ok := flagFields[f.Name]; ok && !(f.Name == "label" && lf.labelTaken) {
+			copyField(&settings, &flagSettings)
+		}
+	})
+
+	return settings, nil
+}
+
+// flagSettings returns the settings of the flags.
+func (lf *lintFlags) flagSettings() (config.GoDoxSettings, error) {
+	severities, err := splitPairs(lf.severities, "keyword severity", "KEYWORD=SEVERITY")
+	if err != nil {
+		return config.GoDoxSettings{}, err
+	}
+
+	aliases, err := splitAliases(lf.aliases)
+	if err != nil {
+		return config.GoDoxSettings{}, err
+	}
+
+	lengths, err := splitPairs(lf.minLengths, "minimum description length", "KEYWORD=LENGTH")
+	if err != nil {
+		return config.GoDoxSettings{}, err
+	}
+
+	var minLengths map[string]int
+
+	for kw, length := range lengths {
+		n, err := strconv.Atoi(length)
+		if err != nil {
+			return config.GoDoxSettings{}, fmt.Errorf("invalid minimum description length %q of %s", length, kw)
+		}
+
+		if minLengths == nil {
+			minLengths = make(map[string]int, len(lengths))
+		}
+
+		minLengths[kw] = n
+	}
+
+	pathBudgets, err := splitPairs(lf.budgets, "budget", "PATTERN=N")
+	if err != nil {
+		return config.GoDoxSettings{}, err
+	}
+
+	var budgets map[string]int
+
+	for pattern, max := range pathBudgets {
+		n, err := strconv.Atoi(max)
+		if err != nil {
+			return config.GoDoxSettings{}, fmt.Errorf("invalid budget %q of %s", max, pattern)
+		}
+
+		if budgets == nil {
+			budgets = make(map[string]int, len(pathBudgets))
+		}
+
+		budgets[pattern] = n
+	}
+
+	return config.GoDoxSettings{
+		Keywords:              splitList(lf.keywords),
+		ForbiddenKeywords:     splitList(lf.forbidden),
+		Aliases:               aliases,
+		Severities:            severities,
+		MinDescriptionLength:  minLengths,
+		Anywhere:              lf.anywhere,
+		TrimListMarkers:       lf.listMarkers,
+		ReportAllMatches:      lf.allMatches,
+		Multiline:             lf.multiline,
+		CaseSensitive:         lf.caseSens,
+		RequireIssueReference: lf.requireIssue,
+		RequireOwner:          lf.requireOwner,
+		ForbidExportedDocs:    lf.exportedDocs,
+		ScanStrings:           lf.scanStrings,
+		OwnerRoster:           lf.roster,
+		DeadlineMode:          lf.deadlineMode,
+		CheckIssues:           lf.checkIssues,
+		ReportMissingIssues:   lf.missing,
+		GitHub:                config.GitHubSettings{Repository: lf.github},
+		OlderThan:             lf.olderThan,
+		MinPriority:           lf.minPriority,
+		Labels:                splitList(lf.label),
+		EscalateAfter:         lf.escalate,
+		IncludePaths:          splitList(lf.include),
+		ExcludePaths:          splitList(lf.exclude),
+		Include:               splitList(lf.files),
+		Budgets:               budgets,
+		SkipGenerated:         lf.skipGen,
+		SkipLicenseHeaders:    lf.skipLicense,
+		BuildTags:             splitList(lf.tags),
+		Tests:                 lf.tests,
+		Concurrency:           lf.concurrency,
+		Engine:                lf.engine,
+		Deduplicate:           lf.dedupe,
+		CacheDir:              lf.cacheDir,
+		DisabledRules:         splitList(lf.disabled),
+		MessageTemplate:       lf.msgFormat,
+		MaxMessageLength:      &lf.msgLength,
+		ReportSuppressed:      lf.suppressed,
+	}, nil
+}
+
+// context returns context canceled on interrupt or once the timeout, if set, passes.
+func (lf *lintFlags) context() (context.Context, context.CancelFunc) {
+	ctx, cancel := interruptContext()
+	scan, cancelScan := lf.scanContext(ctx)
+
+	return scan, func() {
+		cancelScan()
+		cancel()
+	}
+}
+
+// scanContext returns context of a single scan canceled once the timeout, if set, passes. The commands running
+// until interrupted limit each of their scans by the timeout rather than their whole run.
+func (lf *lintFlags) scanContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if lf.timeout > 0 {
+		return context.WithTimeout(ctx, lf.timeout)
+	}
+
+	return context.WithCancel(ctx)
+}
+
+// interruptContext returns context canceled on interrupt.
+func interruptContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+
+	go func() {
+		select {
+		case <-interrupt:
+			cancel()
+		case <-ctx.Done():
+		}
+
+		signal.Stop(interrupt)
+	}()
+
+	return ctx, cancel
+}
+
+// lint runs the linter on packages matching the patterns, the current package is used if there are none.
+func (lf *lintFlags) lint(ctx context.Context, patterns []string) ([]godox.Message, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	settings, err := lf.settings()
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := godox.RunPackages(ctx, patterns, &settings)
+	if err != nil {
+		return nil, err
+	}
+
+	aged := settings.OlderThan != "" || settings.EscalateAfter != ""
+	if !settings.CheckIssues && !aged && !lf.blame {
+		return messages, nil
+	}
+
+	compiled, err := settings.Compile()
+	if err != nil {
+		return nil, err
+	}
+
+	if settings.CheckIssues {
+		if messages, err = tracker.NewChecker(compiled).Check(ctx, messages); err != nil {
+			return nil, err
+		}
+	}
+
+	if lf.blame || aged {
+		if err := blame.Annotate(ctx, messages); err != nil {
+			return nil, err
+		}
+	}
+
+	if !aged {
+		return messages, nil
+	}
+
+	return blame.Age(messages, compiled)
+}
+
+// lintFunc runs the linter like lint, calling fn with the messages of each file as soon as the file is scanned,
+// and returns all the messages. The messages are passed to fn at once if they are processed after the scan,
+// e.g. to look up the issues or to deduplicate them.
+func (lf *lintFlags) lintFunc(ctx context.Context, patterns []string, fn func([]godox.Message) error) ([]godox.Message, error) {
+	settings, err := lf.settings()
+	if err != nil {
+		return nil, err
+	}
+
+	if settings.CheckIssues || settings.OlderThan != "" || settings.EscalateAfter != "" || lf.blame || settings.Deduplicate {
+		messages, err := lf.lint(ctx, patterns)
+		if err != nil {
+			return nil, err
+		}
+
+		return messages, fn(messages)
+	}
+
+	if len(patterns) == 0 {
+		patterns = []string{"."}
+	}
+
+	var messages []godox.Message
+
+	err = godox.RunPackagesFunc(ctx, patterns, &settings, func(found []godox.Message) error {
+		messages = append(messages, found...)
+		return fn(found)
+	})
+
+	return messages, err
+}
+
+// filterDiff returns messages on lines changed in the revision range of the repository in the working directory.
+func filterDiff(ctx context.Context, revRange string, messages []godox.Message) ([]godox.Message, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	if wd, err = filepath.EvalSymlinks(wd); err != nil {
+		return nil, err
+	}
+
+	changes, err := diff.Git(ctx, wd, revRange)
+	if err != nil {
+		return nil, err
+	}
+
+	return changes.Filter(wd, messages), nil
+}
+
+// defaultCacheDir returns godox directory in the user cache directory, GODOXCACHE overrides the default.
+func defaultCacheDir() string {
+	if dir, ok := os.LookupEnv("GODOXCACHE"); ok {
+		return dir
+	}
+
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "godox")
+}
+
+func newFlagSet(name, usage string, stderr io.Writer) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "usage: %s %s\n\n", name, usage)
+		flags.PrintDefaults()
+	}
+
+	return flags
+}
+
+func fail(stderr io.Writer, err error) int {
+	fmt.Fprintf(stderr, "godox: %v\n", err)
+	return exitError
+}
+
+func splitList(s string) []string {
+	var list []string
+
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
+
+// contains reports whether the list contains the item.
+func contains(list []string, item string) bool {
+	for _, s := range list {
+		if s == item {
+			return true
+		}
+	}
+
+	return false
+}
+
+// splitAliases parses the comma separated list of aliases, the builtin item adds the config.BuiltinAliases
+// which aren't listed.
+func splitAliases(s string) (map[string]string, error) {
+	var (
+		items   []string
+		builtin bool
+	)
+
+	for _, item := range splitList(s) {
+		if item == config.BuiltinAliasesName {
+			builtin = true
+			continue
+		}
+
+		items = append(items, item)
+	}
+
+	aliases, err := splitPairs(strings.Join(items, ","), "keyword alias", "ALIAS=KEYWORD")
+	if err != nil || !builtin {
+		return aliases, err
+	}
+
+	if aliases == nil {
+		aliases = make(map[string]string, len(config.BuiltinAliases))
+	}
+
+	for alias, keyword := range config.BuiltinAliases {
+		if _, ok := aliases[alias]; !ok {
+			aliases[alias] = keyword
+		}
+	}
+
+	return aliases, nil
+}
+
+// splitPairs parses comma separated list of KEY=VALUE pairs, what and format describe the pairs in errors.
+// The map is nil if there are no pairs.
+func splitPairs(s, what, format string) (map[string]string, error) {
+	var pairs map[string]string
+
+	for _, item := range splitList(s) {
+		i := strings.IndexByte(item, '=')
+		if i < 0 {
+			return nil, fmt.Errorf("invalid %s %q, expected %s", what, item, format)
+		}
+
+		if pairs == nil {
+			pairs = make(map[string]string)
+		}
+
+		pairs[item[:i]] = item[i+1:]
+	}
+
+	return pairs, nil
+}
@@ -0,0 +1,1036 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	// keep the tests from writing to the user cache directory
+	os.Setenv("GODOXCACHE", "")
+	os.Exit(m.Run())
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		args   []string
+		output []string
+		code   int
+	}{
+		{
+			args: []string{"../../fixtures/03"},
+			output: []string{
+				`../../fixtures/03/main.go:1: Line contains TODO/BUG/FIXME: "TODO: Add package documentation"`,
+				`../../fixtures/03/main.go:2: Line contains TODO/BUG/FIXME: "TODO: Write an actual application"`,
+				`../../fixtures/03/main.go:9: Line contains TODO/BUG/FIXME: "FIXME: Spelling"`,
+				`../../fixtures/03/main.go:14: Line contains TODO/BUG/FIXME: "TODO: Multi line 1"`,
+				`../../fixtures/03/main.go:15: Line contains TODO/BUG/FIXME: "TODO: Multi line 2"`,
+				`../../fixtures/03/main.go:16: Line contains TODO/BUG/FIXME: "FIXME: Mutli line 3"`,
+			},
+			code: exitFindings,
+		},
+		{
+			args: []string{"-keywords", "FIXME", "../../fixtures/01/example1.go"},
+			output: []string{
+				`../../fixtures/01/example1.go:27: Line contains FIXME: "FIXME: Your attitude (Line 26)"`,
+			},
+			code: exitFindings,
+		},
+		{
+			args: []string{"-keywords", "FIXME", "-include", "*.sql", "../../fixtures/08", "../../fixtures/11"},
+			output: []string{
+				`../../fixtures/08/cgo.go:5: Line contains FIXME: "FIXME: free the strings"`,
+				`../../fixtures/08/schema.sql:2: Line contains FIXME: "FIXME: unique names"`,
+				`../../fixtures/11/asm.s:5: Line contains FIXME: "FIXME: load the answer"`,
+			},
+			code: exitFindings,
+		},
+		{
+			args: []string{"-tests=false", "../../fixtures/02"},
+			output: []string{
+				`../../fixtures/02/example3.go:4: Line contains TODO/BUG/FIXME: "TODO: remove foo (Line 3)"`,
+				`../../fixtures/02/example3.go:8: Line contains TODO/BUG/FIXME: "TODO: Rename field (Line 7)"`,
+				`../../fixtures/02/example3.go:11: Line contains TODO/BUG/FIXME: "TODO: get cat food (Line 10)"`,
+				`../../fixtures/02/example3.go:16: Line contains TODO/BUG/FIXME: "todo  : todo comment (Line 15)"`,
+			},
+			code: exitFindings,
+		},
+		{
+			args: []string{"-message-format", "{{.Keyword}}{{with .Declaration}} in {{.}}{{end}}: {{.Text}}", "-keywords", "FIXME", "../../fixtures/03"},
+			output: []string{
+				`../../fixtures/03/main.go:9: FIXME in main: FIXME: Spelling`,
+				`../../fixtures/03/main.go:16: FIXME: FIXME: Mutli line 3`,
+			},
+			code: exitFindings,
+		},
+		{
+			args: []string{"../../fixtures/04"},
+			code: exitOK,
+		},
+		{
+			args: []string{"-format", "jsonl", "-keywords", "FIXME", "../../fixtures/01/example1.go"},
+			output: []string{
+				`{"file":"../../fixtures/01/example1.go","line":27,"column":3,"end_column":8,"keyword":"FIXME","canonical_keyword":"FIXME",` +
+					`"rule":"keyword","severity":"warning","text":"FIXME: Your attitude (Line 26)",` +
+					`"message":"Line contains FIXME: \"FIXME: Your attitude (Line 26)\""}`,
+			},
+			code: exitFindings,
+		},
+		{
+			args: []string{"-severities", "FIXME=error", "-fail-on", "error", "../../fixtures/00"},
+			output: []string{
+				`../../fixtures/00/example1.go:3: Line contains TODO/BUG/FIXME: "TODO"`,
+			},
+			code: exitOK,
+		},
+		{
+			args: []string{"-severities", "todo=error", "-fail-on", "error", "../../fixtures/00"},
+			output: []string{
+				`../../fixtures/00/example1.go:3: Line contains TODO/BUG/FIXME: "TODO"`,
+			},
+			code: exitFindings,
+		},
+		{
+			args: []string{"-severities", "TODO", "../../fixtures/00"},
+			code: exitError,
+		},
+		{
+			args: []string{"-severities", "TODO=fatal", "../../fixtures/00"},
+			code: exitError,
+		},
+		{
+			args: []string{"../../fixtures/09"},
+			output: []string{
+				`../../fixtures/09/generated.go:3: Line contains TODO/BUG/FIXME: "TODO: handwritten"`,
+			},
+			code: exitFindings,
+		},
+		{
+			args: []string{"-skip-generated=false", "../../fixtures/09"},
+			output: []string{
+				`../../fixtures/09/generated.go:3: Line contains TODO/BUG/FIXME: "TODO: handwritten"`,
+				`../../fixtures/09/mock.go:5: Line contains TODO/BUG/FIXME: "TODO: generated mock"`,
+			},
+			code: exitFindings,
+		},
+		{
+			args: []string{"-config", "testdata/config.yml", "../../fixtures/09"},
+			output: []string{
+				`../../fixtures/09/generated.go:3: Line contains TODO: "TODO: handwritten"`,
+				`../../fixtures/09/mock.go:5: Line contains TODO: "TODO: generated mock"`,
+			},
+			code: exitFindings,
+		},
+		{
+			args: []string{"-config", "testdata/config.yml", "-skip-generated", "-keywords", "BUG,TODO", "../../fixtures/09"},
+			output: []string{
+				`../../fixtures/09/generated.go:3: Line contains BUG/TODO: "TODO: handwritten"`,
+			},
+			code: exitFindings,
+		},
+		{
+			args: []string{"-config", "testdata/nonexistent.yml", "../../fixtures/09"},
+			code: exitError,
+		},
+		{
+			args: []string{"-summary", "-keywords", "FIXME", "../../fixtures/01/example1.go"},
+			output: []string{
+				"Total: 1 (0 errors, 1 warnings, 0 info), 0 suppressed",
+				"",
+				"Keywords:",
+				"  FIXME  1",
+				"",
+				"Packages:",
+				"  ../../fixtures/01  1",
+				"",
+				"Files:",
+				"  ../../fixtures/01/example1.go  1",
+				"",
+				"Owners:",
+				"  (none)  1",
+			},
+			code: exitFindings,
+		},
+		{
+			args: []string{"-summary", "-max-issues", "0", "-keywords", "FIXME", "../../fixtures/01/example1.go"},
+			output: []string{
+				"Total: 1 (0 errors, 1 warnings, 0 info), 0 suppressed",
+				"",
+				"Keywords:",
+				"  FIXME  1",
+				"",
+				"Packages:",
+				"  ../../fixtures/01  1",
+				"",
+				"Files:",
+				"  ../../fixtures/01/example1.go  1",
+				"",
+				"Owners:",
+				"  (none)  1",
+				"",
+				"Policy: failed",
+				"  1 findings, at most 0 allowed",
+			},
+			code: exitFindings,
+		},
+		{
+			args: []string{"-max-issues", "10", "-max-per-keyword", "fixme=0", "../../fixtures/03"},
+			output: []string{
+				`../../fixtures/03/main.go:1: Line contains TODO/BUG/FIXME: "TODO: Add package documentation"`,
+				`../../fixtures/03/main.go:2: Line contains TODO/BUG/FIXME: "TODO: Write an actual application"`,
+				`../../fixtures/03/main.go:9: Line contains TODO/BUG/FIXME: "FIXME: Spelling"`,
+				`../../fixtures/03/main.go:14: Line contains TODO/BUG/FIXME: "TODO: Multi line 1"`,
+				`../../fixtures/03/main.go:15: Line contains TODO/BUG/FIXME: "TODO: Multi line 2"`,
+				`../../fixtures/03/main.go:16: Line contains TODO/BUG/FIXME: "FIXME: Mutli line 3"`,
+			},
+			code: exitFindings,
+		},
+		{
+			args: []string{"-max-issues", "10", "-max-per-keyword", "FIXME=2", "../../fixtures/03"},
+			output: []string{
+				`../../fixtures/03/main.go:1: Line contains TODO/BUG/FIXME: "TODO: Add package documentation"`,
+				`../../fixtures/03/main.go:2: Line contains TODO/BUG/FIXME: "TODO: Write an actual application"`,
+				`../../fixtures/03/main.go:9: Line contains TODO/BUG/FIXME: "FIXME: Spelling"`,
+				`../../fixtures/03/main.go:14: Line contains TODO/BUG/FIXME: "TODO: Multi line 1"`,
+				`../../fixtures/03/main.go:15: Line contains TODO/BUG/FIXME: "TODO: Multi line 2"`,
+				`../../fixtures/03/main.go:16: Line contains TODO/BUG/FIXME: "FIXME: Mutli line 3"`,
+			},
+			code: exitOK,
+		},
+		{
+			args: []string{"-summary", "-budgets", "fixtures/03/**=3", "-keywords", "FIXME", "../../fixtures/03"},
+			output: []string{
+				"Total: 2 (0 errors, 2 warnings, 0 info), 0 suppressed",
+				"",
+				"Keywords:",
+				"  FIXME  2",
+				"",
+				"Packages:",
+				"  ../../fixtures/03  2",
+				"",
+				"Files:",
+				"  ../../fixtures/03/main.go  2",
+				"",
+				"Owners:",
+				"  (none)  2",
+				"",
+				"Policy: passed",
+			},
+			code: exitOK,
+		},
+		{
+			args: []string{"-summary", "-budgets", "fixtures/03/**=3", "../../fixtures/03"},
+			output: []string{
+				"Total: 6 (0 errors, 6 warnings, 0 info), 0 suppressed",
+				"",
+				"Keywords:",
+				"  TODO   4",
+				"  FIXME  2",
+				"",
+				"Packages:",
+				"  ../../fixtures/03  6",
+				"",
+				"Files:",
+				"  ../../fixtures/03/main.go  6",
+				"",
+				"Owners:",
+				"  (none)  6",
+				"",
+				"Policy: failed",
+				"  budget of fixtures/03/** exceeded: 6 findings, at most 3 allowed",
+			},
+			code: exitFindings,
+		},
+		{
+			args: []string{"-budgets", "fixtures/03/**=-1", "../../fixtures/03"},
+			code: exitError,
+		},
+		{
+			args: []string{"-exit-zero", "../../fixtures/00"},
+			output: []string{
+				`../../fixtures/00/example1.go:3: Line contains TODO/BUG/FIXME: "TODO"`,
+			},
+			code: exitOK,
+		},
+		{
+			args: []string{"-max-per-keyword", "FIXME=none", "../../fixtures/00"},
+			code: exitError,
+		},
+		{
+			args:   []string{"badge", "../../fixtures/03"},
+			output: []string{`{"schemaVersion":1,"label":"TODOs","message":"6","color":"yellow"}`},
+			code:   exitOK,
+		},
+		{
+			args:   []string{"badge", "-label", "debt", "-red", "5", "../../fixtures/03"},
+			output: []string{`{"schemaVersion":1,"label":"debt","message":"6","color":"red"}`},
+			code:   exitOK,
+		},
+		{
+			args:   []string{"badge", "../../fixtures/04"},
+			output: []string{`{"schemaVersion":1,"label":"TODOs","message":"0","color":"brightgreen"}`},
+			code:   exitOK,
+		},
+		{
+			args: []string{"badge", "-yellow", "10", "-red", "5", "../../fixtures/03"},
+			code: exitError,
+		},
+		{
+			args: []string{"-group-by", "keyword", "../../fixtures/03"},
+			output: []string{
+				`FIXME`,
+				`  ../../fixtures/03/main.go:9: Line contains TODO/BUG/FIXME: "FIXME: Spelling"`,
+				`  ../../fixtures/03/main.go:16: Line contains TODO/BUG/FIXME: "FIXME: Mutli line 3"`,
+				`  2 findings`,
+				``,
+				`TODO`,
+				`  ../../fixtures/03/main.go:1: Line contains TODO/BUG/FIXME: "TODO: Add package documentation"`,
+				`  ../../fixtures/03/main.go:2: Line contains TODO/BUG/FIXME: "TODO: Write an actual application"`,
+				`  ../../fixtures/03/main.go:14: Line contains TODO/BUG/FIXME: "TODO: Multi line 1"`,
+				`  ../../fixtures/03/main.go:15: Line contains TODO/BUG/FIXME: "TODO: Multi line 2"`,
+				`  4 findings`,
+			},
+			code: exitFindings,
+		},
+		{
+			args: []string{"-group-by", "author", "../../fixtures/03"},
+			code: exitError,
+		},
+		{
+			args: []string{"-group-by", "owner", "-format", "json", "../../fixtures/03"},
+			code: exitError,
+		},
+		{
+			args: []string{"leaderboard", "-format", "json", "../../fixtures/04"},
+			output: []string{
+				`{`,
+				`  "schema_version": 1,`,
+				`  "people": []`,
+				`}`,
+			},
+			code: exitOK,
+		},
+		{
+			args: []string{"leaderboard", "-format", "html", "../../fixtures/05"},
+			code: exitError,
+		},
+		{
+			args: []string{"-sort", "owner", "../../fixtures/03"},
+			code: exitError,
+		},
+		{
+			args: []string{"-min-priority", "P1", "../../fixtures/03"},
+			code: exitOK,
+		},
+		{
+			args: []string{"-label", "perf", "../../fixtures/03"},
+			code: exitOK,
+		},
+		{
+			args: []string{"snoozed", "../../fixtures/03"},
+			code: exitOK,
+		},
+		{
+			args: []string{"-group-by", "label", "-label", "perf", "../../fixtures/03"},
+			code: exitOK,
+		},
+		{
+			args: []string{"history"},
+			code: exitError,
+		},
+		{
+			args: []string{"history", "-since", "2023-01-01", "-step", "0d"},
+			code: exitError,
+		},
+		{
+			args: []string{"history", "-since", "01/01/2023"},
+			code: exitError,
+		},
+		{
+			args: []string{"history", "-since", "2023-01-01", "-format", "sarif"},
+			code: exitError,
+		},
+		{
+			args: []string{"-summary", "-format", "sarif", "../../fixtures/00"},
+			code: exitError,
+		},
+		{
+			args: []string{"../../fixtures/nonexistent"},
+			code: exitError,
+		},
+		{
+			args: []string{"-timeout", "1ns", "../../fixtures/03"},
+			code: exitError,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt //nolint // reason tt is ok on this context
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			t.Parallel()
+
+			var stdout, stderr bytes.Buffer
+
+			code := run(tt.args, &stdout, &stderr)
+			if code != tt.code {
+				t.Errorf("unexpected exit code %d, expected %d, stderr:\n%s", code, tt.code, stderr.String())
+			}
+
+			var expected string
+			if len(tt.output) > 0 {
+				expected = strings.Join(tt.output, "\n") + "\n"
+			}
+
+			if stdout.String() != expected {
+				t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expected, stdout.String())
+			}
+		})
+	}
+}
+
+func TestBaseline(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "godox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "baseline.json")
+
+	var stdout, stderr bytes.Buffer
+
+	if code := run([]string{"baseline", "write", "-o", file, "../../fixtures/03"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("unexpected exit code %d, stderr:\n%s", code, stderr.String())
+	}
+
+	if expected := "baseline with 6 findings written to " + file + "\n"; stdout.String() != expected {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expected, stdout.String())
+	}
+
+	stdout.Reset()
+
+	if code := run([]string{"-baseline", file, "../../fixtures/03", "../../fixtures/00"}, &stdout, &stderr); code != exitFindings {
+		t.Fatalf("unexpected exit code %d, stderr:\n%s", code, stderr.String())
+	}
+
+	expected := `../../fixtures/00/example1.go:3: Line contains TODO/BUG/FIXME: "TODO"` + "\n"
+	if stdout.String() != expected {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expected, stdout.String())
+	}
+
+	if code := run([]string{"baseline"}, &stdout, &stderr); code != exitError {
+		t.Errorf("unexpected exit code %d for missing subcommand", code)
+	}
+}
+
+func TestRatchet(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "godox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "ratchet.json")
+
+	var stdout, stderr bytes.Buffer
+
+	if code := run([]string{"ratchet", "-f", file, "../../fixtures/03"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("unexpected exit code %d, stderr:\n%s", code, stderr.String())
+	}
+
+	if expected := "ratchet with 6 findings written to " + file + "\n"; stdout.String() != expected {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expected, stdout.String())
+	}
+
+	stdout.Reset()
+
+	if code := run([]string{"ratchet", "-f", file, "-keywords", "FIXME", "../../fixtures/03"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("unexpected exit code %d, stderr:\n%s", code, stderr.String())
+	}
+
+	if expected := "ratchet lowered from 6 to 2 findings in " + file + "\n"; stdout.String() != expected {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expected, stdout.String())
+	}
+
+	stdout.Reset()
+
+	if code := run([]string{"ratchet", "-f", file, "../../fixtures/03", "../../fixtures/00"}, &stdout, &stderr); code != exitFindings {
+		t.Fatalf("unexpected exit code %d, stderr:\n%s", code, stderr.String())
+	}
+
+	expected := "../../fixtures/00: 1 findings, at most 0 allowed\n../../fixtures/03: 6 findings, at most 2 allowed\n"
+	if stdout.String() != expected {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expected, stdout.String())
+	}
+
+	// the package without findings might not have been scanned unless pruned
+	for _, tt := range []struct {
+		args   []string
+		output string
+	}{
+		{args: []string{"-keywords", "BUG", "../../fixtures/03"}},
+		{args: []string{"-keywords", "BUG", "-prune", "../../fixtures/03"}, output: "ratchet lowered from 2 to 0 findings in " + file + "\n"},
+	} {
+		stdout.Reset()
+
+		if code := run(append([]string{"ratchet", "-f", file}, tt.args...), &stdout, &stderr); code != exitOK {
+			t.Fatalf("unexpected exit code %d, stderr:\n%s", code, stderr.String())
+		}
+
+		if stdout.String() != tt.output {
+			t.Errorf("%v: not equal\nexpected:\n%s\nactual:\n%s", tt.args, tt.output, stdout.String())
+		}
+	}
+}
+
+func TestDiffReport(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "godox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	before, after := filepath.Join(dir, "before.json"), filepath.Join(dir, "after.jsonl")
+
+	var stdout, stderr bytes.Buffer
+
+	if code := run([]string{"-format", "json", "-out", before, "-keywords", "FIXME", "../../fixtures/03"}, &stdout, &stderr); code != exitFindings {
+		t.Fatalf("unexpected exit code %d, stderr:\n%s", code, stderr.String())
+	}
+
+	if code := run([]string{"-format", "jsonl", "-out", after, "../../fixtures/03"}, &stdout, &stderr); code != exitFindings {
+		t.Fatalf("unexpected exit code %d, stderr:\n%s", code, stderr.String())
+	}
+
+	if code := run([]string{"diff-report", before, after}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("unexpected exit code %d, stderr:\n%s", code, stderr.String())
+	}
+
+	for _, line := range []string{
+		"**Debt went up by 4 findings**: 2 → 6 findings (4 added, 0 removed, 2 unchanged)",
+		"| FIXME | 2 | 2 | +0 |",
+		"| TODO | 0 | 4 | +4 |",
+		`| ../../fixtures/03/main.go:1 | Line contains TODO/BUG/FIXME: "TODO: Add package documentation" |`,
+	} {
+		if !strings.Contains(stdout.String(), line) {
+			t.Errorf("expected output to contain %q, got:\n%s", line, stdout.String())
+		}
+	}
+
+	stdout.Reset()
+
+	if code := run([]string{"diff-report", "-format", "json", after, before}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("unexpected exit code %d, stderr:\n%s", code, stderr.String())
+	}
+
+	var comparison struct {
+		Delta   int               `json:"delta"`
+		Removed []json.RawMessage `json:"removed"`
+	}
+
+	if err := json.Unmarshal(stdout.Bytes(), &comparison); err != nil {
+		t.Fatal(err)
+	}
+
+	if comparison.Delta != -4 || len(comparison.Removed) != 4 {
+		t.Errorf("unexpected comparison:\n%s", stdout.String())
+	}
+
+	if code := run([]string{"diff-report", before}, &stdout, &stderr); code != exitError {
+		t.Errorf("expected error with a single report, got %d", code)
+	}
+}
+
+func TestConfigShow(t *testing.T) {
+	t.Parallel()
+
+	var stdout, stderr bytes.Buffer
+
+	code := run([]string{"config", "show", "-config", "testdata/config.yml", "-tests=false", "main.go"}, &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("unexpected exit code %d, stderr:\n%s", code, stderr.String())
+	}
+
+	for _, line := range []string{"# main.go", "# loaded testdata/config.yml", "keywords:\n- TODO\n", "skip-generated: false", "tests: false"} {
+		if !strings.Contains(stdout.String(), line) {
+			t.Errorf("expected output to contain %q, got:\n%s", line, stdout.String())
+		}
+	}
+
+	if code := run([]string{"config", "show"}, &stdout, &stderr); code != exitError {
+		t.Errorf("expected error without files, got %d", code)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	var stdout, stderr bytes.Buffer
+
+	if code := run([]string{"config", "validate", "-config", "testdata/config.yml"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("unexpected exit code %d, stderr:\n%s", code, stderr.String())
+	}
+
+	for _, line := range []string{"# loaded testdata/config.yml", "keywords:\n- TODO\n", "deadline-layout: \"2006-01-02\""} {
+		if !strings.Contains(stdout.String(), line) {
+			t.Errorf("expected output to contain %q, got:\n%s", line, stdout.String())
+		}
+	}
+
+	stdout.Reset()
+
+	code := run([]string{"config", "validate", "-config", "testdata/config.yml", "-keywords", "TODO,todo,"}, &stdout, &stderr)
+	if code != exitError {
+		t.Errorf("expected error with duplicate keywords, got %d", code)
+	}
+
+	if expected := "godox: keyword 1: duplicate keyword todo\n"; stderr.String() != expected || stdout.Len() > 0 {
+		t.Errorf("not equal\nexpected: %q\nactual: %q", expected, stderr.String())
+	}
+}
+
+func TestConfigInit(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "godox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, preset := range []string{"relaxed", "strict", "google-style"} {
+		file := filepath.Join(dir, preset+".yml")
+
+		var stdout, stderr bytes.Buffer
+
+		if code := run([]string{"config", "init", "-preset", preset, "-out", file}, &stdout, &stderr); code != exitOK {
+			t.Fatalf("unexpected exit code %d for %s, stderr:\n%s", code, preset, stderr.String())
+		}
+
+		// the presets are valid configurations
+		if code := run([]string{"config", "validate", "-config", file}, &stdout, &stderr); code != exitOK {
+			t.Errorf("unexpected exit code %d validating %s, stderr:\n%s", code, preset, stderr.String())
+		}
+
+		if code := run([]string{"config", "init", "-preset", preset, "-out", file}, &stdout, &stderr); code != exitError {
+			t.Errorf("expected error overwriting %s, got %d", preset, code)
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	if code := run([]string{"config", "init", "-preset", "strict", "-out", "-"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("unexpected exit code %d, stderr:\n%s", code, stderr.String())
+	}
+
+	if !strings.Contains(stdout.String(), "require-issue-reference: true\n") {
+		t.Errorf("unexpected output:\n%s", stdout.String())
+	}
+
+	if code := run([]string{"config", "init", "-preset", "lax", "-out", "-"}, &stdout, &stderr); code != exitError {
+		t.Errorf("expected error for unknown preset, got %d", code)
+	}
+}
+
+func TestConfigSchema(t *testing.T) {
+	t.Parallel()
+
+	var stdout, stderr bytes.Buffer
+
+	if code := run([]string{"config", "schema"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("unexpected exit code %d, stderr:\n%s", code, stderr.String())
+	}
+
+	// the schema in the repository is kept in sync with the settings
+	expected, err := ioutil.ReadFile("../../godox.schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stdout.String() != string(expected) {
+		t.Errorf("godox.schema.json is out of date, regenerate it using: go run ./cmd/godox config schema > godox.schema.json")
+	}
+}
+
+func TestReport(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "godox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	output := filepath.Join(dir, "report.html")
+
+	var stdout, stderr bytes.Buffer
+
+	// reports are written even if there are findings
+	if code := run([]string{"report", "-o", output, "../../fixtures/03"}, &stdout, &stderr); code != exitOK {
+		t.Fatalf("unexpected exit code %d, stderr:\n%s", code, stderr.String())
+	}
+
+	data, err := ioutil.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(data), "<p>6 findings in 1 packages.</p>") {
+		t.Errorf("unexpected report:\n%s", data)
+	}
+
+	if code := run([]string{"report", "-format", "unknown", "../../fixtures/03"}, &stdout, &stderr); code != exitError {
+		t.Errorf("expected error for unknown format, got %d", code)
+	}
+}
+
+func TestOut(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "godox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	output := filepath.Join(dir, "findings.json")
+
+	var stdout, stderr bytes.Buffer
+
+	if code := run([]string{"-format", "json", "-out", output, "../../fixtures/00"}, &stdout, &stderr); code != exitFindings {
+		t.Fatalf("unexpected exit code %d, stderr:\n%s", code, stderr.String())
+	}
+
+	if stdout.Len() != 0 {
+		t.Errorf("unexpected output:\n%s", stdout.String())
+	}
+
+	data, err := ioutil.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(data), `"text": "TODO"`) {
+		t.Errorf("unexpected findings:\n%s", data)
+	}
+}
+
+func TestFix(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "godox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	input, err := ioutil.ReadFile("testdata/fix/main.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	golden, err := ioutil.ReadFile("testdata/fix/main.go.golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := filepath.Join(dir, "main.go")
+	if err := ioutil.WriteFile(src, input, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// the second run verifies the fixed comments are left as they are
+	for i := 0; i < 2; i++ {
+		var stdout, stderr bytes.Buffer
+
+		if code := run([]string{"-config", "testdata/fix/config.yml", "-fix", src}, &stdout, &stderr); code != exitFindings {
+			t.Fatalf("unexpected exit code %d, stderr:\n%s", code, stderr.String())
+		}
+
+		// only the comment which cannot be fixed is reported
+		expected := `main.go:13: Line does not match the expected format: ^FIXME\[\w+\] \w, "FIXME without owner"` + "\n"
+		if strings.Count(stdout.String(), "\n") != 1 || !strings.HasSuffix(stdout.String(), expected) {
+			t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expected, stdout.String())
+		}
+
+		data, err := ioutil.ReadFile(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(data) != string(golden) {
+			t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", golden, data)
+		}
+	}
+}
+
+func TestTriage(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "godox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	const src = `package main
+
+// TODO: suppress this
+// TODO: assign this
+// TODO(bob): reassign this
+// FIXME: snooze this 2020-01-01
+// TODO(dave)[snooze:2020-01-01]: snooze this again
+func main() {} // BUG: delete this
+// TODO: delete this line
+// TODO: skip this
+// TODO: quit here
+`
+
+	file := filepath.Join(dir, "main.go")
+	if err := ioutil.WriteFile(file, []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// the editor is called with the line and file name, which doesn't change anything
+	input := strings.Join([]string{
+		"x", "s", "", "legacy API",
+		"a", "alice",
+		"a", "carol",
+		"o", "z", "2020-01-01", "2999-01-01",
+		"z", "2999-02-01",
+		"d",
+		"d",
+		"n",
+		"q",
+	}, "\n")
+
+	var stdout, stderr bytes.Buffer
+
+	if code := runTriage([]string{"-editor", "true", file}, strings.NewReader(input), &stdout, &stderr); code != exitOK {
+		t.Fatalf("unexpected exit code %d, stderr:\n%s", code, stderr.String())
+	}
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `package main
+
+// TODO: suppress this //godox:ignore:TODO // legacy API
+// TODO(alice): assign this
+// TODO(carol): reassign this
+// FIXME[snooze:2999-01-01]: snooze this 2020-01-01
+// TODO(dave)[snooze:2999-02-01]: snooze this again
+func main() {}
+// TODO: skip this
+// TODO: quit here
+`
+	if string(data) != expected {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expected, data)
+	}
+
+	if !strings.Contains(stdout.String(), "[1/9] ") || !strings.Contains(stdout.String(), "  > 3 | // TODO: suppress this\n") {
+		t.Errorf("unexpected output:\n%s", stdout.String())
+	}
+
+	expectedErrors := "unknown action \"x\"\nthe justification is required\nthe date has to be in the future in the 2006-01-02 format\n"
+	if stderr.String() != expectedErrors {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expectedErrors, stderr.String())
+	}
+}
+
+func TestTriageMultiline(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "godox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	const src = `package main
+
+// TODO: suppress this
+//  continued here
+// FIXME: do not delete this
+//  continued here
+/*
+  BUG: do not suppress this
+*/
+func main() {}
+`
+
+	file := filepath.Join(dir, "main.go")
+	if err := ioutil.WriteFile(file, []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	input := strings.Join([]string{"s", "legacy API", "d", "n", "s", "n"}, "\n")
+
+	var stdout, stderr bytes.Buffer
+
+	if code := runTriage([]string{"-multiline", file}, strings.NewReader(input), &stdout, &stderr); code != exitOK {
+		t.Fatalf("unexpected exit code %d, stderr:\n%s", code, stderr.String())
+	}
+
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Replace(src, "// TODO: suppress this\n", "// TODO: suppress this //godox:ignore:TODO // legacy API\n", 1)
+	if string(data) != expected {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expected, data)
+	}
+
+	expectedErrors := "godox: cannot delete the comment continuing after line 5, open it instead\n" +
+		"godox: cannot suppress the finding in the block comment on line 8, open it instead\n"
+	if stderr.String() != expectedErrors {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expectedErrors, stderr.String())
+	}
+}
+
+func TestExportIssues(t *testing.T) {
+	t.Parallel()
+
+	var created []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var issue struct {
+			Title  string   `json:"title"`
+			Labels []string `json:"labels"`
+		}
+
+		if r.Method != http.MethodPost || r.URL.Path != "/repos/owner/repo/issues" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&issue); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		created = append(created, fmt.Sprintf("%s %v", issue.Title, issue.Labels))
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"number": %d}`, 455+len(created))
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "godox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cfg := filepath.Join(dir, ".godox.yml")
+	if err := ioutil.WriteFile(cfg, []byte("github:\n  repository: owner/repo\n  api-url: "+server.URL+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	args := []string{"-config", cfg, "-keywords", "FIXME", "-labels", "debt", "../../fixtures/01/example1.go"}
+
+	var stdout, stderr bytes.Buffer
+
+	if code := run(append([]string{"export", "issues", "-dry-run"}, args...), &stdout, &stderr); code != exitOK {
+		t.Fatalf("unexpected exit code %d, stderr:\n%s", code, stderr.String())
+	}
+
+	expected := `../../fixtures/01/example1.go:27: would create issue "Your attitude (Line 26)"
+    FIXME: Your attitude (Line 26)
+
+    Found in ../../fixtures/01/example1.go:27.
+`
+	if stdout.String() != expected || len(created) != 0 {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expected, stdout.String())
+	}
+
+	stdout.Reset()
+
+	if code := run(append([]string{"export", "issues"}, args...), &stdout, &stderr); code != exitOK {
+		t.Fatalf("unexpected exit code %d, stderr:\n%s", code, stderr.String())
+	}
+
+	if expected := "../../fixtures/01/example1.go:27: created issue #456\n"; stdout.String() != expected {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expected, stdout.String())
+	}
+
+	if fmt.Sprint(created) != "[Your attitude (Line 26) [debt]]" {
+		t.Errorf("unexpected created issues: %q", created)
+	}
+
+	if code := run([]string{"export", "issues", "-tracker", "redmine", "../../fixtures/01"}, &stdout, &stderr); code != exitError {
+		t.Errorf("expected error for unknown tracker, got %d", code)
+	}
+
+	// the references are written back to the comments, which are then skipped
+	src := filepath.Join(dir, "main.go")
+	if err := ioutil.WriteFile(src, []byte("package main\n\n// TODO(alice): refactor\nfunc main() {} /* FIXME: name */\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if code := run([]string{"export", "issues", "-config", cfg, "-fix", src}, &stdout, &stderr); code != exitOK {
+			t.Fatalf("unexpected exit code %d, stderr:\n%s", code, stderr.String())
+		}
+	}
+
+	data, err := ioutil.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := "package main\n\n// TODO(alice): refactor [#457]\nfunc main() {} /* FIXME: name [#458] */\n"; string(data) != expected {
+		t.Errorf("not equal\nexpected: %q\nactual: %q", expected, data)
+	}
+
+	if len(created) != 3 {
+		t.Errorf("unexpected created issues: %q", created)
+	}
+}
+
+func TestSplitAliases(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value    string
+		expected map[string]string
+	}{
+		{value: ""},
+		{value: "LATER=TODO", expected: map[string]string{"LATER": "TODO"}},
+		{value: "builtin", expected: map[string]string{"XXX": "FIXME", "HACK": "FIXME", "NOTE": "TODO", "OPTIMIZE": "TODO", "WIP": "TODO"}},
+		{
+			value:    "NOTE=BUG,builtin,LATER=TODO",
+			expected: map[string]string{"XXX": "FIXME", "HACK": "FIXME", "NOTE": "BUG", "OPTIMIZE": "TODO", "WIP": "TODO", "LATER": "TODO"},
+		},
+	}
+
+	for _, tt := range tests {
+		actual, err := splitAliases(tt.value)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(actual, tt.expected) {
+			t.Errorf("%s: not equal\nexpected: %v\nactual: %v", tt.value, tt.expected, actual)
+		}
+	}
+}
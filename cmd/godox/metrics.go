@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/matoous/godox"
+)
+
+// scanBuckets are the upper bounds of the scan duration histogram buckets in seconds.
+var scanBuckets = []float64{0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30, 60}
+
+// findingLabels are the labels of the findings gauge.
+type findingLabels struct {
+	keyword  string
+	pkg      string
+	severity godox.Severity
+}
+
+// metrics of the last scan and the durations of all scans, served in the Prometheus text format.
+type metrics struct {
+	mu       sync.Mutex
+	findings map[findingLabels]int
+	// buckets are the numbers of scans by the scanBuckets, not cumulative
+	buckets []int
+	count   int
+	sum     float64
+}
+
+func newMetrics() *metrics {
+	return &metrics{findings: make(map[findingLabels]int), buckets: make([]int, len(scanBuckets))}
+}
+
+// observe records the findings of a scan, which are not suppressed, and its duration.
+func (m *metrics) observe(messages []godox.Message, d time.Duration) {
+	findings := make(map[findingLabels]int)
+
+	for _, msg := range messages {
+		if msg.Suppressed {
+			continue
+		}
+
+		keyword := msg.Canonical
+		if keyword == "" {
+			keyword = msg.Keyword
+		}
+
+		pkg := path.Dir(filepath.ToSlash(filepath.Clean(msg.Pos.Filename)))
+		findings[findingLabels{keyword: keyword, pkg: pkg, severity: msg.Severity}]++
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.findings = findings
+	m.count++
+	m.sum += d.Seconds()
+
+	for i, le := range scanBuckets {
+		if d.Seconds() <= le {
+			m.buckets[i]++
+			break
+		}
+	}
+}
+
+func (m *metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !allow(w, r, http.MethodGet) {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	labels := make([]findingLabels, 0, len(m.findings))
+	for l := range m.findings {
+		labels = append(labels, l)
+	}
+
+	sort.Slice(labels, func(i, j int) bool {
+		a, b := labels[i], labels[j]
+		if a.keyword != b.keyword {
+			return a.keyword < b.keyword
+		}
+
+		if a.pkg != b.pkg {
+			return a.pkg < b.pkg
+		}
+
+		return a.severity < b.severity
+	})
+
+	var b strings.Builder
+
+	b.WriteString("# HELP godox_findings_total Number of findings of the last scan.\n")
+	b.WriteString("# TYPE godox_findings_total gauge\n")
+
+	for _, l := range labels {
+		fmt.Fprintf(&b, "godox_findings_total{keyword=%s,package=%s,severity=%s} %d\n",
+			labelValue(l.keyword), labelValue(l.pkg), labelValue(string(l.severity)), m.findings[l])
+	}
+
+	b.WriteString("# HELP godox_scan_duration_seconds Duration of the scans.\n")
+	b.WriteString("# TYPE godox_scan_duration_seconds histogram\n")
+
+	cumulative := 0
+	for i, le := range scanBuckets {
+		cumulative += m.buckets[i]
+		fmt.Fprintf(&b, "godox_scan_duration_seconds_bucket{le=\"%s\"} %d\n", strconv.FormatFloat(le, 'g', -1, 64), cumulative)
+	}
+
+	fmt.Fprintf(&b, "godox_scan_duration_seconds_bucket{le=\"+Inf\"} %d\n", m.count)
+	fmt.Fprintf(&b, "godox_scan_duration_seconds_sum %s\n", strconv.FormatFloat(m.sum, 'g', -1, 64))
+	fmt.Fprintf(&b, "godox_scan_duration_seconds_count %d\n", m.count)
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	_, _ = w.Write([]byte(b.String()))
+}
+
+// labelValue returns the quoted label value with backslashes, double quotes and line breaks escaped.
+func labelValue(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/matoous/godox"
+)
+
+// exitPolicy decides the exit code of the findings. Without limits any finding of the severity fails,
+// with limits or budgets the findings fail only if there are more of them than allowed.
+type exitPolicy struct {
+	failOn godox.Severity
+	// maxIssues is the number of findings allowed, negative if unlimited.
+	maxIssues int
+	// maxPerKeyword are the numbers of findings allowed by upper cased canonical keywords.
+	maxPerKeyword map[string]int
+	// budgeted is set if the settings have budgets of the paths.
+	budgeted bool
+	exitZero bool
+}
+
+// verdict is the result of the policy.
+type verdict struct {
+	Passed     bool     `json:"passed"`
+	Violations []string `json:"violations,omitempty"`
+}
+
+// newExitPolicy returns the policy of the flags, the limits per keyword are KEYWORD=N pairs.
+func newExitPolicy(failOn string, maxIssues int, maxPerKeyword string, exitZero bool) (exitPolicy, error) {
+	pairs, err := splitPairs(maxPerKeyword, "keyword limit", "KEYWORD=N")
+	if err != nil {
+		return exitPolicy{}, err
+	}
+
+	p := exitPolicy{failOn: godox.Severity(failOn), maxIssues: maxIssues, exitZero: exitZero}
+
+	for kw, limit := range pairs {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			return exitPolicy{}, fmt.Errorf("invalid limit %q of %s", limit, kw)
+		}
+
+		if p.maxPerKeyword == nil {
+			p.maxPerKeyword = make(map[string]int, len(pairs))
+		}
+
+		p.maxPerKeyword[strings.ToUpper(kw)] = n
+	}
+
+	return p, nil
+}
+
+// limited reports whether the policy has any limits or budgets.
+func (p exitPolicy) limited() bool {
+	return p.maxIssues >= 0 || len(p.maxPerKeyword) > 0 || p.budgeted
+}
+
+// check returns the verdict of the findings which are not suppressed and have at least the severity,
+// and of the exceeded budgets.
+func (p exitPolicy) check(messages []godox.Message, exceeded []*godox.BudgetError) verdict {
+	var (
+		total    int
+		keywords = make(map[string]int)
+	)
+
+	for _, m := range messages {
+		if m.Suppressed || !m.Severity.AtLeast(p.failOn) {
+			continue
+		}
+
+		keyword := m.Canonical
+		if keyword == "" {
+			keyword = m.Keyword
+		}
+
+		total++
+		keywords[strings.ToUpper(keyword)]++
+	}
+
+	if !p.limited() {
+		return verdict{Passed: total == 0}
+	}
+
+	var violations []string
+
+	if p.maxIssues >= 0 && total > p.maxIssues {
+		violations = append(violations, fmt.Sprintf("%d findings, at most %d allowed", total, p.maxIssues))
+	}
+
+	names := make([]string, 0, len(p.maxPerKeyword))
+	for kw := range p.maxPerKeyword {
+		names = append(names, kw)
+	}
+
+	sort.Strings(names)
+
+	for _, kw := range names {
+		if count, limit := keywords[kw], p.maxPerKeyword[kw]; count > limit {
+			violations = append(violations, fmt.Sprintf("%d %s findings, at most %d allowed", count, kw, limit))
+		}
+	}
+
+	for _, e := range exceeded {
+		violations = append(violations, e.Error())
+	}
+
+	return verdict{Passed: len(violations) == 0, Violations: violations}
+}
+
+// exitCode returns the exit code of the verdict.
+func (p exitPolicy) exitCode(v verdict) int {
+	if v.Passed || p.exitZero {
+		return exitOK
+	}
+
+	return exitFindings
+}
+
+// writeVerdict writes the verdict following the text summary.
+func writeVerdict(w io.Writer, v verdict) {
+	result := "passed"
+	if !v.Passed {
+		result = "failed"
+	}
+
+	fmt.Fprintf(w, "\nPolicy: %s\n", result)
+
+	for _, violation := range v.Violations {
+		fmt.Fprintf(w, "  %s\n", violation)
+	}
+}
+
+// summaryJSON writes the statistics with the verdict as JSON.
+func summaryJSON(w io.Writer, stats godox.Statistics, v *verdict) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(struct {
+		godox.Statistics
+		Verdict *verdict `json:"verdict,omitempty"`
+	}{stats, v})
+}
@@ -0,0 +1,105 @@
+package main
+
+// configPresets are the starter configuration files written by godox config init.
+var configPresets = map[string]string{
+	"relaxed": `# godox configuration, see https://github.com/matoous/godox
+#
+# The relaxed preset reports the keyword comments as warnings so they stay visible,
+# without requiring any format.
+
+# keywords reported as usual, with their aliases reported as the keywords they map to
+keywords: [TODO, BUG, FIXME]
+aliases:
+  HACK: FIXME
+  XXX: FIXME
+
+# severities of the findings, warning by default: error, warning or info
+severities:
+  BUG: warning
+  FIXME: warning
+  TODO: info
+
+# the deadlines, e.g. TODO(2025-06-01), are reported as errors once they pass
+deadline-mode: escalate
+
+skip-generated: true
+exclude-paths: [vendor/**, "**/testdata/**"]
+
+# format rules are checked for the comments with their keywords in the combined mode, uncomment to start
+# enforcing the owners:
+#
+# combined: true
+# format-rules:
+#   - keyword: TODO
+#     regular-expression: '^TODO\(\w+\): '
+`,
+	"strict": `# godox configuration, see https://github.com/matoous/godox
+#
+# The strict preset requires every comment to have an owner and to reference an issue,
+# e.g. TODO(alice): drop the legacy client #123, and reports the violations as errors.
+
+keywords: [TODO, BUG, FIXME]
+
+# XXX is reported as an error wherever it occurs
+forbidden-keywords: [XXX]
+
+severities:
+  BUG: error
+  FIXME: error
+  TODO: error
+
+# the comments of the keywords of the format rules are validated by the rules, the other keywords as usual
+combined: true
+format-rules:
+  # the owner in parenthesis and the issue reference, e.g. #123, PROJ-123 or an URL
+  - id: owner-and-issue
+    keyword: '*'
+    regular-expression: '^\w+\(\w+\): .*(#\d+|\b[A-Z][A-Z0-9]+-\d+\b|https?://)'
+    forbid: '(?i)\b(temporary|temp fix)\b'
+  # FIXMEs are tolerated only for bugs tracked in Jira
+  - id: fixme-jira
+    keyword: FIXME
+    regular-expression: '^FIXME\(\w+\): .*\b[A-Z][A-Z0-9]+-\d+\b'
+
+# the comments with valid format are reported if they break the policies
+require-owner: true
+require-issue-reference: true
+min-description-length:
+  TODO: 10
+  FIXME: 10
+
+# the deadlines, e.g. TODO(alice, 2025-06-01): ..., are reported once they pass
+deadline-mode: escalate
+
+skip-generated: true
+tests: true
+exclude-paths: [vendor/**, "**/testdata/**"]
+`,
+	"google-style": `# godox configuration, see https://github.com/matoous/godox
+#
+# The google-style preset follows the Google style guides, the TODOs name the person or the bug
+# which has the best context about the problem, e.g. TODO(alice): ... or TODO(b/123): ...
+
+keywords: [TODO]
+
+# other keywords, e.g. FIXME or XXX, are reported to be rewritten as TODOs
+forbidden-keywords: [FIXME, XXX, HACK, BUG]
+
+format: true
+format-rules:
+  - id: google-todo
+    keyword: TODO
+    regular-expression: '^TODO\((\w+|b/\d+)\): \S'
+    # TODO: text, todo(alice) text and similar are rewritten by godox -fix to TODO(owner): text,
+    # the comments without owners are left to be fixed by hand
+    fix-pattern: '(?i)^todo\s*\(\s*([\w/]+)\s*\)\W*(.+)$'
+    fix: 'TODO($1): $2'
+
+# the owner is a user name or a bug, e.g. b/123
+owner-pattern: '^\(([\w/]+)\)'
+require-owner: true
+
+skip-generated: true
+exclude-paths: [vendor/**, "**/testdata/**", third_party/**]
+`,
+}
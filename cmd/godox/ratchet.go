@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+
+	"github.com/matoous/godox/ratchet"
+)
+
+// runRatchet fails when any package has more findings than recorded in the ratchet file, lowering the recorded
+// counts of the packages with fewer findings. The counts of the packages without findings, which might not have been
+// scanned, are kept unless pruned. The file is created with the current counts if it doesn't exist.
+func runRatchet(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("godox ratchet", "[flags] [packages]", stderr)
+
+	var lf lintFlags
+	lf.register(flags)
+
+	file := flags.String("f", ratchet.DefaultFile, "ratchet file with the counts of findings per package")
+	prune := flags.Bool("prune", false, "drop the recorded packages without findings, use only when scanning all the packages")
+
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	ctx, cancel := lf.context()
+	defer cancel()
+
+	messages, err := lf.lint(ctx, flags.Args())
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	recorded, err := ratchet.Load(*file)
+	if os.IsNotExist(err) {
+		r := ratchet.New(messages)
+		if err := r.Save(*file); err != nil {
+			return fail(stderr, err)
+		}
+
+		fmt.Fprintf(stdout, "ratchet with %d findings written to %s\n", r.Total(), *file)
+
+		return exitOK
+	}
+
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	lowered, increases := recorded.Check(messages, *prune)
+
+	if !reflect.DeepEqual(lowered.Packages, recorded.Packages) {
+		if err := lowered.Save(*file); err != nil {
+			return fail(stderr, err)
+		}
+
+		fmt.Fprintf(stdout, "ratchet lowered from %d to %d findings in %s\n", recorded.Total(), lowered.Total(), *file)
+	}
+
+	for _, i := range increases {
+		fmt.Fprintln(stdout, i)
+	}
+
+	if len(increases) > 0 {
+		return exitFindings
+	}
+
+	return exitOK
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/matoous/godox/report"
+)
+
+// runReport writes report of the findings, unlike the linter it doesn't fail when there are findings.
+func runReport(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("godox report", "[flags] [packages]", stderr)
+
+	var lf lintFlags
+	lf.register(flags)
+
+	format := flags.String("format", "html", "report format, one of: "+strings.Join(report.Formats(), ", "))
+	output := flags.String("o", "", "file to write the report to (default standard output)")
+	sourceURL := flags.String("source-url", "", "template of links to source lines, e.g. https://github.com/owner/repo/blob/main/{path}#L{line}")
+	top := flags.Int("top", report.DefaultTop, "number of files and packages listed by the top format")
+	columns := flags.String("columns", "", "comma separated list of columns of the csv and tsv formats (default "+strings.Join(report.DefaultCSVColumns, ",")+")")
+
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	reporter, err := report.NewWithOptions(*format, report.Options{SourceURL: *sourceURL, Columns: splitList(*columns), Top: *top})
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	ctx, cancel := lf.context()
+	defer cancel()
+
+	messages, err := lf.lint(ctx, flags.Args())
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	if *output == "" {
+		if err := reporter.Report(stdout, messages); err != nil {
+			return fail(stderr, err)
+		}
+
+		return exitOK
+	}
+
+	if fr, ok := reporter.(report.FileReporter); ok {
+		if err := fr.ReportFile(*output, messages); err != nil {
+			return fail(stderr, err)
+		}
+
+		return exitOK
+	}
+
+	f, err := os.Create(*output)
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	if err := reporter.Report(f, messages); err != nil {
+		f.Close()
+		return fail(stderr, err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fail(stderr, err)
+	}
+
+	return exitOK
+}
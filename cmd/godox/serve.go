@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/matoous/godox"
+	"github.com/matoous/godox/internal/glob"
+	"github.com/matoous/godox/report"
+)
+
+// serveShutdownTimeout is the time the requests in progress have to finish when the daemon stops.
+const serveShutdownTimeout = 5 * time.Second
+
+// runServe scans the packages and serves the findings over HTTP until interrupted,
+// the packages are scanned again on request.
+func runServe(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("godox serve", "[flags] [packages]", stderr)
+
+	var lf lintFlags
+	lf.register(flags)
+
+	addr := flags.String("addr", "localhost:8080", "address to listen on")
+
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	ctx, cancel := interruptContext()
+	defer cancel()
+
+	patterns := flags.Args()
+	d := &daemon{metrics: newMetrics(), timeout: lf.timeout, scan: func(ctx context.Context) ([]godox.Message, error) {
+		return lf.lint(ctx, patterns)
+	}}
+
+	if err := d.rescan(ctx); err != nil {
+		return fail(stderr, err)
+	}
+
+	l, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	fmt.Fprintf(stdout, "%d findings, serving on http://%s\n", len(d.messages), l.Addr())
+
+	if err := serveHTTP(ctx, l, d); err != nil {
+		return fail(stderr, err)
+	}
+
+	return exitOK
+}
+
+// serveHTTP serves the requests until the context is canceled.
+func serveHTTP(ctx context.Context, l net.Listener, h http.Handler) error {
+	srv := &http.Server{Handler: h, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+
+		shutdown, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+		defer cancel()
+
+		_ = srv.Shutdown(shutdown)
+	}()
+
+	if err := srv.Serve(l); err != nil && err != http.ErrServerClosed {
+		return err
+	}
+
+	return nil
+}
+
+// daemon keeps the findings of the last scan and serves them:
+//
+//	GET  /findings  findings, filtered by the path glob pattern and keyword query parameters
+//	GET  /stats     counts of the findings, see godox.Stats
+//	POST /scan      scans the packages again and returns the counts of the new findings
+//	GET  /metrics   metrics in the Prometheus text format
+//
+// Thanks to the cache only the changed files are parsed again when scanning.
+type daemon struct {
+	scan    func(ctx context.Context) ([]godox.Message, error)
+	metrics *metrics
+	// timeout limits each scan if set
+	timeout time.Duration
+
+	// scanning serializes the scans, mu guards the messages
+	scanning sync.Mutex
+	mu       sync.RWMutex
+	messages []godox.Message
+}
+
+func (d *daemon) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/findings":
+		if !allow(w, r, http.MethodGet) {
+			return
+		}
+
+		messages, err := d.findings(r.URL.Query().Get("path"), r.URL.Query().Get("keyword"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = report.JSON(w, messages)
+	case "/stats":
+		if !allow(w, r, http.MethodGet) {
+			return
+		}
+
+		d.mu.RLock()
+		stats := godox.Stats(d.messages)
+		d.mu.RUnlock()
+
+		writeJSON(w, stats)
+	case "/scan":
+		if !allow(w, r, http.MethodPost) {
+			return
+		}
+
+		if err := d.rescan(r.Context()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		d.mu.RLock()
+		stats := godox.Stats(d.messages)
+		d.mu.RUnlock()
+
+		writeJSON(w, stats)
+	case "/metrics":
+		d.metrics.ServeHTTP(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// rescan replaces the findings with the findings of a new scan, the findings are kept if the scan fails.
+func (d *daemon) rescan(ctx context.Context) error {
+	d.scanning.Lock()
+	defer d.scanning.Unlock()
+
+	if d.timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, d.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+
+	messages, err := d.scan(ctx)
+	if err != nil {
+		return err
+	}
+
+	d.metrics.observe(messages, time.Since(start))
+
+	d.mu.Lock()
+	d.messages = messages
+	d.mu.Unlock()
+
+	return nil
+}
+
+// findings returns the findings of the files matching the glob pattern with the keyword or its alias,
+// empty pattern and keyword match all findings.
+func (d *daemon) findings(pattern, keyword string) ([]godox.Message, error) {
+	var p *glob.Pattern
+
+	if pattern != "" {
+		var err error
+		if p, err = glob.Compile(pattern); err != nil {
+			return nil, err
+		}
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var filtered []godox.Message
+
+	for _, m := range d.messages {
+		if p != nil && !p.Match(filepath.ToSlash(filepath.Clean(m.Pos.Filename))) {
+			continue
+		}
+
+		if keyword != "" && !strings.EqualFold(m.Keyword, keyword) && !strings.EqualFold(m.Canonical, keyword) {
+			continue
+		}
+
+		filtered = append(filtered, m)
+	}
+
+	return filtered, nil
+}
+
+// allow responds with 405 Method Not Allowed to requests with other methods.
+func allow(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+
+	w.Header().Set("Allow", method)
+	http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matoous/godox"
+	"github.com/matoous/godox/report"
+)
+
+func TestServe(t *testing.T) {
+	t.Parallel()
+
+	scans := [][]godox.Message{
+		{
+			{Pos: token.Position{Filename: "pkg/a.go"}, Keyword: "TODO", Canonical: "TODO", Text: "TODO: a"},
+			{Pos: token.Position{Filename: "pkg/b.go"}, Keyword: "HACK", Canonical: "FIXME", Text: "HACK: b"},
+			{Pos: token.Position{Filename: "main.go"}, Keyword: "TODO", Canonical: "TODO", Text: "TODO: main"},
+		},
+		{
+			{Pos: token.Position{Filename: "main.go"}, Keyword: "TODO", Canonical: "TODO", Text: "TODO: main"},
+		},
+	}
+
+	d := &daemon{metrics: newMetrics(), scan: func(context.Context) ([]godox.Message, error) {
+		messages := scans[0]
+		scans = scans[1:]
+
+		return messages, nil
+	}}
+
+	if err := d.rescan(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		method string
+		target string
+		code   int
+		texts  []string
+		total  int
+	}{
+		{method: http.MethodGet, target: "/findings", code: http.StatusOK, texts: []string{"TODO: a", "HACK: b", "TODO: main"}},
+		{method: http.MethodGet, target: "/findings?path=pkg/**&keyword=fixme", code: http.StatusOK, texts: []string{"HACK: b"}},
+		{method: http.MethodGet, target: "/findings?keyword=BUG", code: http.StatusOK, texts: []string{}},
+		{method: http.MethodGet, target: "/findings?path=[a", code: http.StatusBadRequest},
+		{method: http.MethodGet, target: "/stats", code: http.StatusOK, total: 3},
+		{method: http.MethodGet, target: "/scan", code: http.StatusMethodNotAllowed},
+		{method: http.MethodPost, target: "/scan", code: http.StatusOK, total: 1},
+		{method: http.MethodGet, target: "/findings", code: http.StatusOK, texts: []string{"TODO: main"}},
+		{method: http.MethodGet, target: "/other", code: http.StatusNotFound},
+	}
+
+	// the requests depend on the scans made by the previous requests
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		d.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, nil))
+
+		if rec.Code != tt.code {
+			t.Fatalf("%s %s: unexpected status %d: %s", tt.method, tt.target, rec.Code, rec.Body.String())
+		}
+
+		switch {
+		case tt.code != http.StatusOK:
+		case tt.texts != nil:
+			var r report.JSONReport
+			if err := json.NewDecoder(rec.Body).Decode(&r); err != nil {
+				t.Fatal(err)
+			}
+
+			texts := []string{}
+			for _, m := range r.Findings {
+				texts = append(texts, m.Text)
+			}
+
+			if !reflect.DeepEqual(texts, tt.texts) {
+				t.Errorf("%s: not equal\nexpected: %q\nactual: %q", tt.target, tt.texts, texts)
+			}
+		default:
+			var stats godox.Statistics
+			if err := json.NewDecoder(rec.Body).Decode(&stats); err != nil {
+				t.Fatal(err)
+			}
+
+			if stats.Total != tt.total {
+				t.Errorf("%s: not equal\nexpected: %d\nactual: %d", tt.target, tt.total, stats.Total)
+			}
+		}
+	}
+}
+
+func TestServeTimeout(t *testing.T) {
+	t.Parallel()
+
+	d := &daemon{metrics: newMetrics(), timeout: time.Millisecond, scan: func(ctx context.Context) ([]godox.Message, error) {
+		<-ctx.Done()
+
+		return nil, ctx.Err()
+	}}
+
+	// the timeout limits the scans, not the daemon
+	for i := 0; i < 2; i++ {
+		if err := d.rescan(context.Background()); err != context.DeadlineExceeded {
+			t.Fatalf("not equal\nexpected: %v\nactual: %v", context.DeadlineExceeded, err)
+		}
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	m := newMetrics()
+	m.observe([]godox.Message{
+		{Pos: token.Position{Filename: "pkg/a.go"}, Keyword: "TODO", Canonical: "TODO", Severity: godox.SeverityWarning},
+		{Pos: token.Position{Filename: "pkg/b.go"}, Keyword: "TODO", Canonical: "TODO", Severity: godox.SeverityWarning},
+		{Pos: token.Position{Filename: `pkg/"q"/a.go`}, Keyword: "HACK", Canonical: "FIXME", Severity: godox.SeverityError},
+		{Pos: token.Position{Filename: "main.go"}, Keyword: "TODO", Canonical: "TODO", Suppressed: true},
+	}, 30*time.Millisecond)
+	m.observe([]godox.Message{
+		{Pos: token.Position{Filename: "pkg/a.go"}, Keyword: "TODO", Canonical: "TODO", Severity: godox.SeverityWarning},
+	}, 2*time.Second)
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	expected := `# HELP godox_findings_total Number of findings of the last scan.
+# TYPE godox_findings_total gauge
+godox_findings_total{keyword="TODO",package="pkg",severity="warning"} 1
+# HELP godox_scan_duration_seconds Duration of the scans.
+# TYPE godox_scan_duration_seconds histogram
+godox_scan_duration_seconds_bucket{le="0.01"} 0
+godox_scan_duration_seconds_bucket{le="0.05"} 1
+godox_scan_duration_seconds_bucket{le="0.1"} 1
+godox_scan_duration_seconds_bucket{le="0.5"} 1
+godox_scan_duration_seconds_bucket{le="1"} 1
+godox_scan_duration_seconds_bucket{le="5"} 2
+godox_scan_duration_seconds_bucket{le="10"} 2
+godox_scan_duration_seconds_bucket{le="30"} 2
+godox_scan_duration_seconds_bucket{le="60"} 2
+godox_scan_duration_seconds_bucket{le="+Inf"} 2
+godox_scan_duration_seconds_sum 2.03
+godox_scan_duration_seconds_count 2
+`
+
+	if actual := rec.Body.String(); actual != expected {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expected, actual)
+	}
+
+	// label values are escaped
+	m.observe([]godox.Message{
+		{Pos: token.Position{Filename: `pkg/"q"/a.go`}, Keyword: "HACK", Canonical: "FIXME", Severity: godox.SeverityError},
+	}, time.Second)
+
+	rec = httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if line := `godox_findings_total{keyword="FIXME",package="pkg/\"q\"",severity="error"} 1`; !strings.Contains(rec.Body.String(), line) {
+		t.Errorf("missing %s in:\n%s", line, rec.Body.String())
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"io"
+	"time"
+
+	"github.com/matoous/godox/report"
+)
+
+// runSnoozed writes the findings snoozed until a future date, unlike the linter it doesn't fail
+// when there are findings.
+func runSnoozed(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("godox snoozed", "[flags] [packages]", stderr)
+
+	var lf lintFlags
+	lf.register(flags)
+
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	// the snoozed findings are suppressed
+	lf.suppressed = true
+
+	ctx, cancel := lf.context()
+	defer cancel()
+
+	messages, err := lf.lint(ctx, flags.Args())
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	if err := report.Snoozed(stdout, messages, time.Now()); err != nil {
+		return fail(stderr, err)
+	}
+
+	return exitOK
+}
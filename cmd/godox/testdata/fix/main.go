@@ -0,0 +1,14 @@
+package main
+
+// todo fix this
+//TODO add tests
+// TODO(alice) - handle errors
+
+/*
+	Todo: block comment
+	TODO: already fine
+*/
+
+// FIXME(bob) - rename
+// FIXME without owner
+func main() {} // todo - trailing
@@ -0,0 +1,479 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/matoous/godox"
+	"github.com/matoous/godox/config"
+	"github.com/matoous/godox/internal/edit"
+)
+
+const triagePrompt = "[o]pen, [s]uppress, [a]ssign, snoo[z]e, [d]elete, [n]ext, [q]uit? "
+
+// triageContext is the number of source lines shown around the findings.
+const triageContext = 2
+
+// triage walks the findings interactively, writing the edits back to the source files right away.
+type triage struct {
+	in       *bufio.Scanner
+	stdout   io.Writer
+	stderr   io.Writer
+	editor   string
+	settings *config.CompiledSettings
+}
+
+// runTriage asks what to do with each of the findings, one by one.
+func runTriage(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := newFlagSet("godox triage", "[flags] [packages]", stderr)
+
+	var lf lintFlags
+	lf.register(flags)
+
+	editor := flags.String("editor", os.Getenv("EDITOR"), "command opening files at a line, called with +LINE FILE (default $EDITOR)")
+
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	settings, err := lf.settings()
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	compiled, err := settings.Compile()
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	ctx, cancel := lf.context()
+	defer cancel()
+
+	messages, err := lf.lint(ctx, flags.Args())
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	t := &triage{in: bufio.NewScanner(stdin), stdout: stdout, stderr: stderr, editor: *editor, settings: compiled}
+
+	findings := triaged(messages)
+	for i, m := range findings {
+		fmt.Fprintf(stdout, "[%d/%d] %s\n", i+1, len(findings), m.Message)
+
+		if !t.handle(m) {
+			break
+		}
+	}
+
+	return exitOK
+}
+
+// triaged returns the messages which are not suppressed, one per comment line.
+func triaged(messages []godox.Message) []godox.Message {
+	var (
+		filtered []godox.Message
+		seen     = make(map[string]struct{})
+	)
+
+	for _, m := range messages {
+		if m.Suppressed {
+			continue
+		}
+
+		key := fmt.Sprintf("%s:%d", m.Pos.Filename, m.Line)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+
+		seen[key] = struct{}{}
+		filtered = append(filtered, m)
+	}
+
+	return filtered
+}
+
+// handle asks for the actions on the finding until it is resolved or skipped,
+// it returns false once the user quits or the input ends.
+func (t *triage) handle(m godox.Message) bool {
+	t.show(m)
+
+	for {
+		action, ok := t.ask(triagePrompt)
+		if !ok {
+			return false
+		}
+
+		var err error
+
+		switch strings.ToLower(action) {
+		case "o", "open":
+			if err := t.open(m); err != nil {
+				fmt.Fprintf(t.stderr, "godox: %v\n", err)
+			}
+
+			t.show(m)
+
+			continue
+		case "s", "suppress":
+			err = t.edit(m, t.suppress)
+		case "a", "assign":
+			err = t.edit(m, t.assign)
+		case "z", "snooze":
+			err = t.edit(m, t.snooze)
+		case "d", "delete":
+			err = t.edit(m, deleteComment)
+		case "n", "next", "":
+			return true
+		case "q", "quit":
+			return false
+		default:
+			fmt.Fprintf(t.stderr, "unknown action %q\n", action)
+			continue
+		}
+
+		switch {
+		case err == errCanceled:
+			return false
+		case err != nil:
+			fmt.Fprintf(t.stderr, "godox: %v\n", err)
+		default:
+			return true
+		}
+	}
+}
+
+// ask prints the prompt and reads the answer, false is returned at the end of the input.
+func (t *triage) ask(prompt string) (string, bool) {
+	fmt.Fprint(t.stdout, prompt)
+
+	if !t.in.Scan() {
+		fmt.Fprintln(t.stdout)
+		return "", false
+	}
+
+	return strings.TrimSpace(t.in.Text()), true
+}
+
+// show prints the comment line of the finding with the surrounding source lines.
+func (t *triage) show(m godox.Message) {
+	content, err := ioutil.ReadFile(m.Pos.Filename)
+	if err != nil {
+		fmt.Fprintf(t.stderr, "godox: %v\n", err)
+		return
+	}
+
+	line := m.Line
+	if l, err := locate(content, m); err == nil {
+		line = l.number
+	}
+
+	lines := strings.Split(string(content), "\n")
+	width := len(strconv.Itoa(line + triageContext))
+
+	for n := line - triageContext; n <= line+triageContext; n++ {
+		if n < 1 || n > len(lines) {
+			continue
+		}
+
+		marker := " "
+		if n == line {
+			marker = ">"
+		}
+
+		fmt.Fprintf(t.stdout, "  %s %*d | %s\n", marker, width, n, lines[n-1])
+	}
+}
+
+// open opens the file of the finding in the editor and waits for it to exit.
+func (t *triage) open(m godox.Message) error {
+	args := strings.Fields(t.editor)
+	if len(args) == 0 {
+		return errors.New("no editor set, use -editor or $EDITOR")
+	}
+
+	content, err := ioutil.ReadFile(m.Pos.Filename)
+	if err != nil {
+		return err
+	}
+
+	line := m.Line
+	if l, err := locate(content, m); err == nil {
+		line = l.number
+	}
+
+	cmd := exec.Command(args[0], append(args[1:], "+"+strconv.Itoa(line), m.Pos.Filename)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = t.stdout
+	cmd.Stderr = t.stderr
+
+	return cmd.Run()
+}
+
+// errCanceled is returned by the edits when the input ends while asking for the details.
+var errCanceled = errors.New("canceled")
+
+// edit applies the edit of the comment line of the finding to its file.
+func (t *triage) edit(m godox.Message, change func([]byte, godox.Message, sourceLine) (edit.Edit, error)) error {
+	content, err := ioutil.ReadFile(m.Pos.Filename)
+	if err != nil {
+		return err
+	}
+
+	line, err := locate(content, m)
+	if err != nil {
+		return err
+	}
+
+	e, err := change(content, m, line)
+	if err != nil {
+		return err
+	}
+
+	return edit.ApplyFile(m.Pos.Filename, []edit.Edit{e})
+}
+
+// suppress appends the godox:ignore directive with the justification to the comment line,
+// which cannot be done within a block comment continuing on the next line.
+func (t *triage) suppress(content []byte, m godox.Message, line sourceLine) (edit.Edit, error) {
+	if inBlockComment(content, line) {
+		return edit.Edit{}, fmt.Errorf("cannot suppress the finding in the block comment on line %d, open it instead", line.number)
+	}
+
+	for {
+		reason, ok := t.ask("justification: ")
+		switch {
+		case !ok:
+			return edit.Edit{}, errCanceled
+		case reason == "":
+			fmt.Fprintln(t.stderr, "the justification is required")
+		case strings.Contains(reason, "*/"):
+			fmt.Fprintln(t.stderr, "the justification cannot contain */")
+		default:
+			keyword := m.Canonical
+			if keyword == "" {
+				keyword = m.Keyword
+			}
+
+			return edit.AppendToLine(content, line.number, " //godox:ignore:"+keyword+" // "+reason)
+		}
+	}
+}
+
+// assign sets the owner in parenthesis after the keyword, replacing the current owner.
+func (t *triage) assign(content []byte, m godox.Message, line sourceLine) (edit.Edit, error) {
+	for {
+		owner, ok := t.ask("owner: ")
+		switch {
+		case !ok:
+			return edit.Edit{}, errCanceled
+		case owner == "" || strings.ContainsAny(owner, "() \t"):
+			fmt.Fprintln(t.stderr, "the owner has to be a single word")
+		case !bytes.HasPrefix(bytes.ToUpper(content[line.text:line.end]), []byte(strings.ToUpper(m.Keyword))):
+			return edit.Edit{}, fmt.Errorf("the comment line %d doesn't start with %s", line.number, m.Keyword)
+		default:
+			start := line.text + len(m.Keyword)
+			rest := content[start:line.end]
+
+			if m.Owner != "" && bytes.HasPrefix(rest, []byte("(")) {
+				if end := bytes.IndexByte(rest, ')'); end >= 0 {
+					return edit.Edit{Start: start + 1, End: start + end, New: owner}, nil
+				}
+			}
+
+			return edit.Edit{Start: start, End: start, New: "(" + owner + ")"}, nil
+		}
+	}
+}
+
+// snooze sets the snooze date of the comment, replacing the current one, in the annotation following the keyword
+// and the owner in parenthesis, or appended to the line if it doesn't start with the keyword.
+func (t *triage) snooze(content []byte, m godox.Message, line sourceLine) (edit.Edit, error) {
+	layout := t.settings.DeadlineLayout
+
+	for {
+		answer, ok := t.ask("until (" + layout + "): ")
+		if !ok {
+			return edit.Edit{}, errCanceled
+		}
+
+		date, err := time.ParseInLocation(layout, answer, t.settings.Now.Location())
+		if err != nil || !date.After(t.settings.Now) {
+			fmt.Fprintf(t.stderr, "the date has to be in the future in the %s format\n", layout)
+			continue
+		}
+
+		annotation := "[snooze:" + date.Format(layout) + "]"
+		text := content[line.text:line.end]
+
+		if loc := snoozeRe.FindIndex(text); loc != nil {
+			return edit.Edit{Start: line.text + loc[0], End: line.text + loc[1], New: annotation}, nil
+		}
+
+		if !bytes.HasPrefix(bytes.ToUpper(text), []byte(strings.ToUpper(m.Keyword))) {
+			return edit.AppendToLine(content, line.number, " "+annotation)
+		}
+
+		start := line.text + len(m.Keyword)
+		if rest := content[start:line.end]; bytes.HasPrefix(rest, []byte("(")) {
+			if end := bytes.IndexByte(rest, ')'); end >= 0 {
+				start += end + 1
+			}
+		}
+
+		return edit.Edit{Start: start, End: start, New: annotation}, nil
+	}
+}
+
+// snoozeRe matches the snooze annotations of the comments, e.g. [snooze:2025-09-01].
+var snoozeRe = regexp.MustCompile(`\[snooze:[^\]]*\]`)
+
+// deleteComment removes the line comment, with the whole line if there is no code before it,
+// or the line of a block comment which doesn't start or end on it.
+func deleteComment(content []byte, _ godox.Message, line sourceLine) (edit.Edit, error) {
+	if line.partial {
+		return edit.Edit{}, fmt.Errorf("cannot delete the comment continuing after line %d, open it instead", line.number)
+	}
+
+	whole := line.end
+	if whole < len(content) {
+		whole++
+	}
+
+	prefix := bytes.TrimRight(content[line.start:line.text], " \t@")
+
+	if !bytes.HasSuffix(prefix, []byte("//")) {
+		// the text is in a block comment, possibly after a leading asterisk
+		if len(bytes.Trim(prefix, " \t*")) != 0 || bytes.Contains(content[line.text:line.end], []byte("*/")) {
+			return edit.Edit{}, fmt.Errorf("cannot delete the comment line %d, open it instead", line.number)
+		}
+
+		return edit.Edit{Start: line.start, End: whole}, nil
+	}
+
+	code := bytes.TrimRight(bytes.TrimSuffix(prefix, []byte("//")), " \t")
+	if len(bytes.TrimSpace(code)) == 0 {
+		return edit.Edit{Start: line.start, End: whole}, nil
+	}
+
+	return edit.Edit{Start: line.start + len(code), End: line.end}, nil
+}
+
+// sourceLine is a line of the source file containing the comment text of a finding.
+type sourceLine struct {
+	// number of the line, starting at 1.
+	number int
+	// start and end offsets of the line, without the line break.
+	start, end int
+	// text is the offset of the comment text of the finding.
+	text int
+	// partial is set if the line contains only the first line of the comment text, see -multiline.
+	partial bool
+}
+
+// locate finds the line containing the comment text of the finding closest to the line it was reported on,
+// the files might have been edited since they were scanned.
+func locate(content []byte, m godox.Message) (sourceLine, error) {
+	var lines []sourceLine
+
+	for start := 0; start <= len(content); {
+		end := len(content)
+		if i := bytes.IndexByte(content[start:], '\n'); i >= 0 {
+			end = start + i
+		}
+
+		lines = append(lines, sourceLine{number: len(lines) + 1, start: start, end: end})
+		start = end + 1
+	}
+
+	for d := 0; d < len(lines)+m.Line; d++ {
+		for _, n := range []int{m.Line - d, m.Line + d} {
+			if n < 1 || n > len(lines) {
+				continue
+			}
+
+			l := lines[n-1]
+			if i, partial := textIndex(content[l.start:l.end], m.Text); i >= 0 {
+				l.text, l.partial = l.start+i, partial
+				return l, nil
+			}
+		}
+	}
+
+	return sourceLine{}, fmt.Errorf("%s:%d: comment %q not found", filepath.Clean(m.Pos.Filename), m.Line, m.Text)
+}
+
+// textIndex returns the index of the comment text within the line, -1 if the line doesn't contain it, and whether
+// the line contains only its first line. The texts merged with their continuation lines are joined by spaces.
+func textIndex(line []byte, text string) (int, bool) {
+	if i := bytes.Index(line, []byte(text)); i >= 0 {
+		return i, false
+	}
+
+	first := text
+	if i := strings.IndexByte(text, ' '); i >= 0 {
+		first = text[:i]
+	}
+
+	for start := 0; ; start++ {
+		i := bytes.Index(line[start:], []byte(first))
+		if i < 0 {
+			return -1, false
+		}
+
+		start += i
+
+		if rest := bytes.TrimRight(line[start:], " \t\r"); strings.HasPrefix(text, string(rest)+" ") {
+			return start, true
+		}
+	}
+}
+
+// blockMarkers are the markers starting and ending the block comments.
+var blockMarkers = [][2]string{{"/*", "*/"}, {"<!--", "-->"}}
+
+// inBlockComment reports whether the comment text on the line is within a block comment continuing on the next line,
+// either an inner line of the comment or the line starting it.
+func inBlockComment(content []byte, line sourceLine) bool {
+	prefix := content[line.start:line.text]
+	if len(bytes.Trim(prefix, " \t*@")) == 0 {
+		// an inner line, line comments always start with their markers
+		return !closes(content[line.text:line.end])
+	}
+
+	for _, marker := range blockMarkers {
+		start := bytes.LastIndex(prefix, []byte(marker[0]))
+		if start < 0 || bytes.LastIndex(prefix, []byte(marker[1])) > start {
+			continue
+		}
+
+		if comment := bytes.Index(prefix, []byte("//")); comment >= 0 && comment < start {
+			continue
+		}
+
+		return !closes(content[line.text:line.end])
+	}
+
+	return false
+}
+
+// closes reports whether the text ends a block comment.
+func closes(text []byte) bool {
+	for _, marker := range blockMarkers {
+		if bytes.Contains(text, []byte(marker[1])) {
+			return true
+		}
+	}
+
+	return false
+}
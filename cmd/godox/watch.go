@@ -0,0 +1,291 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/matoous/godox"
+	"github.com/matoous/godox/config"
+)
+
+// watchDelay is the time to wait for more changes before re-scanning the changed files.
+const watchDelay = 100 * time.Millisecond
+
+func runWatch(args []string, stdout, stderr io.Writer) int {
+	flags := newFlagSet("godox watch", "[flags] [paths]", stderr)
+
+	var lf lintFlags
+	lf.register(flags)
+
+	metricsAddr := flags.String("metrics-addr", "", "address to serve the metrics in the Prometheus text format on, at /metrics")
+
+	if err := flags.Parse(args); err != nil {
+		return exitError
+	}
+
+	settings, err := lf.settings()
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	compiled, err := settings.Compile()
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	ctx, cancel := interruptContext()
+	defer cancel()
+
+	w := &watcher{settings: compiled, out: stdout, findings: make(map[string][]godox.Message)}
+
+	var served chan error
+
+	if *metricsAddr != "" {
+		l, err := net.Listen("tcp", *metricsAddr)
+		if err != nil {
+			return fail(stderr, err)
+		}
+
+		w.metrics = newMetrics()
+
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", w.metrics)
+
+		served = make(chan error, 1)
+
+		go func() {
+			served <- serveHTTP(ctx, l, mux)
+		}()
+	}
+
+	err = w.watch(ctx, paths)
+
+	if served != nil {
+		cancel()
+
+		if serveErr := <-served; err == nil {
+			err = serveErr
+		}
+	}
+
+	if err != nil {
+		return fail(stderr, err)
+	}
+
+	return exitOK
+}
+
+// watcher re-scans Go files in the watched directories as they change.
+type watcher struct {
+	settings *config.CompiledSettings
+	out      io.Writer
+	// findings by file name
+	findings map[string][]godox.Message
+	// metrics are observed after every scan if set
+	metrics *metrics
+}
+
+// watch scans all files in the paths and then keeps re-scanning the files which changed
+// until the context is cancelled.
+func (w *watcher) watch(ctx context.Context, paths []string) error {
+	fw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer fw.Close()
+
+	var files []string
+
+	for _, path := range paths {
+		found, err := w.add(fw, path)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, found...)
+	}
+
+	start := time.Now()
+
+	for _, file := range files {
+		w.scan(file)
+	}
+
+	w.observe(start)
+
+	for _, file := range files {
+		for _, m := range w.findings[file] {
+			fmt.Fprintln(w.out, m)
+		}
+	}
+
+	fmt.Fprintf(w.out, "%d findings in %d files, watching for changes\n", w.total(), len(files))
+
+	changed := make(map[string]struct{})
+	timer := time.NewTimer(watchDelay)
+	timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-fw.Errors:
+			return err
+		case event := <-fw.Events:
+			if event.Op&fsnotify.Create != 0 {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					found, err := w.add(fw, event.Name)
+					if err != nil {
+						return err
+					}
+
+					for _, file := range found {
+						changed[file] = struct{}{}
+					}
+				}
+			}
+
+			if w.isSource(event.Name) {
+				changed[event.Name] = struct{}{}
+			}
+
+			timer.Reset(watchDelay)
+		case <-timer.C:
+			w.update(changed)
+			changed = make(map[string]struct{})
+		}
+	}
+}
+
+// add watches the directory and all its subdirectories and returns Go files they contain.
+// Directories ignored by the go command, such as vendor or testdata, are skipped.
+func (w *watcher) add(fw *fsnotify.Watcher, root string) ([]string, error) {
+	var files []string
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			name := info.Name()
+			if path != root && (strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") ||
+				name == "vendor" || name == "testdata") {
+				return filepath.SkipDir
+			}
+
+			return fw.Add(path)
+		}
+
+		if w.isSource(path) {
+			files = append(files, path)
+		}
+
+		return nil
+	})
+
+	return files, err
+}
+
+func (w *watcher) isSource(path string) bool {
+	if !strings.HasSuffix(path, ".go") {
+		return false
+	}
+
+	return w.settings.Tests || !strings.HasSuffix(path, "_test.go")
+}
+
+// scan scans the file, files which can't be read or parsed, have invalid nested configuration
+// or a failing message template, have no findings.
+func (w *watcher) scan(filename string) {
+	settings, err := w.settings.ForFile(filename)
+	if err != nil || !settings.IncludesPath(filename) {
+		delete(w.findings, filename)
+		return
+	}
+
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+	if err != nil {
+		delete(w.findings, filename)
+		return
+	}
+
+	messages, err := godox.RunCompiledContext(context.Background(), f, fset, settings)
+	if err != nil {
+		delete(w.findings, filename)
+		return
+	}
+
+	w.findings[filename] = messages
+}
+
+// update re-scans the changed files and prints their findings.
+func (w *watcher) update(changed map[string]struct{}) {
+	files := make([]string, 0, len(changed))
+	for file := range changed {
+		files = append(files, file)
+	}
+
+	sort.Strings(files)
+
+	start := time.Now()
+
+	for _, file := range files {
+		w.scan(file)
+
+		fmt.Fprintf(w.out, "%s: %d findings\n", file, len(w.findings[file]))
+
+		for _, m := range w.findings[file] {
+			fmt.Fprintln(w.out, m)
+		}
+	}
+
+	if len(files) > 0 {
+		w.observe(start)
+		fmt.Fprintf(w.out, "%d findings in total\n", w.total())
+	}
+}
+
+// observe records the findings of all files and duration of the scan started at the time in the metrics.
+func (w *watcher) observe(start time.Time) {
+	if w.metrics == nil {
+		return
+	}
+
+	d := time.Since(start)
+
+	var messages []godox.Message
+	for _, findings := range w.findings {
+		messages = append(messages, findings...)
+	}
+
+	w.metrics.observe(messages, d)
+}
+
+func (w *watcher) total() int {
+	var total int
+	for _, messages := range w.findings {
+		total += len(messages)
+	}
+
+	return total
+}
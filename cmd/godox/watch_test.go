@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/matoous/godox"
+	"github.com/matoous/godox/config"
+)
+
+// syncBuffer is a buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
+// waitFor waits until the buffer contains the text.
+func waitFor(t *testing.T, b *syncBuffer, text string) {
+	t.Helper()
+
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if strings.Contains(b.String(), text) {
+			return
+		}
+	}
+
+	t.Fatalf("timed out waiting for %q, output:\n%s", text, b.String())
+}
+
+func TestWatch(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "godox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	write := func(name, src string) {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(src), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("a.go", "package a\n\n// TODO: first\n")
+
+	compiled, err := (&config.GoDoxSettings{}).Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out syncBuffer
+
+	w := &watcher{settings: compiled, out: &out, findings: make(map[string][]godox.Message)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+
+	go func() {
+		done <- w.watch(ctx, []string{dir})
+	}()
+
+	waitFor(t, &out, "1 findings in 1 files, watching for changes")
+
+	write("b.go", "package a\n\n// FIXME: second\n")
+	waitFor(t, &out, filepath.Join(dir, "b.go")+`:3: Line contains TODO/BUG/FIXME: "FIXME: second"`)
+	waitFor(t, &out, "2 findings in total")
+
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
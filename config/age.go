@@ -0,0 +1,38 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseAge parses age such as 180d, 2w or any time.ParseDuration duration, e.g. 36h.
+// Empty age is zero.
+func ParseAge(age string) (time.Duration, error) {
+	if age == "" {
+		return 0, nil
+	}
+
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+
+	for suffix, unit := range units {
+		if !strings.HasSuffix(age, suffix) {
+			continue
+		}
+
+		n, err := strconv.Atoi(strings.TrimSuffix(age, suffix))
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid age %q", age)
+		}
+
+		return time.Duration(n) * unit, nil
+	}
+
+	d, err := time.ParseDuration(age)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age %q", age)
+	}
+
+	return d, nil
+}
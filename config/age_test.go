@@ -0,0 +1,44 @@
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/matoous/godox/config"
+)
+
+func TestParseAge(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		age      string
+		expected time.Duration
+		err      bool
+	}{
+		{age: "", expected: 0},
+		{age: "180d", expected: 180 * 24 * time.Hour},
+		{age: "2w", expected: 14 * 24 * time.Hour},
+		{age: "36h", expected: 36 * time.Hour},
+		{age: "1h30m", expected: 90 * time.Minute},
+		{age: "d", err: true},
+		{age: "-1d", err: true},
+		{age: "-1h", err: true},
+		{age: "3x", err: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt //nolint // reason tt is ok on this context
+		t.Run(tt.age, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := config.ParseAge(tt.age)
+			if (err != nil) != tt.err {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if actual != tt.expected {
+				t.Errorf("not equal\nexpected: %v\nactual: %v", tt.expected, actual)
+			}
+		})
+	}
+}
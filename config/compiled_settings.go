@@ -0,0 +1,751 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/matoous/godox/internal/glob"
+	"github.com/matoous/godox/internal/matcher"
+)
+
+// DefaultKeywords are used when no keywords are configured.
+var DefaultKeywords = []string{"TODO", "BUG", "FIXME"}
+
+// BuiltinAliases are the common aliases of the default keywords, selected by the BuiltinAliasesName value of the
+// aliases in the configuration files or the -aliases flag. No aliases are matched unless configured.
+var BuiltinAliases = map[string]string{
+	"XXX":      "FIXME",
+	"HACK":     "FIXME",
+	"NOTE":     "TODO",
+	"OPTIMIZE": "TODO",
+	"WIP":      "TODO",
+}
+
+// MessageTemplateData is the value the message templates are executed with once by Compile, so that templates
+// referring to missing fields fail upfront. The godox package sets it to a zero godox.Message.
+var MessageTemplateData interface{}
+
+// BuiltinAliasesName is the value of the aliases selecting the BuiltinAliases, e.g. aliases: builtin.
+const BuiltinAliasesName = "builtin"
+
+// DefaultIssuePatterns recognize URLs, Jira style keys (PROJ-123) and GitHub style references (#123, owner/repo#123).
+var DefaultIssuePatterns = []IssuePattern{
+	{Tracker: "url", RegularExpression: `https?://[^\s)\]]+`},
+	{Tracker: "jira", RegularExpression: `\b[A-Z][A-Z0-9]+-\d+\b`},
+	{Tracker: "github", RegularExpression: `(?:[\w.-]+/[\w.-]+)?#\d+\b`},
+}
+
+// Deadline defaults.
+const (
+	DefaultDeadlineLayout  = "2006-01-02"
+	DefaultDeadlinePattern = `\b\d{4}-\d{2}-\d{2}\b`
+)
+
+// DefaultFormatRuleKeyword is the keyword of the default format rules, which apply to the comment lines starting
+// with the keywords without format rules of their own.
+const DefaultFormatRuleKeyword = "*"
+
+// DefaultMaxMessageLength is the number of runes of the comment lines quoted in the messages.
+const DefaultMaxMessageLength = 40
+
+// DefaultOwnerPattern matches owners in parenthesis right after the keyword, e.g. TODO(alice) or TODO(@alice, PROJ-1).
+// The owner has to start with a letter so deadlines and issue references are not considered owners.
+const DefaultOwnerPattern = `^\(\s*@?([A-Za-z][\w.-]*)`
+
+// GitHub defaults.
+const (
+	DefaultGitHubAPIURL   = "https://api.github.com"
+	DefaultGitHubTokenEnv = "GITHUB_TOKEN"
+)
+
+// GitLab defaults.
+const (
+	DefaultGitLabURL      = "https://gitlab.com"
+	DefaultGitLabTokenEnv = "GITLAB_TOKEN"
+)
+
+// Jira defaults.
+const (
+	DefaultJiraUserEnv   = "JIRA_USER"
+	DefaultJiraTokenEnv  = "JIRA_API_TOKEN"
+	DefaultJiraIssueType = "Task"
+)
+
+// CompiledSettings are settings prepared for running the linter.
+type CompiledSettings struct {
+	GoDoxSettings
+
+	FormatRules   []CompiledFormatRule
+	IssuePatterns []CompiledIssuePattern
+	OwnerRegexp   *regexp.Regexp
+	// Roster contains lower cased known owners, nil if there is no roster.
+	Roster         map[string]struct{}
+	DeadlineRegexp *regexp.Regexp
+	// MinAge and EscalateAge are the parsed OlderThan and EscalateAfter ages, zero if not set.
+	MinAge      time.Duration
+	EscalateAge time.Duration
+	// Now is the time deadlines are compared with, it is set to the time of the compilation.
+	Now time.Time
+	// Template is the parsed MessageTemplate, nil if there is none.
+	Template *template.Template
+	// PathBudgets are the compiled Budgets sorted by their patterns.
+	PathBudgets []CompiledBudget
+
+	// severities, case sensitivity, canonical and forbidden keywords by upper cased keywords
+	severities    map[string]string
+	minLengths    map[string]int
+	forbidden     map[string]struct{}
+	disabled      map[string]struct{}
+	caseSensitive map[string]bool
+	aliases       map[string]string
+	keywords      *matcher.Matcher
+	includes      []*glob.Pattern
+	excludes      []*glob.Pattern
+	raw           []*glob.Pattern
+	hierarchy     *Hierarchy
+}
+
+// MatchKeyword returns the keyword the comment line starts with, or the first keyword found
+// anywhere in the line if Anywhere is set, and its offset within the line.
+func (s *CompiledSettings) MatchKeyword(line []byte) (string, int, bool) {
+	if s.Anywhere {
+		keyword, start, _, ok := s.keywords.Find(line)
+		return keyword, start, ok
+	}
+
+	keyword, _, ok := s.keywords.Match(line)
+
+	return keyword, 0, ok
+}
+
+// KeywordMatch is a keyword found in a comment line at the offset.
+type KeywordMatch struct {
+	Keyword string
+	Start   int
+}
+
+// MatchKeywords returns the keyword found by MatchKeyword, or all keywords found in the line if ReportAllMatches
+// is set. Lines have to start with a keyword unless Anywhere is set.
+func (s *CompiledSettings) MatchKeywords(line []byte) []KeywordMatch {
+	if !s.ReportAllMatches {
+		keyword, start, ok := s.MatchKeyword(line)
+		if !ok {
+			return nil
+		}
+
+		return []KeywordMatch{{Keyword: keyword, Start: start}}
+	}
+
+	if !s.Anywhere {
+		if _, _, ok := s.keywords.Match(line); !ok {
+			return nil
+		}
+	}
+
+	return s.FindKeywords(line)
+}
+
+// FindKeywords returns the first keyword found anywhere in the line at word boundaries, or all of them
+// if ReportAllMatches is set, regardless of Anywhere.
+func (s *CompiledSettings) FindKeywords(line []byte) []KeywordMatch {
+	if !s.ReportAllMatches {
+		keyword, start, _, ok := s.keywords.Find(line)
+		if !ok {
+			return nil
+		}
+
+		return []KeywordMatch{{Keyword: keyword, Start: start}}
+	}
+
+	found := s.keywords.FindAll(line)
+	matches := make([]KeywordMatch, len(found))
+
+	for i, f := range found {
+		matches[i] = KeywordMatch{Keyword: f.Keyword, Start: f.Start}
+	}
+
+	return matches
+}
+
+// Canonical returns the configured keyword the alias is reported as, or the keyword if it isn't an alias.
+func (s *CompiledSettings) Canonical(keyword string) string {
+	if canonical, ok := s.aliases[strings.ToUpper(keyword)]; ok {
+		return canonical
+	}
+
+	return keyword
+}
+
+// IsForbidden reports whether the keyword, or the keyword it is an alias of, is forbidden.
+func (s *CompiledSettings) IsForbidden(keyword string) bool {
+	if _, ok := s.forbidden[strings.ToUpper(keyword)]; ok {
+		return true
+	}
+
+	_, ok := s.forbidden[strings.ToUpper(s.Canonical(keyword))]
+
+	return ok
+}
+
+// MessageLength returns the number of runes of the comment lines quoted in the messages, 0 if they are not truncated.
+func (s *CompiledSettings) MessageLength() int {
+	if s.MaxMessageLength == nil {
+		return DefaultMaxMessageLength
+	}
+
+	return *s.MaxMessageLength
+}
+
+// HasPriority reports whether comments with the priority, empty if they have none, are reported
+// according to the MinPriority.
+func (s *CompiledSettings) HasPriority(priority string) bool {
+	return s.MinPriority == "" || PriorityRank(priority) <= PriorityRank(s.MinPriority)
+}
+
+// HasLabel reports whether comments with the labels are reported according to the Labels.
+func (s *CompiledSettings) HasLabel(labels []string) bool {
+	if len(s.Labels) == 0 {
+		return true
+	}
+
+	for _, label := range labels {
+		for _, l := range s.Labels {
+			if strings.EqualFold(label, l) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// PriorityRank returns the index of the priority in the Priorities, unknown and empty priorities
+// are ranked after all of them.
+func PriorityRank(priority string) int {
+	for i, p := range Priorities {
+		if p == priority {
+			return i
+		}
+	}
+
+	return len(Priorities)
+}
+
+// IsRuleDisabled reports whether any of the rules is disabled by the DisabledRules.
+func (s *CompiledSettings) IsRuleDisabled(ids ...string) bool {
+	for _, id := range ids {
+		if _, ok := s.disabled[id]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsCaseSensitive reports whether the keyword is matched only if its case is the same.
+func (s *CompiledSettings) IsCaseSensitive(keyword string) bool {
+	if caseSensitive, ok := s.caseSensitive[strings.ToUpper(keyword)]; ok {
+		return caseSensitive
+	}
+
+	return s.CaseSensitive
+}
+
+// ForFile returns the effective settings of the file, the settings themselves unless ConfigRoot is set.
+func (s *CompiledSettings) ForFile(filename string) (*CompiledSettings, error) {
+	if s.hierarchy == nil {
+		return s, nil
+	}
+
+	return s.hierarchy.Compiled(filename)
+}
+
+// Hierarchy returns the hierarchy of the nested configuration files, nil unless ConfigRoot is set.
+func (s *CompiledSettings) Hierarchy() *Hierarchy {
+	return s.hierarchy
+}
+
+// IncludesPath reports whether the file should be scanned according to the IncludePaths and ExcludePaths.
+func (s *CompiledSettings) IncludesPath(filename string) bool {
+	name := filepath.ToSlash(filename)
+
+	for _, p := range s.excludes {
+		if p.Match(name) {
+			return false
+		}
+	}
+
+	if len(s.includes) == 0 {
+		return true
+	}
+
+	for _, p := range s.includes {
+		if p.Match(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IncludesFile reports whether the other than Go file is scanned according to the Include patterns.
+func (s *CompiledSettings) IncludesFile(filename string) bool {
+	name := filepath.ToSlash(filename)
+
+	for _, p := range s.raw {
+		if p.Match(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Severity returns severity of findings for the keyword, aliases without a severity have the severity
+// of their canonical keyword.
+func (s *CompiledSettings) Severity(keyword string) string {
+	if severity, ok := s.severities[strings.ToUpper(keyword)]; ok {
+		return severity
+	}
+
+	if severity, ok := s.severities[strings.ToUpper(s.Canonical(keyword))]; ok {
+		return severity
+	}
+
+	return SeverityWarning
+}
+
+// RequiredDescriptionLength returns the minimum description length of comments of the keyword, zero if there
+// is none. Aliases without a length have the length of their canonical keyword.
+func (s *CompiledSettings) RequiredDescriptionLength(keyword string) int {
+	if length, ok := s.minLengths[strings.ToUpper(keyword)]; ok {
+		return length
+	}
+
+	return s.minLengths[strings.ToUpper(s.Canonical(keyword))]
+}
+
+// FormatRuleKeyword returns the keyword the comment line starts with if the format rule applies to the line.
+// The default rules apply to the keywords, and their aliases, without rules of their own.
+func (s *CompiledSettings) FormatRuleKeyword(rule CompiledFormatRule, line []byte) (string, bool) {
+	if rule.Keyword != DefaultFormatRuleKeyword {
+		return rule.Keyword, matcher.HasKeyword(line, rule.Keyword, s.IsCaseSensitive(rule.Keyword))
+	}
+
+	keyword, _, ok := s.keywords.Match(line)
+	if !ok {
+		return "", false
+	}
+
+	for _, r := range s.FormatRules {
+		if r.Keyword == DefaultFormatRuleKeyword {
+			continue
+		}
+
+		if strings.EqualFold(r.Keyword, keyword) || strings.EqualFold(r.Keyword, s.Canonical(keyword)) {
+			return "", false
+		}
+	}
+
+	return keyword, true
+}
+
+// CompiledFormatRule is a format rule with compiled regular expression.
+type CompiledFormatRule struct {
+	GoDoxFormatRule
+
+	// Regexp is nil if the rule has no regular expression.
+	Regexp *regexp.Regexp
+	// ForbidRegexp is nil if the rule has no forbidden pattern.
+	ForbidRegexp *regexp.Regexp
+	// FixRegexp is nil if the rule has no fix pattern.
+	FixRegexp *regexp.Regexp
+}
+
+// CompiledBudget is a budget of findings of the files matching the glob pattern.
+type CompiledBudget struct {
+	Pattern string
+	Max     int
+
+	glob *glob.Pattern
+}
+
+// Match reports whether the file is covered by the budget.
+func (b CompiledBudget) Match(filename string) bool {
+	return b.glob.Match(filepath.ToSlash(filename))
+}
+
+// CompiledIssuePattern is an issue pattern with compiled regular expression.
+type CompiledIssuePattern struct {
+	IssuePattern
+
+	Regexp *regexp.Regexp
+}
+
+// Compile validates the settings, compiles all format rules and fills in the defaults.
+// The compiled settings can be reused for any number of runs.
+func (s *GoDoxSettings) Compile() (*CompiledSettings, error) {
+	compiled := &CompiledSettings{
+		GoDoxSettings: *s,
+		FormatRules:   make([]CompiledFormatRule, 0, len(s.FormatRules)),
+	}
+
+	if len(compiled.Keywords) == 0 {
+		compiled.Keywords = DefaultKeywords
+	}
+
+	compiled.caseSensitive = make(map[string]bool, len(s.CaseSensitiveKeywords))
+
+	for kw, caseSensitive := range s.CaseSensitiveKeywords {
+		compiled.caseSensitive[strings.ToUpper(kw)] = caseSensitive
+	}
+
+	matched, err := compiled.compileAliases()
+	if err != nil {
+		return nil, err
+	}
+
+	compiled.forbidden = make(map[string]struct{}, len(s.ForbiddenKeywords))
+
+	for i, kw := range s.ForbiddenKeywords {
+		if kw == "" {
+			return nil, fmt.Errorf("forbidden keyword %d: empty keyword", i)
+		}
+
+		compiled.forbidden[strings.ToUpper(kw)] = struct{}{}
+
+		if _, ok := findKeyword(matched, kw); !ok {
+			matched = append(matched, kw)
+		}
+	}
+
+	caseSensitive := make([]bool, len(matched))
+	for i, kw := range matched {
+		caseSensitive[i] = compiled.IsCaseSensitive(kw)
+	}
+
+	compiled.keywords = matcher.NewCaseSensitive(matched, caseSensitive)
+
+	compiled.severities = make(map[string]string, len(s.Severities))
+
+	for kw, severity := range s.Severities {
+		switch severity {
+		case SeverityError, SeverityWarning, SeverityInfo:
+		default:
+			return nil, fmt.Errorf("keyword %s: unknown severity %q", kw, severity)
+		}
+
+		compiled.severities[strings.ToUpper(kw)] = severity
+	}
+
+	compiled.minLengths = make(map[string]int, len(s.MinDescriptionLength))
+
+	for kw, length := range s.MinDescriptionLength {
+		if length < 0 {
+			return nil, fmt.Errorf("keyword %s: negative minimum description length %d", kw, length)
+		}
+
+		compiled.minLengths[strings.ToUpper(kw)] = length
+	}
+
+	switch s.ExportedDocsSeverity {
+	case "":
+		compiled.ExportedDocsSeverity = SeverityError
+	case SeverityError, SeverityWarning, SeverityInfo:
+	default:
+		return nil, fmt.Errorf("exported docs: unknown severity %q", s.ExportedDocsSeverity)
+	}
+
+	if compiled.includes, err = compileGlobs("include path", s.IncludePaths); err != nil {
+		return nil, err
+	}
+
+	if compiled.excludes, err = compileGlobs("exclude path", s.ExcludePaths); err != nil {
+		return nil, err
+	}
+
+	if compiled.raw, err = compileGlobs("include", s.Include); err != nil {
+		return nil, err
+	}
+
+	if compiled.PathBudgets, err = compileBudgets(s.Budgets); err != nil {
+		return nil, err
+	}
+
+	switch s.Engine {
+	case "", EngineParser, EngineFast:
+	default:
+		return nil, fmt.Errorf("unknown engine %q", s.Engine)
+	}
+
+	switch s.DeadlineMode {
+	case "", DeadlineModeExpired, DeadlineModeEscalate:
+	default:
+		return nil, fmt.Errorf("unknown deadline mode %q", s.DeadlineMode)
+	}
+
+	compiled.MinPriority = strings.ToUpper(s.MinPriority)
+	if compiled.MinPriority != "" && PriorityRank(compiled.MinPriority) == len(Priorities) {
+		return nil, fmt.Errorf("unknown minimum priority %q, expected one of %s", s.MinPriority, strings.Join(Priorities, ", "))
+	}
+
+	if compiled.MinAge, err = ParseAge(s.OlderThan); err != nil {
+		return nil, fmt.Errorf("older than: %w", err)
+	}
+
+	if compiled.EscalateAge, err = ParseAge(s.EscalateAfter); err != nil {
+		return nil, fmt.Errorf("escalate after: %w", err)
+	}
+
+	if compiled.GitHub.APIURL == "" {
+		compiled.GitHub.APIURL = DefaultGitHubAPIURL
+	}
+
+	if compiled.GitHub.TokenEnv == "" {
+		compiled.GitHub.TokenEnv = DefaultGitHubTokenEnv
+	}
+
+	if compiled.GitLab.URL == "" {
+		compiled.GitLab.URL = DefaultGitLabURL
+	}
+
+	if compiled.GitLab.TokenEnv == "" {
+		compiled.GitLab.TokenEnv = DefaultGitLabTokenEnv
+	}
+
+	for i, c := range s.TrackerCommands {
+		if c.Tracker == "" || len(c.Command) == 0 {
+			return nil, fmt.Errorf("tracker command %d: tracker and command are required", i)
+		}
+	}
+
+	if compiled.Jira.UserEnv == "" {
+		compiled.Jira.UserEnv = DefaultJiraUserEnv
+	}
+
+	if compiled.Jira.TokenEnv == "" {
+		compiled.Jira.TokenEnv = DefaultJiraTokenEnv
+	}
+
+	if compiled.Jira.IssueType == "" {
+		compiled.Jira.IssueType = DefaultJiraIssueType
+	}
+
+	if compiled.OwnerPattern == "" {
+		compiled.OwnerPattern = DefaultOwnerPattern
+	}
+
+	owner, err := regexp.Compile(compiled.OwnerPattern)
+	if err != nil {
+		return nil, fmt.Errorf("owner pattern: %w", err)
+	}
+
+	compiled.OwnerRegexp = owner
+
+	if s.OwnerRoster != "" {
+		roster, err := LoadRoster(s.OwnerRoster)
+		if err != nil {
+			return nil, fmt.Errorf("owner roster: %w", err)
+		}
+
+		compiled.Roster = roster
+	}
+
+	if compiled.DeadlineLayout == "" {
+		compiled.DeadlineLayout = DefaultDeadlineLayout
+	}
+
+	if compiled.DeadlinePattern == "" {
+		compiled.DeadlinePattern = DefaultDeadlinePattern
+	}
+
+	re, err := regexp.Compile(compiled.DeadlinePattern)
+	if err != nil {
+		return nil, fmt.Errorf("deadline pattern: %w", err)
+	}
+
+	compiled.DeadlineRegexp = re
+	compiled.Now = time.Now()
+
+	if s.MaxMessageLength != nil && *s.MaxMessageLength < 0 {
+		return nil, fmt.Errorf("negative max message length %d", *s.MaxMessageLength)
+	}
+
+	if s.MessageTemplate != "" {
+		if compiled.Template, err = template.New("message").Parse(s.MessageTemplate); err != nil {
+			return nil, fmt.Errorf("message template: %w", err)
+		}
+
+		if MessageTemplateData != nil {
+			if err = compiled.Template.Execute(ioutil.Discard, MessageTemplateData); err != nil {
+				return nil, fmt.Errorf("message template: %w", err)
+			}
+		}
+	}
+
+	compiled.disabled = make(map[string]struct{}, len(s.DisabledRules))
+
+	for _, id := range s.DisabledRules {
+		compiled.disabled[id] = struct{}{}
+	}
+
+	ids := make(map[string]struct{}, len(s.FormatRules))
+
+	for i, rule := range s.FormatRules {
+		if rule.Keyword == "" {
+			return nil, fmt.Errorf("format rule %d: missing keyword", i)
+		}
+
+		if rule.ID == "" {
+			rule.ID = fmt.Sprintf("format-%d", i)
+		}
+
+		if _, ok := ids[rule.ID]; ok {
+			return nil, fmt.Errorf("format rule %d (%s): duplicate id %s", i, rule.Keyword, rule.ID)
+		}
+
+		ids[rule.ID] = struct{}{}
+
+		cr := CompiledFormatRule{GoDoxFormatRule: rule}
+
+		if rule.RegularExpression != "" {
+			re, err := regexp.Compile(rule.RegularExpression)
+			if err != nil {
+				return nil, fmt.Errorf("format rule %d (%s): %w", i, rule.Keyword, err)
+			}
+
+			cr.Regexp = re
+		}
+
+		if rule.Forbid != "" {
+			re, err := regexp.Compile(rule.Forbid)
+			if err != nil {
+				return nil, fmt.Errorf("format rule %d (%s) forbid: %w", i, rule.Keyword, err)
+			}
+
+			cr.ForbidRegexp = re
+		}
+
+		if (rule.FixPattern == "") != (rule.Fix == "") {
+			return nil, fmt.Errorf("format rule %d (%s): fix pattern and fix have to be set together", i, rule.Keyword)
+		}
+
+		if rule.FixPattern != "" {
+			re, err := regexp.Compile(rule.FixPattern)
+			if err != nil {
+				return nil, fmt.Errorf("format rule %d (%s) fix pattern: %w", i, rule.Keyword, err)
+			}
+
+			cr.FixRegexp = re
+		}
+
+		compiled.FormatRules = append(compiled.FormatRules, cr)
+	}
+
+	patterns := s.IssuePatterns
+	if len(patterns) == 0 {
+		patterns = DefaultIssuePatterns
+	}
+
+	for i, p := range patterns {
+		re, err := regexp.Compile(p.RegularExpression)
+		if err != nil {
+			return nil, fmt.Errorf("issue pattern %d (%s): %w", i, p.Tracker, err)
+		}
+
+		compiled.IssuePatterns = append(compiled.IssuePatterns, CompiledIssuePattern{IssuePattern: p, Regexp: re})
+	}
+
+	if s.ConfigRoot != "" {
+		if compiled.hierarchy, err = NewHierarchy(s.ConfigRoot, compiled); err != nil {
+			return nil, err
+		}
+	}
+
+	return compiled, nil
+}
+
+// compileAliases fills in the canonical keywords of the aliases and returns all keywords to match,
+// the configured keywords first followed by the aliases ordered by name.
+func (s *CompiledSettings) compileAliases() ([]string, error) {
+	aliases := s.Aliases
+
+	names := make([]string, 0, len(aliases))
+	for alias := range aliases {
+		names = append(names, alias)
+	}
+
+	sort.Strings(names)
+
+	matched := append([]string(nil), s.Keywords...)
+	s.aliases = make(map[string]string, len(aliases))
+
+	for _, alias := range names {
+		canonical, ok := findKeyword(s.Keywords, aliases[alias])
+		if !ok {
+			return nil, fmt.Errorf("alias %s: unknown keyword %s", alias, aliases[alias])
+		}
+
+		if _, ok := findKeyword(s.Keywords, alias); ok {
+			continue
+		}
+
+		matched = append(matched, alias)
+		s.aliases[strings.ToUpper(alias)] = canonical
+	}
+
+	return matched, nil
+}
+
+// findKeyword returns the keyword from the list equal to the keyword ignoring case.
+func findKeyword(keywords []string, keyword string) (string, bool) {
+	for _, kw := range keywords {
+		if strings.EqualFold(kw, keyword) {
+			return kw, true
+		}
+	}
+
+	return "", false
+}
+
+func compileBudgets(budgets map[string]int) ([]CompiledBudget, error) {
+	compiled := make([]CompiledBudget, 0, len(budgets))
+
+	for pattern, max := range budgets {
+		if max < 0 {
+			return nil, fmt.Errorf("budget %s: negative number of findings %d", pattern, max)
+		}
+
+		p, err := glob.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("budget %s: %w", pattern, err)
+		}
+
+		compiled = append(compiled, CompiledBudget{Pattern: pattern, Max: max, glob: p})
+	}
+
+	sort.Slice(compiled, func(i, j int) bool { return compiled[i].Pattern < compiled[j].Pattern })
+
+	return compiled, nil
+}
+
+func compileGlobs(what string, patterns []string) ([]*glob.Pattern, error) {
+	compiled := make([]*glob.Pattern, 0, len(patterns))
+
+	for i, pattern := range patterns {
+		p, err := glob.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%s %d: %w", what, i, err)
+		}
+
+		compiled = append(compiled, p)
+	}
+
+	return compiled, nil
+}
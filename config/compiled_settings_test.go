@@ -0,0 +1,265 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/matoous/godox/config"
+)
+
+func TestCompile(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		settings config.GoDoxSettings
+		err      string
+	}{
+		{
+			name: "valid",
+			settings: config.GoDoxSettings{
+				FormatRules: []config.GoDoxFormatRule{
+					{Keyword: "TODO", RegularExpression: `^TODO\([a-z]+\)`},
+					{Keyword: "FIXME"},
+				},
+			},
+		},
+		{
+			name: "invalid regular expression",
+			settings: config.GoDoxSettings{
+				FormatRules: []config.GoDoxFormatRule{
+					{Keyword: "TODO", RegularExpression: `^TODO(`},
+				},
+			},
+			err: "format rule 0 (TODO): error parsing regexp: missing closing ): `^TODO(`",
+		},
+		{
+			name:     "invalid minimum priority",
+			settings: config.GoDoxSettings{MinPriority: "P4"},
+			err:      `unknown minimum priority "P4", expected one of P0, P1, P2, P3`,
+		},
+		{
+			name: "invalid severity",
+			settings: config.GoDoxSettings{
+				Severities: map[string]string{"TODO": "fatal"},
+			},
+			err: `keyword TODO: unknown severity "fatal"`,
+		},
+		{
+			name:     "negative minimum description length",
+			settings: config.GoDoxSettings{MinDescriptionLength: map[string]int{"TODO": -1}},
+			err:      "keyword TODO: negative minimum description length -1",
+		},
+		{
+			name:     "empty forbidden keyword",
+			settings: config.GoDoxSettings{ForbiddenKeywords: []string{"FIXME", ""}},
+			err:      "forbidden keyword 1: empty keyword",
+		},
+		{
+			name:     "invalid exported docs severity",
+			settings: config.GoDoxSettings{ForbidExportedDocs: true, ExportedDocsSeverity: "fatal"},
+			err:      `exported docs: unknown severity "fatal"`,
+		},
+		{
+			name: "missing keyword",
+			settings: config.GoDoxSettings{
+				FormatRules: []config.GoDoxFormatRule{
+					{RegularExpression: `^TODO`},
+				},
+			},
+			err: "format rule 0: missing keyword",
+		},
+		{
+			name: "fix without fix pattern",
+			settings: config.GoDoxSettings{
+				FormatRules: []config.GoDoxFormatRule{
+					{Keyword: "TODO", RegularExpression: `^TODO: \w`, Fix: "TODO: $1"},
+				},
+			},
+			err: "format rule 0 (TODO): fix pattern and fix have to be set together",
+		},
+		{
+			name:     "negative max message length",
+			settings: config.GoDoxSettings{MaxMessageLength: func() *int { n := -1; return &n }()},
+			err:      "negative max message length -1",
+		},
+		{
+			name:     "invalid message template",
+			settings: config.GoDoxSettings{MessageTemplate: "{{.Keyword"},
+			err:      "message template: template: message:1: unclosed action",
+		},
+		{
+			name: "duplicate rule id",
+			settings: config.GoDoxSettings{
+				FormatRules: []config.GoDoxFormatRule{
+					{ID: "format-1", Keyword: "TODO"},
+					{Keyword: "FIXME"},
+				},
+			},
+			err: "format rule 1 (FIXME): duplicate id format-1",
+		},
+		{
+			name: "invalid forbidden pattern",
+			settings: config.GoDoxSettings{
+				FormatRules: []config.GoDoxFormatRule{
+					{Keyword: "TODO", Forbid: `(?i)temporar(y`},
+				},
+			},
+			err: "format rule 0 (TODO) forbid: error parsing regexp: missing closing ): `(?i)temporar(y`",
+		},
+		{
+			name:     "invalid exclude path",
+			settings: config.GoDoxSettings{ExcludePaths: []string{"vendor/**", "[a"}},
+			err:      `exclude path 1: glob "[a": syntax error in pattern`,
+		},
+		{
+			name:     "negative budget",
+			settings: config.GoDoxSettings{Budgets: map[string]int{"pkg/payments/**": -1}},
+			err:      "budget pkg/payments/**: negative number of findings -1",
+		},
+		{
+			name:     "invalid budget pattern",
+			settings: config.GoDoxSettings{Budgets: map[string]int{"[a": 1}},
+			err:      `budget [a: glob "[a": syntax error in pattern`,
+		},
+		{
+			name: "alias of unknown keyword",
+			settings: config.GoDoxSettings{
+				Aliases: map[string]string{"HACK": "KLUDGE"},
+			},
+			err: "alias HACK: unknown keyword KLUDGE",
+		},
+		{
+			name:     "invalid age",
+			settings: config.GoDoxSettings{OlderThan: "six months"},
+			err:      `older than: invalid age "six months"`,
+		},
+		{
+			name: "tracker command without command",
+			settings: config.GoDoxSettings{
+				TrackerCommands: []config.TrackerCommand{{Tracker: "custom"}},
+			},
+			err: "tracker command 0: tracker and command are required",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt //nolint // reason tt is ok on this context
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			compiled, err := tt.settings.Compile()
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(compiled.Keywords) == 0 {
+				t.Error("expected default keywords")
+			}
+
+			if len(tt.settings.Keywords) != 0 {
+				t.Error("compile should not modify the settings")
+			}
+
+			if len(compiled.FormatRules) != len(tt.settings.FormatRules) {
+				t.Errorf("expected %d rules, got %d", len(tt.settings.FormatRules), len(compiled.FormatRules))
+			}
+		})
+	}
+}
+
+func TestAliases(t *testing.T) {
+	t.Parallel()
+
+	aliases := map[string]string{"HACK": "FIXME", "XXX": "FIXME"}
+
+	tests := []struct {
+		name      string
+		settings  config.GoDoxSettings
+		line      string
+		keyword   string
+		canonical string
+		severity  string
+	}{
+		{
+			name: "default",
+			line: "HACK: x",
+		},
+		{
+			name:      "configured",
+			settings:  config.GoDoxSettings{Aliases: aliases},
+			line:      "HACK: x",
+			keyword:   "HACK",
+			canonical: "FIXME",
+			severity:  config.SeverityWarning,
+		},
+		{
+			name:      "canonical severity",
+			settings:  config.GoDoxSettings{Aliases: aliases, Severities: map[string]string{"fixme": config.SeverityError}},
+			line:      "xxx: x",
+			keyword:   "XXX",
+			canonical: "FIXME",
+			severity:  config.SeverityError,
+		},
+		{
+			name: "alias severity",
+			settings: config.GoDoxSettings{
+				Aliases:    aliases,
+				Severities: map[string]string{"FIXME": config.SeverityError, "HACK": config.SeverityInfo},
+			},
+			line:      "HACK: x",
+			keyword:   "HACK",
+			canonical: "FIXME",
+			severity:  config.SeverityInfo,
+		},
+		{
+			name:     "disabled",
+			settings: config.GoDoxSettings{Aliases: map[string]string{}},
+			line:     "WIP: x",
+		},
+		{
+			name:      "custom",
+			settings:  config.GoDoxSettings{Keywords: []string{"TODO"}, Aliases: map[string]string{"LATER": "todo"}},
+			line:      "LATER: x",
+			keyword:   "LATER",
+			canonical: "TODO",
+			severity:  config.SeverityWarning,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt //nolint // reason tt is ok on this context
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			compiled, err := tt.settings.Compile()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			keyword, _, ok := compiled.MatchKeyword([]byte(tt.line))
+			if keyword != tt.keyword || ok != (tt.keyword != "") {
+				t.Fatalf("expected keyword %q, got %q", tt.keyword, keyword)
+			}
+
+			if !ok {
+				return
+			}
+
+			if canonical := compiled.Canonical(keyword); canonical != tt.canonical {
+				t.Errorf("expected canonical keyword %q, got %q", tt.canonical, canonical)
+			}
+
+			if severity := compiled.Severity(keyword); severity != tt.severity {
+				t.Errorf("expected severity %q, got %q", tt.severity, severity)
+			}
+		})
+	}
+}
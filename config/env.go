@@ -0,0 +1,165 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// EnvPrefix is the prefix of the environment variables overriding the settings, see LoadEnv.
+const EnvPrefix = "GODOX_"
+
+// EnvName returns the environment variable overriding the setting with the key, the key of the nested
+// settings is the path of the keys, e.g. GODOX_REQUIRE_OWNER for require-owner and GODOX_GITHUB_REPOSITORY
+// for the repository of github.
+func EnvName(key string) string {
+	return EnvPrefix + strings.NewReplacer("-", "_", ".", "_").Replace(strings.ToUpper(key))
+}
+
+// LoadEnv reads the settings from the environment variables named by EnvName over s, looking them up
+// using the lookup function, e.g. os.LookupEnv. The values of the string settings are used as they are,
+// the other values are YAML, e.g. GODOX_KEYWORDS='[TODO, FIXME]' or GODOX_SEVERITIES='{FIXME: error}'.
+// Relative paths are relative to the working directory.
+//
+// The settings are meant to be read from the defaults, the configuration file, the environment
+// and the flags, in the order, so that the environment overrides the file and the flags override both.
+func (s *GoDoxSettings) LoadEnv(lookup func(string) (string, bool)) error {
+	raw, err := envSettings(reflect.TypeOf(*s), "", lookup)
+	if err != nil {
+		return err
+	}
+
+	if len(raw) == 0 {
+		return nil
+	}
+
+	return s.decode(raw, ".")
+}
+
+// envSettings returns the raw values of the environment variables of the settings of the struct type
+// keyed by the mapstructure tags, the path is the key of the struct.
+func envSettings(t reflect.Type, path string, lookup func(string) (string, bool)) (map[string]interface{}, error) {
+	raw := make(map[string]interface{})
+
+	for i := 0; i < t.NumField(); i++ {
+		key := t.Field(i).Tag.Get("mapstructure")
+		if key == "-" {
+			continue
+		}
+
+		if key == "" {
+			key = strings.ToLower(t.Field(i).Name)
+		}
+
+		if path != "" {
+			key = path + "." + key
+		}
+
+		name := key[strings.LastIndexByte(key, '.')+1:]
+
+		if ft := t.Field(i).Type; ft.Kind() == reflect.Struct {
+			nested, err := envSettings(ft, key, lookup)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(nested) > 0 {
+				raw[name] = nested
+			}
+
+			continue
+		}
+
+		value, ok := lookup(EnvName(key))
+		if !ok {
+			continue
+		}
+
+		if t.Field(i).Type.Kind() == reflect.String {
+			raw[name] = value
+			continue
+		}
+
+		var v interface{}
+		if err := yaml.Unmarshal([]byte(value), &v); err != nil {
+			return nil, fmt.Errorf("%s: %w", EnvName(key), err)
+		}
+
+		raw[name] = v
+	}
+
+	return raw, nil
+}
+
+// envRefRe matches the references to environment variables, e.g. ${GITHUB_TOKEN}, and the escaped $${.
+var envRefRe = regexp.MustCompile(`\$\$\{|\$\{([A-Z_][A-Z0-9_]*)\}`)
+
+// interpolate replaces the references to environment variables in the string values of the raw settings,
+// the variables have to be set.
+func interpolate(raw interface{}, lookup func(string) (string, bool)) (interface{}, error) {
+	switch raw := raw.(type) {
+	case string:
+		var missing []string
+
+		replaced := envRefRe.ReplaceAllStringFunc(raw, func(ref string) string {
+			if ref == "$${" {
+				return "${"
+			}
+
+			name := ref[2 : len(ref)-1]
+
+			value, ok := lookup(name)
+			if !ok {
+				missing = append(missing, name)
+			}
+
+			return value
+		})
+
+		if len(missing) > 0 {
+			sort.Strings(missing)
+			return nil, fmt.Errorf("environment variable %s is not set", strings.Join(missing, ", "))
+		}
+
+		return replaced, nil
+	case []interface{}:
+		for i, v := range raw {
+			interpolated, err := interpolate(v, lookup)
+			if err != nil {
+				return nil, err
+			}
+
+			raw[i] = interpolated
+		}
+	case []map[string]interface{}:
+		for _, m := range raw {
+			if _, err := interpolate(m, lookup); err != nil {
+				return nil, err
+			}
+		}
+	case map[string]interface{}:
+		for k, v := range raw {
+			interpolated, err := interpolate(v, lookup)
+			if err != nil {
+				return nil, err
+			}
+
+			raw[k] = interpolated
+		}
+	case map[interface{}]interface{}:
+		for k, v := range raw {
+			interpolated, err := interpolate(v, lookup)
+			if err != nil {
+				return nil, err
+			}
+
+			raw[k] = interpolated
+		}
+	}
+
+	return raw, nil
+}
@@ -0,0 +1,116 @@
+package config_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/matoous/godox/config"
+)
+
+func TestLoadEnv(t *testing.T) {
+	t.Parallel()
+
+	if name := config.EnvName("github.token-env"); name != "GODOX_GITHUB_TOKEN_ENV" {
+		t.Errorf("not equal\nexpected: %s\nactual: %s", "GODOX_GITHUB_TOKEN_ENV", name)
+	}
+
+	env := map[string]string{
+		"GODOX_KEYWORDS":          "[BUG]",
+		"GODOX_SEVERITIES":        "{BUG: info}",
+		"GODOX_REQUIRE_OWNER":     "true",
+		"GODOX_CONCURRENCY":       "4",
+		"GODOX_GITHUB_REPOSITORY": "alice/project",
+		"GODOX_JIRA_PROJECT":      "123",
+		"GODOX_CACHE_DIR":         "cache",
+	}
+
+	lookup := func(name string) (string, bool) {
+		value, ok := env[name]
+		return value, ok
+	}
+
+	settings, err := config.Load("testdata/load/settings.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	settings.GitHub.APIURL = "https://github.example.com/api/v3"
+
+	if err := settings.LoadEnv(lookup); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &config.GoDoxSettings{
+		Format:   true,
+		Keywords: []string{"BUG"},
+		FormatRules: []config.GoDoxFormatRule{
+			{Keyword: "TODO", RegularExpression: `^TODO\(\w+\)`},
+		},
+		Severities:   map[string]string{"BUG": "info"},
+		OwnerRoster:  filepath.Join("testdata", "load", "CODEOWNERS"),
+		RequireOwner: true,
+		Concurrency:  4,
+		GitHub:       config.GitHubSettings{Repository: "alice/project", APIURL: "https://github.example.com/api/v3"},
+		Jira:         config.JiraSettings{Project: "123"},
+		CacheDir:     "cache",
+	}
+
+	if !reflect.DeepEqual(settings, expected) {
+		t.Errorf("not equal\nexpected: %+v\nactual: %+v", expected, settings)
+	}
+
+	env = map[string]string{"GODOX_CONCURRENCY": "many"}
+
+	if err := settings.LoadEnv(lookup); err == nil {
+		t.Error("expected error for invalid value")
+	}
+
+	env = map[string]string{"GODOX_KEYWORDS": "[TODO"}
+
+	if err := settings.LoadEnv(lookup); err == nil {
+		t.Error("expected error for invalid YAML")
+	}
+}
+
+func TestLoadFileInterpolation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "godox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	const name = "GODOX_TEST_INTERPOLATION_REPOSITORY"
+
+	if err := os.Setenv(name, "alice/project"); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Unsetenv(name)
+
+	path := filepath.Join(dir, ".godox.yml")
+	content := "github:\n  repository: ${" + name + "}\n" +
+		"format-rules:\n  - keyword: TODO\n    fix-pattern: '^TODO (?P<text>.*)$'\n    fix: 'TODO: ${text} $${" + name + "}'\n"
+
+	if err := ioutil.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	settings, err := config.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if settings.GitHub.Repository != "alice/project" || settings.FormatRules[0].Fix != "TODO: ${text} ${"+name+"}" {
+		t.Errorf("unexpected settings %+v", settings)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("owner-roster: ${GODOX_TEST_UNSET_VARIABLE}/CODEOWNERS\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := config.Load(path); err == nil {
+		t.Error("expected error for unset variable")
+	}
+}
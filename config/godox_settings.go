@@ -1,12 +1,240 @@
 package config
 
+// Deadline modes.
+const (
+	// DeadlineModeExpired reports only comments with expired deadlines instead of all of them.
+	DeadlineModeExpired = "expired"
+	// DeadlineModeEscalate reports comments as usual, escalating severity of those with expired deadlines.
+	DeadlineModeEscalate = "escalate"
+)
+
+// Engines.
+const (
+	// EngineParser parses the files using go/parser, files with syntax errors can't be scanned.
+	EngineParser = "parser"
+	// EngineFast tokenizes only the comments using go/scanner without building the syntax trees,
+	// files are scanned even if they contain syntax errors.
+	EngineFast = "fast"
+)
+
+// Severities.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityInfo    = "info"
+)
+
+// Priorities parsed from the comments, e.g. TODO(P1), from the highest to the lowest. The exclamation marks
+// following the keyword are priorities too, e.g. TODO!! is P0 and TODO! is P1.
+var Priorities = []string{"P0", "P1", "P2", "P3"}
+
 type GoDoxSettings struct {
-	Format      bool
+	Format bool `mapstructure:"format"`
+	// Combined validates the format of comments with keywords of the FormatRules, as in the format mode, and reports
+	// comments with other keywords as usual in a single pass.
+	Combined    bool              `mapstructure:"combined"`
 	Keywords    []string          `mapstructure:"keywords"`
 	FormatRules []GoDoxFormatRule `mapstructure:"format-rules"`
+	// Aliases map keywords to the configured keywords they are reported as, e.g. HACK: FIXME.
+	// No aliases are matched unless configured, see BuiltinAliases for the common ones.
+	Aliases map[string]string `mapstructure:"aliases"`
+	// Severities maps keywords to the severity of their findings, e.g. FIXME: error.
+	// Keywords without a severity are reported as warnings.
+	Severities map[string]string `mapstructure:"severities"`
+	// ForbiddenKeywords are reported as errors with the forbidden-keyword rule wherever they occur, regardless
+	// of the format rules and policies, e.g. to allow TODOs with issue references but ban FIXMEs outright.
+	// Aliases of the forbidden keywords are forbidden too, keywords which aren't configured are matched as well.
+	ForbiddenKeywords []string `mapstructure:"forbidden-keywords"`
+	// Anywhere matches keywords anywhere in the comment lines at word boundaries instead of only
+	// at the start of the lines. Format rules are always matched at the start of the lines.
+	Anywhere bool `mapstructure:"anywhere"`
+	// TrimListMarkers skips the markers of the Markdown list items at the start of the comment lines, so the
+	// keywords of the items, e.g. "- TODO: handle timeouts" or "1. FIXME retry logic", are matched.
+	TrimListMarkers bool `mapstructure:"trim-list-markers"`
+	// ReportAllMatches reports every keyword found in the comment lines starting with one, or in all lines if
+	// Anywhere is set, and every format rule the lines violate, instead of only the first ones.
+	ReportAllMatches bool `mapstructure:"report-all-matches"`
+	// Multiline merges the lines continuing the comment lines starting with keywords into single findings, so
+	// their Text contains the whole paragraph: the following lines of block comments, and the more indented line
+	// comments following line comments, up to an empty line or a line starting with a keyword.
+	Multiline bool `mapstructure:"multiline"`
+	// CaseSensitive matches keywords only if their case is the same as in the configuration.
+	CaseSensitive bool `mapstructure:"case-sensitive"`
+	// CaseSensitiveKeywords overrides CaseSensitive for the keywords, e.g. TODO: true.
+	CaseSensitiveKeywords map[string]bool `mapstructure:"case-sensitive-keywords"`
+	// RequireIssueReference reports keyword comments which don't reference an issue
+	// instead of reporting all of them.
+	RequireIssueReference bool `mapstructure:"require-issue-reference"`
+	// ForbidExportedDocs reports all keyword comments in the doc comments of the package and its exported
+	// declarations, which are published with the package documentation, with the exported-doc rule
+	// and the ExportedDocsSeverity, regardless of the format rules and policies.
+	ForbidExportedDocs bool `mapstructure:"forbid-exported-docs"`
+	// ExportedDocsSeverity is the severity of the exported-doc findings, SeverityError is used when empty.
+	ExportedDocsSeverity string `mapstructure:"exported-docs-severity"`
+	// ScanStrings reports the keywords found anywhere in the string literals, e.g. in embedded SQL or templates,
+	// with the string-literal rule. Import paths and struct tags are not scanned. The literals are found only
+	// by the parser engine.
+	ScanStrings bool `mapstructure:"scan-strings"`
+	// MinDescriptionLength maps keywords to the minimum length of the description following the keyword and
+	// the owner, e.g. TODO: 10, comments of the keywords with shorter descriptions are reported instead of all
+	// of them. Aliases without a length have the length of their canonical keyword.
+	MinDescriptionLength map[string]int `mapstructure:"min-description-length"`
+	// IssuePatterns recognize the issue references, DefaultIssuePatterns are used when empty.
+	IssuePatterns []IssuePattern `mapstructure:"issue-patterns"`
+	// CheckIssues looks up the referenced issues in their trackers and reports comments referencing
+	// closed issues instead of all of them.
+	CheckIssues bool `mapstructure:"check-issues"`
+	// ReportMissingIssues reports comments referencing issues which don't exist too when checking the issues.
+	ReportMissingIssues bool `mapstructure:"report-missing-issues"`
+	// GitHub configures the lookups of GitHub issues.
+	GitHub GitHubSettings `mapstructure:"github"`
+	// GitLab configures the lookups of GitLab issues.
+	GitLab GitLabSettings `mapstructure:"gitlab"`
+	// Jira configures the lookups of Jira issues.
+	Jira JiraSettings `mapstructure:"jira"`
+	// TrackerCommands look up issues of custom trackers, or replace the built-in ones, by running commands.
+	TrackerCommands []TrackerCommand `mapstructure:"tracker-commands"`
+	// RequireOwner reports keyword comments which don't specify an owner, e.g. TODO(alice),
+	// instead of reporting all of them.
+	RequireOwner bool `mapstructure:"require-owner"`
+	// OwnerPattern matches the owner in the text following the keyword, the first group of the regular
+	// expression, or the whole match if there is none, is the owner. DefaultOwnerPattern is used when empty.
+	OwnerPattern string `mapstructure:"owner-pattern"`
+	// OwnerRoster is a path to file with known owners, see LoadRoster for supported formats.
+	// When set, comments with owners which are not in the roster are reported instead of all of them.
+	OwnerRoster string `mapstructure:"owner-roster"`
+	// MinPriority reports only comments with the priority, e.g. P1, or a higher one, P0 being the highest.
+	// Comments without priorities are left out. See Priorities.
+	MinPriority string `mapstructure:"min-priority"`
+	// Labels report only comments with at least one of the labels, compared case-insensitively, e.g. perf
+	// for TODO: speed up #perf. The comments are reported regardless of their labels when empty.
+	Labels []string `mapstructure:"labels"`
+	// DeadlineMode controls handling of deadlines, such as TODO(2025-06-01), found in the comments.
+	// Deadlines are only parsed by default, see the DeadlineMode constants.
+	DeadlineMode string `mapstructure:"deadline-mode"`
+	// DeadlineLayout is the time.Parse layout of the deadlines, DefaultDeadlineLayout is used when empty.
+	DeadlineLayout string `mapstructure:"deadline-layout"`
+	// DeadlinePattern finds deadline candidates which are then parsed using the DeadlineLayout,
+	// DefaultDeadlinePattern is used when empty.
+	DeadlinePattern string `mapstructure:"deadline-pattern"`
+	// OlderThan reports only comments introduced longer ago than the age, e.g. 180d, according to git blame.
+	// See ParseAge for the supported ages.
+	OlderThan string `mapstructure:"older-than"`
+	// EscalateAfter escalates severity of comments introduced longer ago than the age, e.g. 90d,
+	// according to git blame to error.
+	EscalateAfter string `mapstructure:"escalate-after"`
+	// IncludePaths are glob patterns of the files to scan, all files are scanned when empty.
+	// Patterns support ** and {a,b}, patterns not starting with / match any trailing elements of the paths.
+	IncludePaths []string `mapstructure:"include-paths"`
+	// ExcludePaths are glob patterns of the files to skip, e.g. vendor/** or **/testdata/**.
+	// Excluded files are skipped even if they match the IncludePaths.
+	ExcludePaths []string `mapstructure:"exclude-paths"`
+	// Include are glob patterns of other than Go files whose comments are scanned too, e.g. *.proto, *.sql
+	// or Dockerfile. The comment syntax is chosen by the name of the files, unknown files are scanned for the #,
+	// // and /* */ comments. The patterns are the same as of the IncludePaths.
+	Include []string `mapstructure:"include"`
+	// Budgets map glob patterns of the files to the numbers of findings allowed in them in total,
+	// e.g. pkg/payments/**: 0. The patterns are the same as of the IncludePaths.
+	Budgets map[string]int `mapstructure:"budgets"`
+	// SkipGenerated skips files with the standard "// Code generated ... DO NOT EDIT." comment.
+	SkipGenerated bool `mapstructure:"skip-generated"`
+	// SkipLicenseHeaders skips the license headers, the comments preceding the package clause with copyright
+	// or license markers, such as "Copyright" or "SPDX-License-Identifier:". Only the first comment of the files
+	// without package clause, e.g. assembly files, can be a license header.
+	SkipLicenseHeaders bool `mapstructure:"skip-license-headers"`
+	// BuildTags are additional build tags used when loading packages.
+	BuildTags []string `mapstructure:"build-tags"`
+	// Tests enables scanning of test files when loading packages.
+	Tests bool `mapstructure:"tests"`
+	// Concurrency is the number of files scanned in parallel, GOMAXPROCS is used when not set.
+	Concurrency int `mapstructure:"concurrency"`
+	// Deduplicate reports identical findings at the same position once, e.g. when the same file is scanned
+	// multiple times with different build tags.
+	Deduplicate bool `mapstructure:"deduplicate"`
+	// Engine reads the comments of the files, see the Engine constants. EngineParser is used when empty.
+	Engine string `mapstructure:"engine"`
+	// ConfigRoot enables nested configuration files, the configuration files in the subdirectories
+	// of the root are loaded over the settings for the files in them, see Hierarchy.
+	ConfigRoot string `mapstructure:"-"`
+	// CacheDir is the directory of the cache of results, caching is disabled when empty.
+	CacheDir string `mapstructure:"cache-dir"`
+	// MaxMessageLength is the number of runes of the comment lines quoted in the messages, longer lines are
+	// truncated, 0 disables the truncation and DefaultMaxMessageLength is used when nil. The Text of the messages
+	// always contains the whole line.
+	MaxMessageLength *int `mapstructure:"max-message-length"`
+	// MessageTemplate is a text/template of the messages following the file name and line, executed with
+	// the godox.Message, e.g. "{{.Keyword}} in {{.Declaration}}: {{.Text}}". The default messages are used when empty.
+	MessageTemplate string `mapstructure:"message-template"`
+	// DisabledRules are IDs of format rules and built-in rules, such as issue or deadline, which are not reported.
+	DisabledRules []string `mapstructure:"disabled-rules"`
+	// ReportSuppressed enables reporting of findings suppressed by nolint or godox:ignore directives.
+	ReportSuppressed bool `mapstructure:"report-suppressed"`
 }
 
 type GoDoxFormatRule struct {
-	Keyword           string
-	RegularExpression string
+	// ID identifies the rule in the findings, DisabledRules and godox:disable directives,
+	// format-N, where N is the index of the rule, is used when empty.
+	ID string `mapstructure:"id"`
+	// Keyword the comment lines start with, DefaultFormatRuleKeyword for the default rule of the keywords
+	// without rules of their own.
+	Keyword           string `mapstructure:"keyword"`
+	RegularExpression string `mapstructure:"regular-expression"`
+	// Forbid is a regular expression the comment lines must not match, e.g. internal host names or "temporary".
+	// Rules with only the forbidden pattern don't check the format.
+	Forbid string `mapstructure:"forbid"`
+	// FixPattern matches the violating comment lines which can be fixed, the lines are rewritten
+	// to the Fix template, which can refer to the capture groups of the pattern as $1 or ${name}.
+	// Lines are rewritten to the canonical "KEYWORD(owner): text" form when the pattern is empty.
+	FixPattern string `mapstructure:"fix-pattern"`
+	Fix        string `mapstructure:"fix"`
+}
+
+// GitHubSettings configure the lookups of GitHub issues.
+type GitHubSettings struct {
+	// Repository, owner/repo, of references without a repository, e.g. #123.
+	// The GITHUB_REPOSITORY environment variable is used when empty.
+	Repository string `mapstructure:"repository"`
+	// APIURL is the URL of the GitHub API, DefaultGitHubAPIURL is used when empty.
+	APIURL string `mapstructure:"api-url"`
+	// TokenEnv is the environment variable with the API token, GITHUB_TOKEN is used when empty.
+	TokenEnv string `mapstructure:"token-env"`
+}
+
+// GitLabSettings configure the lookups of GitLab issues of the gitlab tracker and GitLab issue URLs.
+type GitLabSettings struct {
+	// Project, group/project, of references without a project, e.g. #123.
+	// The CI_PROJECT_PATH environment variable is used when empty.
+	Project string `mapstructure:"project"`
+	// URL of the GitLab instance, DefaultGitLabURL is used when empty.
+	URL string `mapstructure:"url"`
+	// TokenEnv is the environment variable with the API token, GITLAB_TOKEN is used when empty.
+	TokenEnv string `mapstructure:"token-env"`
+}
+
+// JiraSettings configure the lookups of Jira issues, the issues are looked up only if the URL is known.
+type JiraSettings struct {
+	// URL of the Jira instance, e.g. https://example.atlassian.net. The JIRA_URL environment variable is used when empty.
+	URL string `mapstructure:"url"`
+	// UserEnv is the environment variable with the user name or email, JIRA_USER is used when empty.
+	// The token is sent as a bearer token when there is no user, e.g. for personal access tokens.
+	UserEnv string `mapstructure:"user-env"`
+	// TokenEnv is the environment variable with the API token, JIRA_API_TOKEN is used when empty.
+	TokenEnv string `mapstructure:"token-env"`
+	// Project is the key of the project exported issues are created in.
+	Project string `mapstructure:"project"`
+	// IssueType of the exported issues, DefaultJiraIssueType is used when empty.
+	IssueType string `mapstructure:"issue-type"`
+}
+
+// TrackerCommand looks up issues of the tracker by running the command with the reference as the last argument.
+// The command prints the status of the issue: open, closed or missing.
+type TrackerCommand struct {
+	Tracker string   `mapstructure:"tracker"`
+	Command []string `mapstructure:"command"`
+}
+
+// IssuePattern recognizes issue references of an issue tracker.
+type IssuePattern struct {
+	Tracker           string `mapstructure:"tracker"`
+	RegularExpression string `mapstructure:"regular-expression"`
 }
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Hierarchy resolves settings of files from nested configuration files. The configuration file found
+// in each directory below the root, named one of the DefaultFiles, is loaded over the settings of its
+// parent directory, the root directory and the directories outside of it have the base settings.
+type Hierarchy struct {
+	root string
+	base *CompiledSettings
+
+	mu   sync.Mutex
+	dirs map[string]*level
+}
+
+// level are the settings of a directory.
+type level struct {
+	settings GoDoxSettings
+	// files are the configuration files loaded over the base settings, starting with the outermost
+	files    []string
+	compiled *CompiledSettings
+	err      error
+}
+
+// NewHierarchy returns hierarchy of the configuration files below the root directory with the base settings.
+func NewHierarchy(root string, base *CompiledSettings) (*Hierarchy, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Hierarchy{root: root, base: base, dirs: make(map[string]*level)}, nil
+}
+
+// Settings returns the effective settings of the file and the configuration files loaded over
+// the base settings to get them.
+func (h *Hierarchy) Settings(filename string) (GoDoxSettings, []string, error) {
+	l, err := h.level(filename)
+	if err != nil {
+		return GoDoxSettings{}, nil, err
+	}
+
+	return l.settings, append([]string(nil), l.files...), nil
+}
+
+// Compiled returns the compiled effective settings of the file.
+func (h *Hierarchy) Compiled(filename string) (*CompiledSettings, error) {
+	l, err := h.level(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.compiled, l.err
+}
+
+func (h *Hierarchy) level(filename string) (*level, error) {
+	path, err := filepath.Abs(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.dirLevel(filepath.Dir(path))
+}
+
+// dirLevel returns settings of the directory, h.mu must be held.
+func (h *Hierarchy) dirLevel(dir string) (*level, error) {
+	if l, ok := h.dirs[dir]; ok {
+		return l, nil
+	}
+
+	rel, err := filepath.Rel(h.root, dir)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		l := &level{settings: h.base.GoDoxSettings, compiled: h.base}
+		h.dirs[dir] = l
+
+		return l, nil
+	}
+
+	parent, err := h.dirLevel(filepath.Dir(dir))
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := findIn(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	l := parent
+	if path != "" {
+		l = &level{settings: parent.settings, files: append(append([]string(nil), parent.files...), path)}
+		l.settings.ConfigRoot = ""
+
+		// Errors of the parent configuration apply to the whole subtree.
+		if l.err = parent.err; l.err == nil {
+			if l.err = l.settings.LoadFile(path); l.err == nil {
+				l.compiled, l.err = l.settings.Compile()
+			}
+		}
+	}
+
+	h.dirs[dir] = l
+
+	return l, nil
+}
+
+// findIn returns the configuration file in the directory, the path is empty if there is none.
+func findIn(dir string) (string, error) {
+	for _, name := range DefaultFiles {
+		path := filepath.Join(dir, name)
+
+		info, err := os.Stat(path)
+		if err == nil && !info.IsDir() {
+			return path, nil
+		}
+
+		if err != nil && !os.IsNotExist(err) {
+			return "", err
+		}
+	}
+
+	return "", nil
+}
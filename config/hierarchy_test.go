@@ -0,0 +1,96 @@
+package config_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/matoous/godox/config"
+)
+
+func TestHierarchy(t *testing.T) {
+	t.Parallel()
+
+	base := config.GoDoxSettings{Keywords: []string{"TODO"}, ConfigRoot: "testdata/hierarchy"}
+
+	compiled, err := base.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		file         string
+		loaded       []string
+		keywords     []string
+		requireOwner bool
+		severity     string
+	}{
+		{file: "testdata/hierarchy/main.go", keywords: []string{"TODO"}, severity: config.SeverityWarning},
+		{file: "testdata/main.go", keywords: []string{"TODO"}, severity: config.SeverityWarning},
+		{
+			file:     "testdata/hierarchy/a/main.go",
+			loaded:   []string{"a/.godox.yml"},
+			keywords: []string{"BUG"},
+			severity: config.SeverityError,
+		},
+		{
+			file:         "testdata/hierarchy/a/b/c/main.go",
+			loaded:       []string{"a/.godox.yml", "a/b/.godox.toml"},
+			keywords:     []string{"BUG"},
+			requireOwner: true,
+			severity:     config.SeverityError,
+		},
+	}
+
+	for _, tt := range tests {
+		settings, loaded, err := compiled.Hierarchy().Settings(tt.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i := range loaded {
+			if loaded[i], err = filepath.Rel("testdata/hierarchy", relative(t, loaded[i])); err != nil {
+				t.Fatal(err)
+			}
+
+			loaded[i] = filepath.ToSlash(loaded[i])
+		}
+
+		if fmt.Sprint(loaded) != fmt.Sprint(tt.loaded) {
+			t.Errorf("%s: expected loaded files %v, got %v", tt.file, tt.loaded, loaded)
+		}
+
+		if fmt.Sprint(settings.Keywords) != fmt.Sprint(tt.keywords) || settings.RequireOwner != tt.requireOwner {
+			t.Errorf("%s: unexpected settings %+v", tt.file, settings)
+		}
+
+		fileSettings, err := compiled.ForFile(tt.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if severity := fileSettings.Severity("BUG"); severity != tt.severity {
+			t.Errorf("%s: expected severity %s, got %s", tt.file, tt.severity, severity)
+		}
+	}
+
+	for _, file := range []string{"testdata/hierarchy/invalid/main.go", "testdata/hierarchy/invalid/child/main.go"} {
+		if _, err := compiled.ForFile(file); err == nil {
+			t.Errorf("%s: expected error for invalid nested configuration", file)
+		}
+	}
+}
+
+func relative(t *testing.T, path string) string {
+	abs, err := filepath.Abs(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rel, err := filepath.Rel(abs, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return rel
+}
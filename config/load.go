@@ -0,0 +1,158 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/mitchellh/mapstructure"
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultFiles are the names of the configuration files looked up by Find, in the order of precedence.
+var DefaultFiles = []string{".godox.yml", ".godox.yaml", ".godox.toml", "godox.json", ".godox.json"}
+
+// Load reads the settings from the YAML, TOML or JSON file, the format is chosen by the file extension.
+func Load(path string) (*GoDoxSettings, error) {
+	settings := &GoDoxSettings{}
+	if err := settings.LoadFile(path); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// LoadFile reads the settings from the file over s, settings which are not in the file are kept.
+// The keys are the same as the mapstructure tags of the settings, e.g. format-rules, and relative
+// paths in the file are relative to the directory of the file. The ${VAR} references to environment
+// variables with upper case names in the values are replaced by the variables, $${ escapes them.
+func (s *GoDoxSettings) LoadFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var raw interface{}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yml", ".yaml":
+		err = yaml.Unmarshal(data, &raw)
+	case ".toml":
+		var m map[string]interface{}
+		_, err = toml.Decode(string(data), &m)
+		raw = m
+	case ".json":
+		err = json.Unmarshal(data, &raw)
+	default:
+		return fmt.Errorf("%s: unsupported configuration format %q", path, ext)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	// empty files decode to nil
+	if raw == nil {
+		return nil
+	}
+
+	if raw, err = interpolate(raw, os.LookupEnv); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	if err := s.decode(raw, filepath.Dir(path)); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	return nil
+}
+
+// decode decodes the raw settings keyed by the mapstructure tags over s, the relative paths are relative
+// to the directory.
+func (s *GoDoxSettings) decode(raw interface{}, dir string) error {
+	loaded := *s
+
+	raw = expandAliases(raw)
+
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		ErrorUnused: true,
+		// values in the file replace the settings, e.g. maps are not merged
+		ZeroFields: true,
+		Result:     &loaded,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := decoder.Decode(raw); err != nil {
+		return err
+	}
+
+	if loaded.OwnerRoster != s.OwnerRoster {
+		loaded.OwnerRoster = resolve(dir, loaded.OwnerRoster)
+	}
+
+	if loaded.CacheDir != s.CacheDir {
+		loaded.CacheDir = resolve(dir, loaded.CacheDir)
+	}
+
+	*s = loaded
+
+	return nil
+}
+
+// expandAliases replaces the BuiltinAliasesName value of the aliases of the raw settings with the BuiltinAliases.
+func expandAliases(raw interface{}) interface{} {
+	builtin := make(map[string]interface{}, len(BuiltinAliases))
+	for alias, keyword := range BuiltinAliases {
+		builtin[alias] = keyword
+	}
+
+	switch raw := raw.(type) {
+	case map[string]interface{}:
+		if raw["aliases"] == BuiltinAliasesName {
+			raw["aliases"] = builtin
+		}
+	case map[interface{}]interface{}:
+		if raw["aliases"] == BuiltinAliasesName {
+			raw["aliases"] = builtin
+		}
+	}
+
+	return raw
+}
+
+// Find returns the configuration file in the directory or the closest of its parents,
+// the path is empty if there is none.
+func Find(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		path, err := findIn(dir)
+		if err != nil || path != "" {
+			return path, err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", nil
+		}
+
+		dir = parent
+	}
+}
+
+func resolve(dir, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(dir, path)
+}
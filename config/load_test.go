@@ -0,0 +1,123 @@
+package config_test
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/matoous/godox/config"
+)
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	for _, path := range []string{"testdata/load/settings.yml", "testdata/load/settings.toml", "testdata/load/settings.json"} {
+		path := path
+		t.Run(path, func(t *testing.T) {
+			t.Parallel()
+
+			settings, err := config.Load(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := &config.GoDoxSettings{
+				Format:   true,
+				Keywords: []string{"TODO", "FIXME"},
+				FormatRules: []config.GoDoxFormatRule{
+					{Keyword: "TODO", RegularExpression: `^TODO\(\w+\)`},
+				},
+				Severities:  map[string]string{"FIXME": "error"},
+				OwnerRoster: filepath.Join("testdata", "load", "CODEOWNERS"),
+				Concurrency: 2,
+			}
+
+			if !reflect.DeepEqual(settings, expected) {
+				t.Errorf("not equal\nexpected: %+v\nactual: %+v", expected, settings)
+			}
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	t.Parallel()
+
+	settings := config.GoDoxSettings{Tests: true, Keywords: []string{"BUG"}}
+	if err := settings.LoadFile("testdata/load/nested/.godox.yml"); err != nil {
+		t.Fatal(err)
+	}
+
+	if !settings.Tests || !settings.RequireOwner || len(settings.Keywords) != 1 {
+		t.Errorf("expected the file to be loaded over the settings, got %+v", settings)
+	}
+
+	if err := settings.LoadFile("testdata/load/unknown.yml"); err == nil || !strings.Contains(err.Error(), "unknown-key") {
+		t.Errorf("expected error about unknown key, got %v", err)
+	}
+
+	if err := settings.LoadFile("testdata/roster.txt"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		dir      string
+		expected string
+	}{
+		{dir: "testdata/load/nested/deeper", expected: "testdata/load/nested/.godox.yml"},
+		{dir: "testdata/load/nested", expected: "testdata/load/nested/.godox.yml"},
+		{dir: "testdata/load", expected: ""},
+	}
+
+	for _, tt := range tests {
+		path, err := config.Find(tt.dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var expected string
+		if tt.expected != "" {
+			if expected, err = filepath.Abs(tt.expected); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if path != expected {
+			t.Errorf("%s: expected %q, got %q", tt.dir, expected, path)
+		}
+	}
+}
+
+func TestLoadBuiltinAliases(t *testing.T) {
+	t.Parallel()
+
+	for _, path := range []string{"testdata/aliases/builtin.yml", "testdata/aliases/builtin.toml", "testdata/aliases/builtin.json"} {
+		path := path
+		t.Run(path, func(t *testing.T) {
+			t.Parallel()
+
+			settings, err := config.Load(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			compiled, err := settings.Compile()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for alias, canonical := range map[string]string{
+				"XXX": "FIXME", "HACK": "FIXME", "NOTE": "TODO", "OPTIMIZE": "TODO", "WIP": "TODO",
+			} {
+				keyword, _, ok := compiled.MatchKeyword([]byte(alias + ": x"))
+				if !ok || compiled.Canonical(keyword) != canonical {
+					t.Errorf("%s: not equal\nexpected: %v\nactual: %v", alias, canonical, compiled.Canonical(keyword))
+				}
+			}
+		})
+	}
+}
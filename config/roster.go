@@ -0,0 +1,66 @@
+package config
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LoadRoster reads list of known owners from the file. The format is detected from the file name:
+//
+//   - CODEOWNERS files, owners are the @user, @org/team or e-mail entries following the paths,
+//   - YAML files (.yml, .yaml) containing a list of owners, one "- owner" item per line,
+//   - plain text files containing one owner per line.
+//
+// Empty lines and lines starting with # are ignored. Leading @ is stripped from the owners and they
+// are returned lower cased as the owner names are compared case insensitively.
+func LoadRoster(path string) (map[string]struct{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	base := filepath.Base(path)
+	ext := filepath.Ext(path)
+	roster := make(map[string]struct{})
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var owners []string
+
+		switch {
+		case base == "CODEOWNERS":
+			// the first field is the path pattern
+			owners = strings.Fields(line)[1:]
+		case ext == ".yml" || ext == ".yaml":
+			if !strings.HasPrefix(line, "- ") {
+				continue
+			}
+
+			owners = []string{strings.Trim(strings.TrimSpace(line[2:]), `"'`)}
+		default:
+			owners = []string{line}
+		}
+
+		for _, owner := range owners {
+			if strings.HasPrefix(owner, "#") {
+				break
+			}
+
+			roster[strings.ToLower(strings.TrimPrefix(owner, "@"))] = struct{}{}
+		}
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	return roster, nil
+}
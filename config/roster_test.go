@@ -0,0 +1,44 @@
+package config_test
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/matoous/godox/config"
+)
+
+func TestLoadRoster(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path   string
+		owners []string
+	}{
+		{path: "testdata/CODEOWNERS", owners: []string{"alice", "bob", "docs@example.com", "org/backend"}},
+		{path: "testdata/roster.yml", owners: []string{"alice", "bob"}},
+		{path: "testdata/roster.txt", owners: []string{"alice", "carol"}},
+	}
+
+	for _, tt := range tests {
+		roster, err := config.LoadRoster(tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		owners := make([]string, 0, len(roster))
+		for owner := range roster {
+			owners = append(owners, owner)
+		}
+
+		sort.Strings(owners)
+
+		if fmt.Sprint(owners) != fmt.Sprint(tt.owners) {
+			t.Errorf("%s: expected owners %v, got %v", tt.path, tt.owners, owners)
+		}
+	}
+
+	if _, err := config.LoadRoster("testdata/nonexistent"); err == nil {
+		t.Error("expected error for nonexistent file")
+	}
+}
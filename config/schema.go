@@ -0,0 +1,85 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// SchemaID is the JSON Schema dialect of the Schema.
+const SchemaID = "http://json-schema.org/draft-07/schema#"
+
+// schemaEnums are the allowed values of the settings by the paths of their keys, e.g. github.repository,
+// the values of the maps are at the path of the map.
+var schemaEnums = map[string][]string{
+	"severities":             {SeverityError, SeverityWarning, SeverityInfo},
+	"exported-docs-severity": {SeverityError, SeverityWarning, SeverityInfo},
+	"deadline-mode":          {"", DeadlineModeExpired, DeadlineModeEscalate},
+	"engine":                 {"", EngineParser, EngineFast},
+	"min-priority":           append([]string{""}, Priorities...),
+}
+
+// Schema returns the JSON Schema of the configuration files, e.g. for the completion and validation of the files
+// by editors. It is produced from the fields of GoDoxSettings, so it describes the same keys LoadFile accepts.
+func Schema() map[string]interface{} {
+	schema := typeSchema(reflect.TypeOf(GoDoxSettings{}), "")
+	schema["$schema"] = SchemaID
+
+	// the aliases can be the name of the built-in ones too
+	properties := schema["properties"].(map[string]interface{})
+	properties["aliases"] = map[string]interface{}{"oneOf": []interface{}{
+		properties["aliases"],
+		map[string]interface{}{"type": "string", "enum": []string{BuiltinAliasesName}},
+	}}
+
+	schema["title"] = "godox configuration"
+
+	return schema
+}
+
+// typeSchema returns the schema of the values of the type at the path of the keys.
+func typeSchema(t reflect.Type, path string) map[string]interface{} {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	var schema map[string]interface{}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		properties := make(map[string]interface{}, t.NumField())
+
+		for i := 0; i < t.NumField(); i++ {
+			key := t.Field(i).Tag.Get("mapstructure")
+			if key == "-" {
+				continue
+			}
+
+			if key == "" {
+				key = strings.ToLower(t.Field(i).Name)
+			}
+
+			properties[key] = typeSchema(t.Field(i).Type, strings.TrimPrefix(path+"."+key, "."))
+		}
+
+		return map[string]interface{}{"type": "object", "properties": properties, "additionalProperties": false}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": typeSchema(t.Elem(), path)}
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem(), path)}
+	case reflect.Bool:
+		schema = map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		schema = map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		schema = map[string]interface{}{"type": "number"}
+	default:
+		schema = map[string]interface{}{"type": "string"}
+	}
+
+	if enum, ok := schemaEnums[path]; ok {
+		schema["enum"] = enum
+	}
+
+	return schema
+}
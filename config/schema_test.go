@@ -0,0 +1,135 @@
+package config_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/matoous/godox/config"
+	"gopkg.in/yaml.v2"
+)
+
+func TestSchema(t *testing.T) {
+	t.Parallel()
+
+	// the schema is encoded as JSON and decoded again, as read by the editors
+	data, err := json.Marshal(config.Schema())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var schema map[string]interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		file string
+		yaml string
+		err  string
+	}{
+		{name: "settings", file: "testdata/load/settings.yml"},
+		{name: "unknown key", file: "testdata/load/unknown.yml", err: "unknown-key: unknown key"},
+		{name: "nested", yaml: "github: {repository: a/b}\nmax-message-length: 10\nbudgets: {pkg/**: 0}\n"},
+		{name: "unknown severity", yaml: "severities: {FIXME: fatal}\n", err: "severities.FIXME: \"fatal\" is not one of [error warning info]"},
+		{name: "wrong type", yaml: "format-rules: [{keyword: TODO, fix: [a]}]\n", err: "format-rules.0.fix: expected string"},
+	}
+
+	for _, tt := range tests {
+		tt := tt //nolint // reason tt is ok on this context
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			content := []byte(tt.yaml)
+			if tt.file != "" {
+				var err error
+				if content, err = ioutil.ReadFile(tt.file); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			var value interface{}
+			if err := yaml.Unmarshal(content, &value); err != nil {
+				t.Fatal(err)
+			}
+
+			var actual string
+			if err := validateSchema(schema, value, ""); err != nil {
+				actual = err.Error()
+			}
+
+			if actual != tt.err {
+				t.Errorf("not equal\nexpected: %q\nactual: %q", tt.err, actual)
+			}
+		})
+	}
+}
+
+// validateSchema validates the value against the subset of JSON Schema used by config.Schema.
+func validateSchema(schema map[string]interface{}, value interface{}, path string) error {
+	if enum, ok := schema["enum"].([]interface{}); ok {
+		for _, allowed := range enum {
+			if allowed == value {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("%s: %q is not one of %v", path, value, enum)
+	}
+
+	switch schema["type"] {
+	case "object":
+		m, ok := value.(map[interface{}]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected object", path)
+		}
+
+		properties, _ := schema["properties"].(map[string]interface{})
+
+		for k, v := range m {
+			key := fmt.Sprint(k)
+
+			sub, ok := properties[key].(map[string]interface{})
+			if !ok {
+				if sub, ok = schema["additionalProperties"].(map[string]interface{}); !ok {
+					return fmt.Errorf("%s: unknown key", key)
+				}
+			}
+
+			if path != "" {
+				key = path + "." + key
+			}
+
+			if err := validateSchema(sub, v, key); err != nil {
+				return err
+			}
+		}
+	case "array":
+		list, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expected array", path)
+		}
+
+		for i, v := range list {
+			if err := validateSchema(schema["items"].(map[string]interface{}), v, fmt.Sprintf("%s.%d", path, i)); err != nil {
+				return err
+			}
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("%s: expected string", path)
+		}
+	case "integer":
+		if _, ok := value.(int); !ok {
+			return fmt.Errorf("%s: expected integer", path)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s: expected boolean", path)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Validate compiles the settings and checks the keywords for mistakes Compile tolerates, such as duplicate
+// keywords or aliases of configured keywords, returning all problems found. The settings are valid if there
+// are none. Only the error of Compile is returned when the settings cannot be compiled.
+func (s *GoDoxSettings) Validate() []error {
+	compiled, err := s.Compile()
+	if err != nil {
+		return []error{err}
+	}
+
+	var problems []error
+
+	known := make(map[string]bool)
+
+	for i, kw := range compiled.Keywords {
+		upper := strings.ToUpper(kw)
+
+		switch {
+		case strings.TrimSpace(kw) == "":
+			problems = append(problems, fmt.Errorf("keyword %d: empty keyword", i))
+		case strings.IndexFunc(kw, unicode.IsSpace) >= 0:
+			problems = append(problems, fmt.Errorf("keyword %d: keyword %q contains white space", i, kw))
+		case known[upper]:
+			problems = append(problems, fmt.Errorf("keyword %d: duplicate keyword %s", i, kw))
+		}
+
+		known[upper] = true
+	}
+
+	aliases := make([]string, 0, len(s.Aliases))
+	for alias := range s.Aliases {
+		aliases = append(aliases, alias)
+	}
+
+	sort.Strings(aliases)
+
+	seen := make(map[string]string, len(aliases))
+
+	for _, alias := range aliases {
+		upper := strings.ToUpper(alias)
+
+		switch {
+		case strings.TrimSpace(alias) == "":
+			problems = append(problems, fmt.Errorf("alias of %s: empty alias", s.Aliases[alias]))
+		case known[upper]:
+			problems = append(problems, fmt.Errorf("alias %s: the alias is a configured keyword", alias))
+		case seen[upper] != "":
+			problems = append(problems, fmt.Errorf("alias %s: duplicate of alias %s", alias, seen[upper]))
+		}
+
+		if seen[upper] == "" {
+			seen[upper] = alias
+		}
+	}
+
+	// the keywords matched by the compiled settings
+	matched := make(map[string]bool)
+
+	for _, kw := range compiled.Keywords {
+		matched[strings.ToUpper(kw)] = true
+	}
+
+	for alias := range compiled.aliases {
+		matched[alias] = true
+	}
+
+	for kw := range compiled.forbidden {
+		matched[kw] = true
+	}
+
+	for _, m := range []struct {
+		name     string
+		keywords []string
+	}{
+		{name: "severity", keywords: mapKeys(s.Severities)},
+		{name: "minimum description length", keywords: mapKeys(s.MinDescriptionLength)},
+		{name: "case sensitivity", keywords: mapKeys(s.CaseSensitiveKeywords)},
+	} {
+		for _, kw := range m.keywords {
+			if !matched[strings.ToUpper(kw)] {
+				problems = append(problems, fmt.Errorf("%s of %s: unknown keyword", m.name, kw))
+			}
+		}
+	}
+
+	return problems
+}
+
+// mapKeys returns the sorted keys of the map with string keys.
+func mapKeys(m interface{}) []string {
+	var keys []string
+
+	switch m := m.(type) {
+	case map[string]string:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]int:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	case map[string]bool:
+		for k := range m {
+			keys = append(keys, k)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
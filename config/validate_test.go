@@ -0,0 +1,81 @@
+package config_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/matoous/godox/config"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		settings config.GoDoxSettings
+		problems []string
+	}{
+		{
+			name: "valid",
+			settings: config.GoDoxSettings{
+				Keywords:          []string{"TODO", "FIXME"},
+				Aliases:           map[string]string{"HACK": "FIXME"},
+				Severities:        map[string]string{"hack": config.SeverityError, "XXX": config.SeverityInfo},
+				ForbiddenKeywords: []string{"XXX"},
+			},
+		},
+		{
+			name: "invalid regular expression",
+			settings: config.GoDoxSettings{
+				Keywords:    []string{"TODO", "TODO"},
+				FormatRules: []config.GoDoxFormatRule{{Keyword: "TODO", RegularExpression: `^TODO(`}},
+			},
+			problems: []string{"format rule 0 (TODO): error parsing regexp: missing closing ): `^TODO(`"},
+		},
+		{
+			name:     "keywords",
+			settings: config.GoDoxSettings{Keywords: []string{"TODO", " ", "todo", "NOTE IT"}},
+			problems: []string{
+				"keyword 1: empty keyword",
+				"keyword 2: duplicate keyword todo",
+				`keyword 3: keyword "NOTE IT" contains white space`,
+			},
+		},
+		{
+			name: "aliases",
+			settings: config.GoDoxSettings{
+				Keywords: []string{"TODO", "FIXME"},
+				Aliases:  map[string]string{"HACK": "FIXME", "hack": "TODO", "todo": "FIXME"},
+			},
+			problems: []string{"alias hack: duplicate of alias HACK", "alias todo: the alias is a configured keyword"},
+		},
+		{
+			name: "unknown keywords",
+			settings: config.GoDoxSettings{
+				Severities:            map[string]string{"FIXME": config.SeverityError, "REVIEW": config.SeverityInfo},
+				MinDescriptionLength:  map[string]int{"LATER": 10},
+				CaseSensitiveKeywords: map[string]bool{"todo": true},
+			},
+			problems: []string{
+				"severity of REVIEW: unknown keyword",
+				"minimum description length of LATER: unknown keyword",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt //nolint // reason tt is ok on this context
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var actual []string
+			for _, problem := range tt.settings.Validate() {
+				actual = append(actual, problem.Error())
+			}
+
+			if fmt.Sprint(actual) != fmt.Sprint(tt.problems) {
+				t.Errorf("not equal\nexpected: %q\nactual: %q", tt.problems, actual)
+			}
+		})
+	}
+}
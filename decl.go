@@ -0,0 +1,192 @@
+package godox
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// Declaration kinds.
+const (
+	DeclarationFunc   = "func"
+	DeclarationMethod = "method"
+	DeclarationType   = "type"
+	DeclarationVar    = "var"
+	DeclarationConst  = "const"
+)
+
+// enclosingDeclaration returns the name and kind of the top level declaration containing the position,
+// including its doc comment and the comments following it on its last line, empty outside of declarations.
+func enclosingDeclaration(file *ast.File, fset *token.FileSet, pos token.Pos) (name, kind string) {
+	// the declarations are sorted by their positions and don't overlap
+	i := sort.Search(len(file.Decls), func(i int) bool {
+		start, _ := declRange(file.Decls[i])
+		return start > pos
+	}) - 1
+
+	if i < 0 {
+		return "", ""
+	}
+
+	if _, end := declRange(file.Decls[i]); pos > end && fset.Position(pos).Line != fset.Position(end).Line {
+		return "", ""
+	}
+
+	switch d := file.Decls[i].(type) {
+	case *ast.FuncDecl:
+		if d.Recv == nil || len(d.Recv.List) == 0 {
+			return d.Name.Name, DeclarationFunc
+		}
+
+		recv := types.ExprString(d.Recv.List[0].Type)
+		if strings.HasPrefix(recv, "*") {
+			recv = "(" + recv + ")"
+		}
+
+		return recv + "." + d.Name.Name, DeclarationMethod
+	case *ast.GenDecl:
+		for _, s := range d.Specs {
+			// the comments of an ungrouped declaration belong to its only spec
+			if start, end := specRange(s); d.Lparen.IsValid() && (pos < start || pos > end) {
+				continue
+			}
+
+			switch s := s.(type) {
+			case *ast.TypeSpec:
+				return s.Name.Name, DeclarationType
+			case *ast.ValueSpec:
+				kind := DeclarationVar
+				if d.Tok == token.CONST {
+					kind = DeclarationConst
+				}
+
+				names := make([]string, len(s.Names))
+				for j, n := range s.Names {
+					names[j] = n.Name
+				}
+
+				return strings.Join(names, ", "), kind
+			}
+		}
+	}
+
+	return "", ""
+}
+
+// declRange returns the start of the declaration including its doc comment and its end
+// including the line comments of its specs.
+func declRange(decl ast.Decl) (start, end token.Pos) {
+	start, end = decl.Pos(), decl.End()
+
+	switch d := decl.(type) {
+	case *ast.FuncDecl:
+		if d.Doc != nil {
+			start = d.Doc.Pos()
+		}
+	case *ast.GenDecl:
+		if d.Doc != nil {
+			start = d.Doc.Pos()
+		}
+
+		for _, s := range d.Specs {
+			if _, e := specRange(s); e > end {
+				end = e
+			}
+		}
+	}
+
+	return start, end
+}
+
+// specRange returns the range of the spec including its doc and line comments.
+func specRange(spec ast.Spec) (start, end token.Pos) {
+	start, end = spec.Pos(), spec.End()
+
+	var doc, comment *ast.CommentGroup
+
+	switch s := spec.(type) {
+	case *ast.TypeSpec:
+		doc, comment = s.Doc, s.Comment
+	case *ast.ValueSpec:
+		doc, comment = s.Doc, s.Comment
+	case *ast.ImportSpec:
+		doc, comment = s.Doc, s.Comment
+	}
+
+	if doc != nil {
+		start = doc.Pos()
+	}
+
+	if comment != nil {
+		end = comment.End()
+	}
+
+	return start, end
+}
+
+// exportedDocs returns the doc comments of the package and its exported top level declarations,
+// which are published with the package documentation. Methods are exported if their receiver type is.
+func exportedDocs(file *ast.File) map[*ast.CommentGroup]bool {
+	docs := make(map[*ast.CommentGroup]bool)
+
+	if file.Doc != nil {
+		docs[file.Doc] = true
+	}
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Doc != nil && d.Name.IsExported() && exportedReceiver(d.Recv) {
+				docs[d.Doc] = true
+			}
+		case *ast.GenDecl:
+			for _, s := range d.Specs {
+				var (
+					doc      *ast.CommentGroup
+					exported bool
+				)
+
+				switch s := s.(type) {
+				case *ast.TypeSpec:
+					doc, exported = s.Doc, s.Name.IsExported()
+				case *ast.ValueSpec:
+					doc = s.Doc
+
+					for _, n := range s.Names {
+						exported = exported || n.IsExported()
+					}
+				}
+
+				if !exported {
+					continue
+				}
+
+				if doc != nil {
+					docs[doc] = true
+				}
+
+				if d.Doc != nil {
+					docs[d.Doc] = true
+				}
+			}
+		}
+	}
+
+	return docs
+}
+
+// exportedReceiver reports whether the receiver type is exported, functions without receiver have one.
+func exportedReceiver(recv *ast.FieldList) bool {
+	if recv == nil || len(recv.List) == 0 {
+		return true
+	}
+
+	name := strings.TrimLeft(types.ExprString(recv.List[0].Type), "*(")
+	if i := strings.IndexByte(name, '['); i >= 0 {
+		name = name[:i]
+	}
+
+	return ast.IsExported(name)
+}
@@ -0,0 +1,187 @@
+// Package diff restricts findings to lines added or modified in a unified diff,
+// allowing godox to be used as a gate for changes without failing on existing comments.
+package diff
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/matoous/godox"
+)
+
+// Changes are the added or modified lines, by file name.
+type Changes map[string]map[int]struct{}
+
+// Parse parses the unified diff and returns the lines added or modified on the new side of the diff.
+// File names are those of the new side with the b/ prefix stripped. The file headers are read only before the first
+// hunk of the file, following the diff line, so the added lines starting with ++ are not mistaken for them.
+func Parse(r io.Reader) (Changes, error) {
+	changes := make(Changes)
+
+	var (
+		file string
+		line int
+		// header is set until the first hunk of the file, the diffs without the diff lines start with the headers
+		header = true
+	)
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1024*1024)
+
+	for sc.Scan() {
+		text := sc.Text()
+
+		switch {
+		case strings.HasPrefix(text, "diff "):
+			file, line, header = "", 0, true
+		case header && strings.HasPrefix(text, "+++ "):
+			name, err := parseFileName(strings.TrimPrefix(text, "+++ "))
+			if err != nil {
+				return nil, err
+			}
+
+			file, line = name, 0
+		case strings.HasPrefix(text, "@@ "):
+			start, err := parseHunkStart(text)
+			if err != nil {
+				return nil, err
+			}
+
+			line, header = start, false
+		case file == "" || line == 0:
+			// outside of a hunk
+		case strings.HasPrefix(text, "+"):
+			if changes[file] == nil {
+				changes[file] = make(map[int]struct{})
+			}
+
+			changes[file][line] = struct{}{}
+			line++
+		case strings.HasPrefix(text, " "):
+			line++
+		}
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// parseFileName parses the file name of the +++ line.
+func parseFileName(name string) (string, error) {
+	if i := strings.IndexByte(name, '\t'); i >= 0 {
+		name = name[:i]
+	}
+
+	if strings.HasPrefix(name, `"`) {
+		unquoted, err := strconv.Unquote(name)
+		if err != nil {
+			return "", fmt.Errorf("invalid file name %s: %w", name, err)
+		}
+
+		name = unquoted
+	}
+
+	if name == "/dev/null" {
+		return "", nil
+	}
+
+	return strings.TrimPrefix(name, "b/"), nil
+}
+
+// parseHunkStart returns the first line of the new side of the hunk, e.g. 3 for @@ -1,2 +3,4 @@.
+func parseHunkStart(header string) (int, error) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+		return 0, fmt.Errorf("invalid hunk header %q", header)
+	}
+
+	start := strings.TrimPrefix(fields[2], "+")
+	if i := strings.IndexByte(start, ','); i >= 0 {
+		start = start[:i]
+	}
+
+	n, err := strconv.Atoi(start)
+	if err != nil {
+		return 0, fmt.Errorf("invalid hunk header %q: %w", header, err)
+	}
+
+	return n, nil
+}
+
+// Git returns lines changed in the revision range, e.g. origin/main...HEAD, of the repository
+// containing the directory. The file names are absolute.
+func Git(ctx context.Context, dir, revRange string) (Changes, error) {
+	root, err := git(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+
+	// The prefixes are forced as diff.noprefix and diff.mnemonicPrefix configurations change them.
+	out, err := git(ctx, dir, "diff", "--no-color", "--no-ext-diff", "--unified=0", "--src-prefix=a/", "--dst-prefix=b/", revRange, "--")
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := Parse(bytes.NewReader(out))
+	if err != nil {
+		return nil, err
+	}
+
+	top, err := filepath.EvalSymlinks(strings.TrimSpace(string(root)))
+	if err != nil {
+		return nil, err
+	}
+
+	abs := make(Changes, len(changes))
+
+	for file, lines := range changes {
+		abs[filepath.Join(top, filepath.FromSlash(file))] = lines
+	}
+
+	return abs, nil
+}
+
+func git(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+
+	return out, nil
+}
+
+// Filter returns messages on the changed lines. Relative file names of the messages
+// are resolved against the directory before looking them up in the changes,
+// the directory should have symbolic links resolved the same way as Git does.
+func (c Changes) Filter(dir string, messages []godox.Message) []godox.Message {
+	var filtered []godox.Message
+
+	for _, m := range messages {
+		file := m.Pos.Filename
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(dir, file)
+		}
+
+		if _, ok := c[filepath.Clean(file)][m.Line]; ok {
+			filtered = append(filtered, m)
+		}
+	}
+
+	return filtered
+}
@@ -0,0 +1,153 @@
+package diff_test
+
+import (
+	"context"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/matoous/godox"
+	"github.com/matoous/godox/config"
+	"github.com/matoous/godox/diff"
+)
+
+const unified = `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,4 +1,6 @@
+ package main
+ 
++// TODO: added
+ // TODO: existing
+-// FIXME: removed
++// FIXME: modified
+ func main() {}
+@@ -10,0 +12,2 @@ func main() {}
++// BUG: added at the end
++++ not a header
+diff --git a/old.go b/old.go
+deleted file mode 100644
+--- a/old.go
++++ /dev/null
+@@ -1 +0,0 @@
+-// TODO: deleted
+`
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	changes, err := diff.Parse(strings.NewReader(unified))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(changes) != 1 {
+		t.Fatalf("expected changes in one file, got %v", changes)
+	}
+
+	for _, line := range []int{3, 5, 12, 13} {
+		if _, ok := changes["main.go"][line]; !ok {
+			t.Errorf("expected line %d to be changed", line)
+		}
+	}
+
+	if len(changes["main.go"]) != 4 {
+		t.Errorf("expected 4 changed lines, got %v", changes["main.go"])
+	}
+}
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+
+	const src = `package main
+
+// TODO: added
+// TODO: existing
+// FIXME: modified
+func main() {}
+`
+
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes := diff.Changes{"/repo/main.go": {3: {}, 5: {}}}
+
+	messages := changes.Filter("/repo", godox.Run(f, fset, &config.GoDoxSettings{}))
+	if len(messages) != 2 || messages[0].Line != 3 || messages[1].Line != 5 {
+		t.Errorf("unexpected messages %q", messages)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	t.Parallel()
+
+	if _, err := diff.Parse(strings.NewReader("+++ b/main.go\n@@ -1 +x @@\n")); err == nil {
+		t.Error("expected error for invalid hunk header")
+	}
+}
+
+func TestGit(t *testing.T) {
+	t.Parallel()
+
+	for _, option := range []string{"diff.noprefix", "diff.mnemonicPrefix"} {
+		option := option //nolint // reason option is ok on this context
+		t.Run(option, func(t *testing.T) {
+			t.Parallel()
+
+			dir, err := ioutil.TempDir("", "godox")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer os.RemoveAll(dir)
+
+			git := func(args ...string) {
+				cmd := exec.Command("git", append([]string{"-c", "user.name=Alice", "-c", "user.email=alice@example.com"}, args...)...)
+				cmd.Dir = dir
+
+				if out, err := cmd.CombinedOutput(); err != nil {
+					t.Fatalf("git %v: %v: %s", args, err, out)
+				}
+			}
+
+			main := filepath.Join(dir, "main.go")
+			if err := ioutil.WriteFile(main, []byte("package main\n\n// TODO: committed\n"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			git("init", "-q")
+			git("config", option, "true")
+			git("add", "main.go")
+			git("commit", "-q", "-m", "initial")
+
+			if err := ioutil.WriteFile(main, []byte("package main\n\n// TODO: committed\n// TODO: changed\n"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			changes, err := diff.Git(context.Background(), dir, "HEAD")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			top, err := filepath.EvalSymlinks(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected := diff.Changes{filepath.Join(top, "main.go"): {4: {}}}
+			if !reflect.DeepEqual(changes, expected) {
+				t.Errorf("not equal\nexpected: %v\nactual: %v", expected, changes)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package godox
+
+import "strings"
+
+// isDirective reports whether the comment is a directive, such as //go:generate, //go:build, //nolint:lll, //line or
+// //export, as defined by gofmt. The directives are not scanned for keywords while the bare //nolint directives
+// suppress the findings of their comments anyway.
+func isDirective(comment string) bool {
+	if !strings.HasPrefix(comment, "//") {
+		return false
+	}
+
+	c := comment[len("//"):]
+
+	for _, prefix := range []string{"line ", "extern ", "export "} {
+		if strings.HasPrefix(c, prefix) {
+			return true
+		}
+	}
+
+	// the directives have the //[a-z0-9]+:[a-z0-9] form
+	colon := strings.IndexByte(c, ':')
+	if colon <= 0 || colon+1 >= len(c) {
+		return false
+	}
+
+	for i := 0; i <= colon+1; i++ {
+		if i == colon {
+			continue
+		}
+
+		if b := c[i]; !('a' <= b && b <= 'z' || '0' <= b && b <= '9') {
+			return false
+		}
+	}
+
+	return true
+}
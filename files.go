@@ -0,0 +1,204 @@
+package godox
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"runtime"
+	"sync"
+
+	"github.com/matoous/godox/config"
+)
+
+// RunFiles parses and runs the godox linter on the files using a pool of Concurrency workers,
+// GOMAXPROCS workers are used if Concurrency isn't set. The messages are sorted by file name, line
+// and column regardless of the order in which the files were scanned, see SortMessages. Identical
+// messages are reported once if Deduplicate is enabled.
+//
+// If CacheDir is set, messages are cached per file content and settings so unchanged files
+// are not scanned again. The cache is invalidated daily so the deadlines are evaluated again.
+func RunFiles(ctx context.Context, filenames []string, settings *config.GoDoxSettings) ([]Message, error) {
+	compiled, err := settings.Compile()
+	if err != nil {
+		return nil, err
+	}
+
+	return runFiles(ctx, filenames, compiled, ioutil.ReadFile)
+}
+
+// RunFilesFrom runs the godox linter on the files like RunFiles, but the contents of the files are returned
+// by the read function instead of being read from the disk, e.g. the contents of the files at a git revision.
+func RunFilesFrom(ctx context.Context, filenames []string, settings *config.GoDoxSettings,
+	read func(filename string) ([]byte, error),
+) ([]Message, error) {
+	compiled, err := settings.Compile()
+	if err != nil {
+		return nil, err
+	}
+
+	return runFiles(ctx, filenames, compiled, read)
+}
+
+// RunSource parses the source of the file and runs the godox linter on it, e.g. on the unsaved content
+// of an editor buffer. Syntax errors are ignored as the comments are collected from the whole source anyway.
+// The file name is used in the messages and to match the paths and nested configurations in the settings.
+func RunSource(ctx context.Context, filename string, src []byte, settings *config.GoDoxSettings) ([]Message, error) {
+	compiled, err := settings.Compile()
+	if err != nil {
+		return nil, err
+	}
+
+	if compiled, err = compiled.ForFile(filename); err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+
+	var f *ast.File
+
+	switch {
+	case isAssembly(filename) || isGoSource(filename) && compiled.Engine == config.EngineFast:
+		f = scanComments(fset, filename, src)
+	case !isGoSource(filename):
+		f = rawComments(fset, filename, src)
+	default:
+		// all errors are collected so the parser doesn't bail out before reaching the end of the source
+		f, _ = parser.ParseFile(fset, filename, src, parser.ParseComments|parser.AllErrors)
+	}
+
+	return RunCompiledContext(ctx, f, fset, compiled)
+}
+
+// readFunc returns content of the file.
+type readFunc func(filename string) ([]byte, error)
+
+// runFiles scans the files read by the read function in parallel.
+func runFiles(ctx context.Context, filenames []string, settings *config.CompiledSettings, read readFunc) ([]Message, error) {
+	results := make([][]Message, len(filenames))
+	errs := make([]error, len(filenames))
+
+	_ = scanFiles(ctx, filenames, settings, read, func(i int, messages []Message, err error) error {
+		results[i], errs[i] = messages, err
+		return nil
+	})
+
+	var messages []Message
+
+	for i := range filenames {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+
+		messages = append(messages, results[i]...)
+	}
+
+	if settings.Deduplicate {
+		return Deduplicate(messages), nil
+	}
+
+	SortMessages(messages)
+
+	return messages, nil
+}
+
+// scanFiles scans the files read by the read function in parallel, calling fn with the results of each file
+// as soon as it is scanned. The fn is not called concurrently, once it returns an error the remaining files
+// are skipped and the error is returned.
+func scanFiles(ctx context.Context, filenames []string, settings *config.CompiledSettings, read readFunc,
+	fn func(i int, messages []Message, err error) error,
+) error {
+	workers := settings.Concurrency
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	if workers > len(filenames) {
+		workers = len(filenames)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		c    = newCache(settings)
+		jobs = make(chan int)
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		stop error
+	)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				messages, err := runFile(ctx, filenames[i], settings, c, read)
+
+				mu.Lock()
+				if stop == nil {
+					if stop = fn(i, messages, err); stop != nil {
+						cancel()
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := range filenames {
+		jobs <- i
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	return stop
+}
+
+func runFile(ctx context.Context, filename string, settings *config.CompiledSettings, c *cache, read readFunc) ([]Message, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	settings, err := settings.ForFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if !settings.IncludesPath(filename) {
+		return nil, nil
+	}
+
+	content, err := read(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if c != nil {
+		if messages, ok := c.get(settings, filename, content); ok {
+			return messages, nil
+		}
+	}
+
+	fset := token.NewFileSet()
+
+	f, err := parseFile(fset, filename, content, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := RunCompiledContext(ctx, f, fset, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	if c != nil {
+		c.put(settings, filename, content, messages)
+	}
+
+	return messages, nil
+}
@@ -0,0 +1,20 @@
+package suppress
+
+// TODO: reported (Line 3)
+func foo() {} // TODO: suppressed //nolint:godox
+
+//nolint:godox
+// FIXME: suppressed by the group directive
+func bar() {}
+
+// godox:ignore:TODO
+// TODO: suppressed by keyword
+// FIXME: reported (Line 12)
+func baz() {}
+
+/* BUG: suppressed by the line directive */ //godox:ignore
+func qux() {}
+
+// TODO: reported, other linter (Line 18) //nolint:lll
+
+// FIXME: reported (Line 20) //nolinter
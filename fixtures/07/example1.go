@@ -0,0 +1,8 @@
+package issues
+
+// TODO(#12): reference to a GitHub issue
+// TODO: see matoous/godox#34
+// FIXME: PROJ-56 is a Jira issue
+// TODO: https://github.com/matoous/godox/issues/78
+// TODO: no reference (Line 7)
+// BUG: hash without number # (Line 8)
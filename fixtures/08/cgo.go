@@ -0,0 +1,11 @@
+package tags
+
+// #include <stdlib.h>
+//
+// // FIXME: free the strings
+// static const char *greeting = "// TODO: not a comment";
+// static int answer(void) { return 42; } /* BUG: not
+// the answer */
+import "C"
+
+// TODO: cgo file
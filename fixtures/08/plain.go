@@ -0,0 +1,3 @@
+package tags
+
+// TODO: plain file
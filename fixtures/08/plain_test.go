@@ -0,0 +1,3 @@
+package tags
+
+// TODO: test file
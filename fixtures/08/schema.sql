@@ -0,0 +1,2 @@
+-- TODO: index the owners
+CREATE TABLE owners (name TEXT); -- FIXME: unique names
@@ -0,0 +1,6 @@
+//go:build special
+// +build special
+
+package tags
+
+// TODO: tagged file
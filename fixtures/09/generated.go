@@ -0,0 +1,3 @@
+package generated
+
+// TODO: handwritten
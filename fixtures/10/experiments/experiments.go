@@ -0,0 +1,4 @@
+package experiments
+
+// TODO: allowed in experiments
+// BUG: still reported
@@ -0,0 +1,4 @@
+package main
+
+// TODO: root
+func main() {}
@@ -0,0 +1,4 @@
+package api
+
+// TODO: missing owner
+// TODO(alice): formatted
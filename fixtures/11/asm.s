@@ -0,0 +1,5 @@
+#include "textflag.h"
+
+// TODO: vectorize the loop
+TEXT ·answer(SB), NOSPLIT, $0-0
+	RET // FIXME: load the answer
@@ -0,0 +1,53 @@
+package godox
+
+import (
+	"context"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/matoous/godox/config"
+)
+
+// RunFS runs the godox linter on the Go files in the root directory of the file system and its subdirectories,
+// e.g. embedded files or a zip archive. Directories ignored by the go command, testdata and those starting
+// with . or _, are skipped, test files are scanned if Tests is enabled. Build constraints are not evaluated.
+// Other files matching the Include patterns are scanned as well.
+// File names in the messages are the slash separated paths in the file system. The files are scanned
+// in parallel, see RunFiles.
+func RunFS(ctx context.Context, fsys fs.FS, root string, settings *config.GoDoxSettings) ([]Message, error) {
+	compiled, err := settings.Compile()
+	if err != nil {
+		return nil, err
+	}
+
+	var filenames []string
+
+	err = fs.WalkDir(fsys, root, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if name != root && ignoredDir(d.Name()) {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		if path.Ext(name) == ".go" && (settings.Tests || !strings.HasSuffix(name, "_test.go")) ||
+			path.Ext(name) != ".go" && compiled.IncludesFile(name) {
+			filenames = append(filenames, name)
+		}
+
+		return ctx.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return runFiles(ctx, filenames, compiled, func(name string) ([]byte, error) {
+		return fs.ReadFile(fsys, name)
+	})
+}
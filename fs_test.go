@@ -0,0 +1,64 @@
+package godox_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	"github.com/matoous/godox"
+	"github.com/matoous/godox/config"
+)
+
+func TestRunFS(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"src/main.go":             {Data: []byte("package main\n\n// TODO: main\n")},
+		"src/main_test.go":        {Data: []byte("package main\n\n// TODO: test\n")},
+		"src/pkg/pkg.go":          {Data: []byte("package pkg\n\n// FIXME: pkg\n")},
+		"src/testdata/data.go":    {Data: []byte("package data\n\n// TODO: testdata\n")},
+		"src/.hidden/hidden.go":   {Data: []byte("package hidden\n\n// TODO: hidden\n")},
+		"src/README.md":           {Data: []byte("TODO: readme\n")},
+		"other/other.go":          {Data: []byte("package other\n\n// TODO: other\n")},
+		"src/pkg/vendor/v/dep.go": {Data: []byte("package v\n\n// BUG: vendor\n")},
+	}
+
+	tests := []struct {
+		settings config.GoDoxSettings
+		expected []string
+	}{
+		{
+			expected: []string{"src/main.go:3 TODO: main", "src/pkg/pkg.go:3 FIXME: pkg", "src/pkg/vendor/v/dep.go:3 BUG: vendor"},
+		},
+		{
+			settings: config.GoDoxSettings{Tests: true, ExcludePaths: []string{"**/vendor/**"}},
+			expected: []string{"src/main.go:3 TODO: main", "src/main_test.go:3 TODO: test", "src/pkg/pkg.go:3 FIXME: pkg"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt //nolint // reason tt is ok on this context
+		t.Run(fmt.Sprint(tt.expected), func(t *testing.T) {
+			t.Parallel()
+
+			messages, err := godox.RunFS(context.Background(), fsys, "src", &tt.settings)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var actual []string
+			for _, m := range messages {
+				actual = append(actual, fmt.Sprintf("%s:%d %s", m.Pos.Filename, m.Line, m.Text))
+			}
+
+			if fmt.Sprint(actual) != fmt.Sprint(tt.expected) {
+				t.Errorf("not equal\nexpected: %q\nactual: %q", tt.expected, actual)
+			}
+		})
+	}
+
+	if _, err := godox.RunFS(context.Background(), fsys, "missing", &config.GoDoxSettings{}); err == nil {
+		t.Error("expected error for missing root")
+	}
+}
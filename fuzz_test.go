@@ -0,0 +1,49 @@
+//go:build go1.18
+// +build go1.18
+
+package godox_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/matoous/godox"
+	"github.com/matoous/godox/config"
+)
+
+func FuzzRun(f *testing.F) {
+	for _, seed := range []string{
+		"package main\n\n// TODO: first\n",
+		"package main\n\n/*\n  FIXME: second\n*/\n",
+		"package main\n\nvar x = 1 //nolint:godox // BUG: third\n",
+		"package main\n\n/**/\n//\n",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, src string) {
+		fset := token.NewFileSet()
+
+		// the source doesn't have to be valid as the comments are collected even on errors
+		file, _ := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+		if file != nil {
+			godox.Run(file, fset, &config.GoDoxSettings{})
+			godox.Run(file, fset, &config.GoDoxSettings{
+				Format: true,
+				FormatRules: []config.GoDoxFormatRule{
+					{Keyword: "DEPRECATED", RegularExpression: `^DEPRECATED\(\w+\):`},
+				},
+			})
+		}
+
+		// the source is also used directly as the comment text of a synthetic file
+		synthetic := &ast.File{
+			Name:     ast.NewIdent("main"),
+			Comments: []*ast.CommentGroup{{List: []*ast.Comment{{Text: src}}}},
+		}
+
+		godox.Run(synthetic, token.NewFileSet(), &config.GoDoxSettings{})
+	})
+}
@@ -0,0 +1,26 @@
+package godox
+
+import (
+	"go/ast"
+	"regexp"
+)
+
+// generatedRe matches the standard comment marking generated files, see https://golang.org/s/generatedcode.
+var generatedRe = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+
+// isGenerated reports whether the file has the generated code comment before the package clause.
+func isGenerated(file *ast.File) bool {
+	for _, c := range file.Comments {
+		if file.Package.IsValid() && c.Pos() > file.Package {
+			break
+		}
+
+		for _, ci := range c.List {
+			if generatedRe.MatchString(ci.Text) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -1,5 +1,11 @@
 module github.com/matoous/godox
 
-go 1.13
+go 1.16
 
-require golang.org/x/tools v0.0.0-20190910044552-dd2b5c81c578
+require (
+	github.com/BurntSushi/toml v0.3.1
+	github.com/fsnotify/fsnotify v1.4.9
+	github.com/mitchellh/mapstructure v1.4.3
+	golang.org/x/tools v0.0.0-20190910044552-dd2b5c81c578
+	gopkg.in/yaml.v2 v2.4.0
+)
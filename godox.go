@@ -1,193 +1,1334 @@
 package godox
 
 import (
-	"bufio"
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"go/ast"
 	"go/token"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+	"text/template"
+	"time"
 	"unicode"
 	"unicode/utf8"
 
 	"github.com/matoous/godox/config"
 )
 
-var defaultKeywords = []string{"TODO", "BUG", "FIXME"}
+// Rule identifiers reported in Message.RuleID.
+const (
+	// RuleKeyword is reported for comments containing one of the keywords.
+	RuleKeyword = "keyword"
+	// RuleFormat is reported for comments not matching the expected format.
+	RuleFormat = "format"
+	// RuleIssue is reported for comments not referencing an issue when issue references are required.
+	RuleIssue = "issue"
+	// RuleDeadline is reported for comments with expired deadline.
+	RuleDeadline = "deadline"
+	// RuleOwner is reported for comments without an owner when owners are required.
+	RuleOwner = "owner"
+	// RuleUnknownOwner is reported for comments with owners which are not in the roster.
+	RuleUnknownOwner = "unknown-owner"
+	// RuleClosedIssue is reported for comments referencing closed issues when the issues are checked.
+	RuleClosedIssue = "closed-issue"
+	// RuleMissingIssue is reported for comments referencing issues which don't exist when the issues are checked.
+	RuleMissingIssue = "missing-issue"
+	// RuleDescriptionLength is reported for comments with descriptions shorter than the minimum length.
+	RuleDescriptionLength = "description-length"
+	// RuleForbiddenPattern is reported for comments matching the forbidden pattern of a format rule.
+	RuleForbiddenPattern = "forbidden-pattern"
+	// RuleForbiddenKeyword is reported for comments containing one of the forbidden keywords.
+	RuleForbiddenKeyword = "forbidden-keyword"
+	// RuleExportedDoc is reported for doc comments of exported declarations and packages containing one of the keywords
+	// when they are forbidden there.
+	RuleExportedDoc = "exported-doc"
+	// RuleStringLiteral is reported for string literals containing one of the keywords when they are scanned.
+	RuleStringLiteral = "string-literal"
+)
+
+// Severity of a message.
+type Severity string
+
+// Available severities.
+const (
+	SeverityError   Severity = config.SeverityError
+	SeverityWarning Severity = config.SeverityWarning
+	SeverityInfo    Severity = config.SeverityInfo
+)
+
+// AtLeast reports whether the severity is the same or more severe than the other one.
+// Unknown severities are the least severe.
+func (s Severity) AtLeast(other Severity) bool {
+	return s.rank() >= other.rank()
+}
+
+func (s Severity) rank() int {
+	switch s {
+	case SeverityError:
+		return 3
+	case SeverityWarning:
+		return 2
+	case SeverityInfo:
+		return 1
+	default:
+		return 0
+	}
+}
 
 // Message contains a message and position.
 type Message struct {
 	Pos     token.Position
 	Message string
+
+	// Keyword is the keyword the comment line starts with.
+	Keyword string
+	// Canonical is the configured keyword the Keyword is an alias of, or the Keyword itself.
+	Canonical string
+	// Text is the full, untruncated, comment line.
+	Text string
+	// Line is the line of the comment line containing the keyword.
+	Line int
+	// Column is the column of the keyword.
+	Column int
+	// End is the position right after the keyword, the keyword spans from Pos to End on the Line.
+	End token.Position
+	// TextEnd is the position right after the comment line Text in the source, on a later line if the continuation
+	// lines are merged into the Text, see GoDoxSettings.Multiline. It is zero for the messages read from JSON.
+	TextEnd token.Position
+	// Severity of the message.
+	Severity Severity
+	// RuleID identifies the rule which produced the message.
+	RuleID string
+	// FormatRule is the ID of the format rule which produced the message, empty for other rules.
+	FormatRule string
+	// Owner specified after the keyword, e.g. alice for TODO(alice).
+	Owner string
+	// Priority specified after the keyword, e.g. P1 for TODO(P1) or TODO(alice, P1), see config.Priorities.
+	Priority string
+	// Labels are the hashtags and bracketed labels in the comment line, e.g. perf and v2 for
+	// TODO: clean up #perf [v2], in the order of their first occurrence.
+	Labels []string
+	// Effort is the estimate in brackets in the comment line, e.g. 3 days for TODO [3d]: rewrite the parser,
+	// in hours, working days of EffortDay or working weeks of EffortWeek, zero if there is none.
+	Effort time.Duration
+	// Issue is the issue reference found in the comment line, if any.
+	Issue string
+	// IssueTracker is the name of the tracker the issue reference belongs to.
+	IssueTracker string
+	// Deadline found in the comment line, zero if there is none.
+	Deadline time.Time
+	// Expired is set if the deadline has passed.
+	Expired bool
+	// Snoozed is the date the comment is snoozed until, e.g. 2025-09-01 for TODO(alice)[snooze:2025-09-01],
+	// parsed using the DeadlineLayout, zero if it isn't snoozed. The messages are suppressed until the date,
+	// and reported as errors since then.
+	Snoozed time.Time
+	// Author of the commit which introduced the line, set only by git blame annotation.
+	Author string
+	// Commit which introduced the line, set only by git blame annotation.
+	Commit string
+	// Introduced is the author date of the commit which introduced the line, zero if unknown.
+	Introduced time.Time
+	// Declaration is the name of the top level declaration containing the comment, e.g. (*Server).Start,
+	// empty outside of declarations and when the comments are read by the fast engine.
+	Declaration string
+	// DeclarationKind is the kind of the Declaration, see the Declaration constants.
+	DeclarationKind string
+	// Fix is the suggested replacement of the comment line Text, which starts at Pos, fixing the message.
+	// It is set for format rule violations which match the rule when rewritten, see GoDoxFormatRule.Fix.
+	Fix string
+	// Suppressed is set for messages suppressed by a nolint or godox:ignore directive, or snoozed,
+	// these are returned only if reporting of suppressed messages is enabled.
+	Suppressed bool
+}
+
+// String returns the formatted message.
+func (m Message) String() string {
+	return m.Message
+}
+
+// Description returns the formatted message without the leading file name and line.
+func (m Message) Description() string {
+	return strings.TrimPrefix(m.Message, fmt.Sprintf("%s:%d: ", filepath.Clean(m.Pos.Filename), m.Line))
+}
+
+// jsonMessage is the JSON representation of Message.
+type jsonMessage struct {
+	File      string   `json:"file"`
+	Line      int      `json:"line"`
+	Column    int      `json:"column"`
+	EndColumn int      `json:"end_column"`
+	Keyword   string   `json:"keyword"`
+	Canonical string   `json:"canonical_keyword,omitempty"`
+	Rule      string   `json:"rule"`
+	Format    string   `json:"format_rule,omitempty"`
+	Severity  Severity `json:"severity"`
+	Text      string   `json:"text"`
+	Message   string   `json:"message"`
+
+	Owner           string   `json:"owner,omitempty"`
+	Priority        string   `json:"priority,omitempty"`
+	Labels          []string `json:"labels,omitempty"`
+	EffortHours     int      `json:"effort_hours,omitempty"`
+	Issue           string   `json:"issue,omitempty"`
+	IssueTracker    string   `json:"issue_tracker,omitempty"`
+	Deadline        string   `json:"deadline,omitempty"`
+	Expired         bool     `json:"expired,omitempty"`
+	Snoozed         string   `json:"snoozed,omitempty"`
+	Author          string   `json:"author,omitempty"`
+	Commit          string   `json:"commit,omitempty"`
+	Introduced      string   `json:"introduced,omitempty"`
+	Declaration     string   `json:"declaration,omitempty"`
+	DeclarationKind string   `json:"declaration_kind,omitempty"`
+	Fix             string   `json:"fix,omitempty"`
+	Suppressed      bool     `json:"suppressed,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (m Message) MarshalJSON() ([]byte, error) {
+	var deadline string
+	if !m.Deadline.IsZero() {
+		deadline = m.Deadline.Format(time.RFC3339)
+	}
+
+	var snoozed string
+	if !m.Snoozed.IsZero() {
+		snoozed = m.Snoozed.Format(time.RFC3339)
+	}
+
+	var introduced string
+	if !m.Introduced.IsZero() {
+		introduced = m.Introduced.Format(time.RFC3339)
+	}
+
+	return json.Marshal(jsonMessage{
+		File:      filepath.ToSlash(filepath.Clean(m.Pos.Filename)),
+		Line:      m.Line,
+		Column:    m.Column,
+		EndColumn: m.End.Column,
+		Keyword:   m.Keyword,
+		Canonical: m.Canonical,
+		Rule:      m.RuleID,
+		Format:    m.FormatRule,
+		Severity:  m.Severity,
+		Text:      m.Text,
+		Message:   m.Description(),
+
+		Owner:           m.Owner,
+		Priority:        m.Priority,
+		Labels:          m.Labels,
+		EffortHours:     int(m.Effort / time.Hour),
+		Issue:           m.Issue,
+		IssueTracker:    m.IssueTracker,
+		Deadline:        deadline,
+		Expired:         m.Expired,
+		Snoozed:         snoozed,
+		Author:          m.Author,
+		Commit:          m.Commit,
+		Introduced:      introduced,
+		Declaration:     m.Declaration,
+		DeclarationKind: m.DeclarationKind,
+		Fix:             m.Fix,
+		Suppressed:      m.Suppressed,
+	})
 }
 
-func getMessages(comment *ast.Comment, fset *token.FileSet, keywords []string) []Message {
-	commentText := extractComment(comment.Text)
+// UnmarshalJSON implements json.Unmarshaler, it reads the messages written by MarshalJSON,
+// e.g. the findings of the JSON reports. The byte offsets of the positions are not preserved.
+func (m *Message) UnmarshalJSON(data []byte) error {
+	var j jsonMessage
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	var deadline time.Time
+	if j.Deadline != "" {
+		var err error
+		if deadline, err = time.Parse(time.RFC3339, j.Deadline); err != nil {
+			return fmt.Errorf("deadline: %w", err)
+		}
+	}
+
+	var snoozed time.Time
+	if j.Snoozed != "" {
+		var err error
+		if snoozed, err = time.Parse(time.RFC3339, j.Snoozed); err != nil {
+			return fmt.Errorf("snoozed: %w", err)
+		}
+	}
+
+	var introduced time.Time
+	if j.Introduced != "" {
+		var err error
+		if introduced, err = time.Parse(time.RFC3339, j.Introduced); err != nil {
+			return fmt.Errorf("introduced: %w", err)
+		}
+	}
+
+	filename := filepath.FromSlash(j.File)
+
+	*m = Message{
+		Pos:        token.Position{Filename: filename, Line: j.Line, Column: j.Column},
+		Message:    fmt.Sprintf("%s:%d: %s", filepath.Clean(filename), j.Line, j.Message),
+		Keyword:    j.Keyword,
+		Canonical:  j.Canonical,
+		Text:       j.Text,
+		Line:       j.Line,
+		Column:     j.Column,
+		End:        token.Position{Filename: filename, Line: j.Line, Column: j.EndColumn},
+		Severity:   j.Severity,
+		RuleID:     j.Rule,
+		FormatRule: j.Format,
 
-	b := bufio.NewReader(bytes.NewBufferString(commentText))
+		Owner:           j.Owner,
+		Priority:        j.Priority,
+		Labels:          j.Labels,
+		Effort:          time.Duration(j.EffortHours) * time.Hour,
+		Issue:           j.Issue,
+		IssueTracker:    j.IssueTracker,
+		Deadline:        deadline,
+		Expired:         j.Expired,
+		Snoozed:         snoozed,
+		Author:          j.Author,
+		Commit:          j.Commit,
+		Introduced:      introduced,
+		Declaration:     j.Declaration,
+		DeclarationKind: j.DeclarationKind,
+		Fix:             j.Fix,
+		Suppressed:      j.Suppressed,
+	}
+
+	return nil
+}
+
+// Rewrite returns the message reported for the rule instead, the description is followed by the quoted
+// comment line truncated to config.DefaultMaxMessageLength runes.
+func (m Message) Rewrite(ruleID, description string) Message {
+	return m.RewriteLimit(ruleID, description, config.DefaultMaxMessageLength)
+}
+
+// RewriteLimit is like Rewrite, but the comment line is truncated to the limit of runes, or not at all if it is 0.
+func (m Message) RewriteLimit(ruleID, description string, limit int) Message {
+	m.RuleID = ruleID
+	m.Message = formatMessage(m.Pos, description, []byte(m.Text), limit)
+
+	return m
+}
+
+func init() {
+	config.MessageTemplateData = Message{}
+}
+
+// applyTemplate replaces the message following the file name and line by the message template executed
+// with the message.
+func applyTemplate(m Message, tmpl *template.Template) (Message, error) {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, m); err != nil {
+		return m, fmt.Errorf("message template: %w", err)
+	}
 
+	m.Message = fmt.Sprintf("%s:%d: %s", filepath.Clean(m.Pos.Filename), m.Line, b.String())
+
+	return m, nil
+}
+
+// Fingerprint returns a stable identifier of the message which doesn't change when the comment
+// is moved to a different line within the same file.
+func (m Message) Fingerprint() string {
+	h := sha256.New()
+	for _, part := range []string{filepath.ToSlash(filepath.Clean(m.Pos.Filename)), m.RuleID, m.Keyword, m.Text} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+func getMessages(comment *ast.Comment, lines []commentLine, fset *token.FileSet, settings *config.CompiledSettings) []Message {
 	var comments []Message
 
-	for lineNum := 0; ; lineNum++ {
-		line, _, err := b.ReadLine()
-		if err != nil {
-			break
+	for _, line := range lines {
+		const minimumSize = 4
+
+		line = trimLine(line, settings)
+
+		if len(line.text) < minimumSize {
+			continue
+		}
+
+		comments = append(comments, keywordMessages(comment, fset, line, settings)...)
+	}
+
+	return comments
+}
+
+// keywordMessages returns the messages of the keywords found in the comment line.
+func keywordMessages(comment *ast.Comment, fset *token.FileSet, line commentLine, settings *config.CompiledSettings) []Message {
+	var messages []Message
+
+	for _, match := range settings.MatchKeywords(line.text) {
+		// the rest of the line is the text of the comment found in the middle of the line
+		rest := line.from(match.Start)
+		kw, text := match.Keyword, rest.text
+		pos := linePosition(comment, fset, rest)
+
+		if settings.IsForbidden(kw) {
+			messages = append(messages, forbiddenMessage(pos, kw, text, settings))
+			continue
+		}
+
+		found, policy := policyMessages(pos, kw, text, settings)
+		if !policy {
+			found = append(found, newMessage(pos, kw, RuleKeyword, text,
+				fmt.Sprintf("Line contains %s: ", strings.Join(settings.Keywords, "/")), settings))
 		}
 
+		messages = append(messages, annotate(found, kw, text, settings)...)
+	}
+
+	return messages
+}
+
+func getMessagesFormat(comment *ast.Comment, lines []commentLine, fset *token.FileSet,
+	settings *config.CompiledSettings,
+) []Message {
+	var comments []Message
+
+	for _, line := range lines {
 		const minimumSize = 4
 
-		sComment := bytes.TrimSpace(line)
+		line = trimLine(line, settings)
+
+		sComment := line.text
 		if len(sComment) < minimumSize {
 			continue
 		}
 
-		for _, kw := range keywords {
-			if lkw := len(kw); !(bytes.EqualFold([]byte(kw), sComment[0:lkw]) &&
-				!hasAlphanumRuneAdjacent(sComment[lkw:])) {
+		// keywords without format rules are reported as usual in the combined mode
+		if settings.Combined && !hasFormatRule(sComment, settings) {
+			comments = append(comments, keywordMessages(comment, fset, line, settings)...)
+			continue
+		}
+
+		forbidden := false
+
+		for _, match := range settings.MatchKeywords(sComment) {
+			if settings.IsForbidden(match.Keyword) {
+				rest := line.from(match.Start)
+				comments = append(comments, forbiddenMessage(linePosition(comment, fset, rest), match.Keyword, rest.text, settings))
+				forbidden = true
+			}
+		}
+
+		if forbidden && !settings.ReportAllMatches {
+			continue
+		}
+
+		for _, formatRule := range settings.FormatRules {
+			if settings.IsRuleDisabled(formatRule.ID) {
+				continue
+			}
+
+			kw, applies := settings.FormatRuleKeyword(formatRule, sComment)
+			if !applies {
+				continue
+			}
+
+			formatPattern := formatRule.RegularExpression
+
+			pos := linePosition(comment, fset, line)
+
+			if formatRule.ForbidRegexp != nil && formatRule.ForbidRegexp.Match(sComment) {
+				m := newMessage(pos, kw, RuleForbiddenPattern, sComment,
+					fmt.Sprintf("Line matches the forbidden pattern: %s, ", formatRule.Forbid), settings)
+				m.FormatRule = formatRule.ID
+
+				comments = append(comments, annotate([]Message{m}, kw, sComment, settings)...)
+
+				if !settings.ReportAllMatches {
+					break
+				}
+
 				continue
 			}
 
-			pos := fset.Position(comment.Pos())
-			// trim the comment
-			const commentLimit = 40
-			if len(sComment) > commentLimit {
-				sComment = []byte(fmt.Sprintf("%.40s...", sComment))
+			// check the format, rules with only the forbidden pattern have none
+			valid := formatRule.Regexp == nil && formatRule.ForbidRegexp != nil
+			if formatRule.Regexp != nil {
+				valid = formatRule.Regexp.Match(sComment)
 			}
 
-			comments = append(comments, Message{
-				Pos: pos,
-				Message: fmt.Sprintf(
-					"%s:%d: Line contains %s: %q",
-					filepath.Clean(pos.Filename),
-					pos.Line+lineNum,
-					strings.Join(keywords, "/"),
-					sComment,
-				),
-			})
+			if valid {
+				found, _ := policyMessages(pos, kw, sComment, settings)
+				comments = append(comments, annotate(found, kw, sComment, settings)...)
 
-			break
+				continue
+			}
+
+			m := newMessage(pos, kw, RuleFormat, sComment,
+				fmt.Sprintf("Line does not match the expected format: %s, ", formatPattern), settings)
+			m.FormatRule = formatRule.ID
+			m.Fix = formatFix(comment, line, kw, formatRule)
+
+			comments = append(comments, annotate([]Message{m}, kw, sComment, settings)...)
+
+			if !settings.ReportAllMatches {
+				break
+			}
 		}
 	}
 
 	return comments
 }
 
-func getMessagesFormat(comment *ast.Comment, fset *token.FileSet, formatRules []config.GoDoxFormatRule) []Message {
-	commentText := extractComment(comment.Text)
+// hasFormatRule reports whether the comment line starts with a keyword of a format rule, or any keyword
+// if there is a default rule.
+func hasFormatRule(sComment []byte, settings *config.CompiledSettings) bool {
+	for _, rule := range settings.FormatRules {
+		if _, ok := settings.FormatRuleKeyword(rule, sComment); ok {
+			return true
+		}
+	}
 
-	b := bufio.NewReader(bytes.NewBufferString(commentText))
+	return false
+}
 
-	var comments []Message
+// forbiddenMessage returns the message of the comment line containing a forbidden keyword, the keyword
+// is not checked by the other rules.
+func forbiddenMessage(pos token.Position, keyword string, sComment []byte, settings *config.CompiledSettings) Message {
+	m := newMessage(pos, keyword, RuleForbiddenKeyword, sComment,
+		fmt.Sprintf("Line contains forbidden keyword %s: ", keyword), settings)
+	m = annotate([]Message{m}, keyword, sComment, settings)[0]
+	m.Severity = SeverityError
 
-	for lineNum := 0; ; lineNum++ {
-		line, _, err := b.ReadLine()
-		if err != nil {
-			break
-		}
+	return m
+}
 
+// getMessagesExportedDoc returns the messages of the doc comment of an exported declaration, all keyword lines
+// are reported regardless of the format rules and policies.
+func getMessagesExportedDoc(comment *ast.Comment, lines []commentLine, fset *token.FileSet,
+	settings *config.CompiledSettings,
+) []Message {
+	var comments []Message
+
+	for _, line := range lines {
 		const minimumSize = 4
 
-		sComment := bytes.TrimSpace(line)
+		line = trimLine(line, settings)
+
+		sComment := line.text
 		if len(sComment) < minimumSize {
 			continue
 		}
 
-		for _, formatRule := range formatRules {
-			kw := formatRule.Keyword
-			formatPattern := formatRule.RegularExpression
+		for _, match := range settings.MatchKeywords(sComment) {
+			rest := line.from(match.Start)
+			kw, text := match.Keyword, rest.text
 
-			if lkw := len(kw); !(bytes.EqualFold([]byte(kw), sComment[0:lkw]) &&
-				!hasAlphanumRuneAdjacent(sComment[lkw:])) {
-				continue
-			}
+			m := newMessage(linePosition(comment, fset, rest), kw, RuleExportedDoc, text,
+				fmt.Sprintf("Exported documentation contains %s: ", settings.Canonical(kw)), settings)
 
-			// check the format
-			if formatPattern != "" && isFormatted(formatPattern, string(sComment)) {
-				continue
+			m = annotate([]Message{m}, kw, text, settings)[0]
+			m.Severity = Severity(settings.ExportedDocsSeverity)
+
+			comments = append(comments, m)
+		}
+	}
+
+	return comments
+}
+
+// fixRe splits the text following the keyword into the owner in parenthesis and the description.
+var fixRe = regexp.MustCompile(`^\s*(\([^)]*\))?\s*[:-]?\s*(.*)$`)
+
+// formatFix returns the comment line rewritten by the Fix template of the rule, or to the canonical
+// "KEYWORD(owner): description" form if the rule has no fix pattern, if it differs from the line and
+// matches the format rule. Lines directly following the // comment marker get a leading space.
+func formatFix(comment *ast.Comment, line commentLine, keyword string, rule config.CompiledFormatRule) string {
+	sComment := line.text
+
+	var fixed string
+
+	switch {
+	// the fixes replace single lines
+	case rule.Regexp == nil || line.continued:
+		return ""
+	case rule.FixRegexp != nil:
+		m := rule.FixRegexp.FindSubmatchIndex(sComment)
+		if m == nil {
+			return ""
+		}
+
+		fixed = string(rule.FixRegexp.Expand(nil, []byte(rule.Fix), sComment, m))
+	default:
+		fixed = canonicalFix(sComment, keyword)
+	}
+
+	if fixed == "" || fixed == string(sComment) || !rule.Regexp.MatchString(fixed) {
+		return ""
+	}
+
+	if line.line == 0 && line.offset == len("//") && strings.HasPrefix(comment.Text, "//") {
+		fixed = " " + fixed
+	}
+
+	return fixed
+}
+
+// canonicalFix returns the comment line starting with the keyword in the "KEYWORD(owner): description" form,
+// empty if the line doesn't start with the keyword or has no description.
+func canonicalFix(sComment []byte, keyword string) string {
+	if len(sComment) <= len(keyword) || !bytes.EqualFold(sComment[:len(keyword)], []byte(keyword)) {
+		return ""
+	}
+
+	rest := sComment[len(keyword):]
+	if r, _ := utf8.DecodeRune(rest); unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+		return ""
+	}
+
+	m := fixRe.FindSubmatch(rest)
+	if m == nil || len(m[2]) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%s%s: %s", keyword, m[1], m[2])
+}
+
+// newMessage returns message for the comment line. The description is followed by the quoted comment line
+// truncated to the MaxMessageLength of the settings.
+func newMessage(pos token.Position, keyword, ruleID string, sComment []byte, description string,
+	settings *config.CompiledSettings,
+) Message {
+	return Message{
+		Pos:      pos,
+		Message:  formatMessage(pos, description, sComment, settings.MessageLength()),
+		Keyword:  keyword,
+		Text:     string(sComment),
+		Line:     pos.Line,
+		Column:   pos.Column,
+		End:      keywordEnd(pos, sComment, keyword),
+		TextEnd:  textEnd(pos, sComment),
+		Severity: SeverityWarning,
+		RuleID:   ruleID,
+	}
+}
+
+// textEnd returns the position following the comment line starting at the position.
+func textEnd(pos token.Position, sComment []byte) token.Position {
+	pos.Offset += len(sComment)
+	pos.Column += len(sComment)
+
+	return pos
+}
+
+// keywordEnd returns the position following the keyword at the start of the comment line, the keyword
+// can be in different case, but it has the same number of runes.
+func keywordEnd(pos token.Position, sComment []byte, keyword string) token.Position {
+	size := 0
+	for n := utf8.RuneCountInString(keyword); n > 0 && size < len(sComment); n-- {
+		_, s := utf8.DecodeRune(sComment[size:])
+		size += s
+	}
+
+	pos.Offset += size
+	pos.Column += size
+
+	return pos
+}
+
+// formatMessage returns the message text with the position, description and the quoted comment line
+// truncated to the limit of runes, the comment line isn't truncated if the limit is 0.
+func formatMessage(pos token.Position, description string, sComment []byte, limit int) string {
+	if limit > 0 && utf8.RuneCount(sComment) > limit {
+		sComment = []byte(fmt.Sprintf("%.*s...", limit, sComment))
+	}
+
+	return fmt.Sprintf("%s:%d: %s%q", filepath.Clean(pos.Filename), pos.Line, description, sComment)
+}
+
+// policyMessages returns messages for the comment line violating the enabled policies, such as
+// missing issue reference or expired deadline. The policy is false if no policy is enabled.
+func policyMessages(pos token.Position, keyword string, sComment []byte, settings *config.CompiledSettings) ([]Message, bool) {
+	var (
+		messages []Message
+		policy   bool
+	)
+
+	if settings.RequireIssueReference {
+		policy = true
+
+		if _, issue := findIssue(sComment, settings.IssuePatterns); issue == "" {
+			messages = append(messages, newMessage(pos, keyword, RuleIssue, sComment, "Line does not reference an issue: ", settings))
+		}
+	}
+
+	if settings.RequireOwner {
+		policy = true
+
+		if findOwner(keyword, sComment, settings) == "" {
+			messages = append(messages, newMessage(pos, keyword, RuleOwner, sComment, "Line does not specify an owner: ", settings))
+		}
+	}
+
+	if minLength := settings.RequiredDescriptionLength(keyword); minLength > 0 {
+		policy = true
+
+		if length := descriptionLength(keyword, sComment); length < minLength {
+			messages = append(messages, newMessage(pos, keyword, RuleDescriptionLength, sComment,
+				fmt.Sprintf("Description is %d characters long, at least %d required: ", length, minLength), settings))
+		}
+	}
+
+	if settings.Roster != nil {
+		policy = true
+
+		if owner := findOwner(keyword, sComment, settings); owner != "" {
+			if _, ok := settings.Roster[strings.ToLower(owner)]; !ok {
+				messages = append(messages, newMessage(pos, keyword, RuleUnknownOwner, sComment,
+					fmt.Sprintf("Owner %s is not in the roster: ", owner), settings))
 			}
+		}
+	}
+
+	if settings.DeadlineMode == config.DeadlineModeExpired {
+		policy = true
+
+		if deadline, expired := findDeadline(sComment, settings); expired {
+			messages = append(messages, newMessage(pos, keyword, RuleDeadline, sComment,
+				fmt.Sprintf("Deadline %s has passed: ", deadline.Format(settings.DeadlineLayout)), settings))
+		}
+	}
+
+	return messages, policy
+}
+
+// annotate fills in the information parsed from the comment line to the messages.
+func annotate(messages []Message, keyword string, sComment []byte, settings *config.CompiledSettings) []Message {
+	owner := findOwner(keyword, sComment, settings)
+	priority := findPriority(keyword, sComment)
+	labels := findLabels(sComment)
+	effort := findEffort(sComment)
+	tracker, issue := findIssue(sComment, settings.IssuePatterns)
+	deadline, expired := findDeadline(sComment, settings)
+	snoozed := findSnooze(sComment, settings)
+
+	for i := range messages {
+		messages[i].Canonical = settings.Canonical(messages[i].Keyword)
+		messages[i].Severity = Severity(settings.Severity(messages[i].Keyword))
+		messages[i].Owner = owner
+		messages[i].Priority = priority
+		messages[i].Labels = labels
+		messages[i].Effort = effort
+		messages[i].Issue = issue
+		messages[i].IssueTracker = tracker
+		messages[i].Deadline = deadline
+		messages[i].Expired = expired
+
+		messages[i].Snoozed = snoozed
+
+		if expired && settings.DeadlineMode == config.DeadlineModeEscalate {
+			messages[i].Severity = SeverityError
+		}
+
+		if !snoozed.IsZero() && !snoozed.After(settings.Now) {
+			messages[i].Severity = SeverityError
+		}
+	}
 
-			pos := fset.Position(comment.Pos())
-			// trim the comment
-			const commentLimit = 40
-			if len(sComment) > commentLimit {
-				sComment = []byte(fmt.Sprintf("%.40s...", sComment))
+	return messages
+}
+
+// descriptionLength returns the number of characters of the description following the keyword and the owner
+// in parenthesis, without the surrounding punctuation, e.g. 0 for "FIXME!!!".
+func descriptionLength(keyword string, text []byte) int {
+	if len(keyword) > len(text) {
+		return 0
+	}
+
+	m := fixRe.FindSubmatch(text[len(keyword):])
+	if m == nil {
+		return 0
+	}
+
+	description := bytes.TrimFunc(m[2], func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r)
+	})
+
+	return utf8.RuneCount(description)
+}
+
+// findIssue returns the first issue reference found in the text together with the tracker it belongs to.
+func findIssue(text []byte, patterns []config.CompiledIssuePattern) (tracker, issue string) {
+	for _, p := range patterns {
+		if ref := p.Regexp.Find(text); ref != nil {
+			return p.Tracker, string(ref)
+		}
+	}
+
+	return "", ""
+}
+
+// findOwner returns the owner specified after the keyword, e.g. alice for TODO(alice).
+func findOwner(keyword string, text []byte, settings *config.CompiledSettings) string {
+	if len(keyword) > len(text) {
+		return ""
+	}
+
+	m := settings.OwnerRegexp.FindSubmatch(text[len(keyword):])
+
+	var owner string
+
+	switch {
+	case m == nil:
+		return ""
+	case len(m) > 1:
+		owner = string(m[1])
+	default:
+		owner = string(m[0])
+	}
+
+	// the priority in place of the owner, e.g. TODO(P1), isn't the owner
+	if priorityTagRe.MatchString(owner) {
+		return ""
+	}
+
+	return owner
+}
+
+var (
+	// priorityRe matches the parenthesis and the exclamation marks following the keyword.
+	priorityRe = regexp.MustCompile(`^\s*(?:\(([^)]*)\))?\s*(!*)`)
+	// priorityTagRe matches the priorities in the parenthesis.
+	priorityTagRe = regexp.MustCompile(`^[Pp][0-3]$`)
+)
+
+// findPriority returns the priority specified after the keyword, e.g. P1 for TODO(alice, P1) or TODO!,
+// empty if there is none.
+func findPriority(keyword string, text []byte) string {
+	if len(keyword) > len(text) {
+		return ""
+	}
+
+	m := priorityRe.FindSubmatch(text[len(keyword):])
+	if m == nil {
+		return ""
+	}
+
+	bangs := len(m[2])
+
+	for _, tag := range strings.Split(string(m[1]), ",") {
+		tag = strings.TrimSpace(tag)
+
+		switch {
+		case priorityTagRe.MatchString(tag):
+			return strings.ToUpper(tag)
+		case tag != "" && strings.Trim(tag, "!") == "":
+			bangs = len(tag)
+		}
+	}
+
+	switch {
+	case bangs > 1:
+		return "P0"
+	case bangs == 1:
+		return "P1"
+	default:
+		return ""
+	}
+}
+
+// labelRe matches the hashtags starting with a letter, e.g. #perf but not the #123 issue references,
+// and the labels in brackets, e.g. [perf].
+var labelRe = regexp.MustCompile(`(?:^|\s)#([A-Za-z][\w-]*)|\[([A-Za-z][\w-]*)\]`)
+
+// findLabels returns the distinct labels in the text, nil if there are none.
+func findLabels(text []byte) []string {
+	var labels []string
+
+	for _, m := range labelRe.FindAllSubmatch(text, -1) {
+		label := string(m[1])
+		if label == "" {
+			label = string(m[2])
+		}
+
+		duplicate := false
+
+		for _, l := range labels {
+			duplicate = duplicate || l == label
+		}
+
+		if !duplicate {
+			labels = append(labels, label)
+		}
+	}
+
+	return labels
+}
+
+// Working time of the effort estimates, the days and weeks of the estimates are working days and weeks.
+const (
+	EffortDay  = 8 * time.Hour
+	EffortWeek = 5 * EffortDay
+)
+
+// effortRe matches the effort estimates in brackets, e.g. [4h], [3d] or [2w].
+var effortRe = regexp.MustCompile(`\[(\d+)([hdw])\]`)
+
+// effortUnits are the durations of the effort estimate units.
+var effortUnits = map[string]time.Duration{"h": time.Hour, "d": EffortDay, "w": EffortWeek}
+
+// findEffort returns the first effort estimate in the text, zero if there is none.
+func findEffort(text []byte) time.Duration {
+	m := effortRe.FindSubmatch(text)
+	if m == nil {
+		return 0
+	}
+
+	n, err := strconv.Atoi(string(m[1]))
+	if err != nil {
+		return 0
+	}
+
+	return time.Duration(n) * effortUnits[string(m[2])]
+}
+
+// snoozeRe matches the snooze annotations, e.g. [snooze:2025-09-01].
+var snoozeRe = regexp.MustCompile(`\[snooze:\s*([^\]]*?)\s*\]`)
+
+// findSnooze returns the date of the first snooze annotation in the text, zero if there is none
+// or the date cannot be parsed.
+func findSnooze(text []byte, settings *config.CompiledSettings) time.Time {
+	m := snoozeRe.FindSubmatch(text)
+	if m == nil {
+		return time.Time{}
+	}
+
+	snoozed, err := time.ParseInLocation(settings.DeadlineLayout, string(m[1]), settings.Now.Location())
+	if err != nil {
+		return time.Time{}
+	}
+
+	return snoozed
+}
+
+// findDeadline returns the first deadline found in the text and whether it has already passed,
+// the dates of the snooze annotations aren't deadlines.
+func findDeadline(text []byte, settings *config.CompiledSettings) (time.Time, bool) {
+	if bytes.Contains(text, []byte("[snooze:")) {
+		text = snoozeRe.ReplaceAll(text, nil)
+	}
+
+	for _, candidate := range settings.DeadlineRegexp.FindAll(text, -1) {
+		deadline, err := time.ParseInLocation(settings.DeadlineLayout, string(candidate), settings.Now.Location())
+		if err != nil {
+			continue
+		}
+
+		return deadline, settings.Now.After(deadline)
+	}
+
+	return time.Time{}, false
+}
+
+// listMarkerRe matches the markers of the Markdown list items, e.g. "- " or "1. ", at the start of the line.
+var listMarkerRe = regexp.MustCompile(`^(?:[-*+]|\d{1,9}[.)])[ \t]+`)
+
+// trimLine removes the list marker, if TrimListMarkers is set, and the @ of the annotation from the start
+// of the line, e.g. "- TODO: ..." or "@todo ...".
+func trimLine(line commentLine, settings *config.CompiledSettings) commentLine {
+	if settings.TrimListMarkers {
+		if m := listMarkerRe.Find(line.text); m != nil {
+			line = line.from(len(m))
+		}
+	}
+
+	return trimAnnotation(line)
+}
+
+// trimAnnotation removes the @ of Javadoc style annotations, e.g. @todo, from the start of the line.
+func trimAnnotation(line commentLine) commentLine {
+	if len(line.text) > 1 && line.text[0] == '@' {
+		line.text = line.text[1:]
+		line.offset++
+	}
+
+	return line
+}
+
+// commentLine is a single line of a comment.
+type commentLine struct {
+	// text of the line with the surrounding white space trimmed.
+	text []byte
+	// line is the index of the line within the comment.
+	line int
+	// offset of the text from the start of the line,
+	// or from the start of the comment for the first line.
+	offset int
+	// continued is set if the continuation lines are merged into the text, see groupLines.
+	continued bool
+	// end is the position following the last continuation line merged into the text.
+	end token.Position
+}
+
+// from returns the rest of the line starting at the offset within its text.
+func (l commentLine) from(start int) commentLine {
+	l.offset += start
+	l.text = l.text[start:]
+
+	return l
+}
+
+// commentLines splits the comment text into lines, omitting the comment markers. Text which isn't
+// a comment, e.g. from synthetic syntax trees, has no lines and a block comment which isn't closed
+// is read up to the end of the text. The comments of other than Go files, see rawComments, are split too.
+func commentLines(commentText string) []commentLine {
+	markerSize := 2
+
+	var body string
+
+	switch {
+	case strings.HasPrefix(commentText, "//"):
+		body = commentText[markerSize:]
+	case strings.HasPrefix(commentText, "/*"):
+		body = strings.TrimSuffix(commentText[markerSize:], "*/")
+	default:
+		for _, marker := range rawMarkers {
+			if strings.HasPrefix(commentText, marker[0]) {
+				markerSize = len(marker[0])
+				body = strings.TrimSuffix(commentText[markerSize:], marker[1])
+
+				break
 			}
+		}
 
-			comments = append(comments, Message{
-				Pos: pos,
-				Message: fmt.Sprintf(
-					"%s:%d: Line does not match the expected format: %s, %q",
-					filepath.Clean(pos.Filename),
-					pos.Line+lineNum,
-					formatPattern,
-					sComment,
-				),
-			})
+		if body == "" {
+			return nil
+		}
+	}
 
-			break
+	var (
+		lines []commentLine
+		block = strings.HasPrefix(commentText, "/*")
+	)
+
+	for i, line := range strings.Split(body, "\n") {
+		offset := len(line) - len(strings.TrimLeftFunc(line, unicode.IsSpace))
+		if i == 0 {
+			offset += markerSize
+		}
+
+		text := bytes.TrimSpace([]byte(line))
+		if block {
+			trimmed := trimDecoration(text)
+			offset += len(text) - len(trimmed)
+			text = trimmed
 		}
+
+		lines = append(lines, commentLine{
+			text:   text,
+			line:   i,
+			offset: offset,
+		})
 	}
 
-	return comments
+	return lines
 }
 
-func isFormatted(regularExpression, input string) bool {
-	regex := regexp.MustCompile(regularExpression)
-	if regex.MatchString(input) {
-		return true
-	} else {
-		return false
+// trimDecoration removes the leading asterisk of the lines of the block comments in the classic formatting,
+// e.g. " * TODO: ...", and the white space following it.
+func trimDecoration(text []byte) []byte {
+	if len(text) == 0 || text[0] != '*' || len(text) > 1 && text[1] != ' ' && text[1] != '\t' {
+		return text
 	}
+
+	return bytes.TrimLeft(text[1:], " \t")
 }
 
-func extractComment(commentText string) string {
-	switch commentText[1] {
-	case '/':
-		commentText = commentText[2:]
-		if len(commentText) > 0 && commentText[0] == ' ' {
-			commentText = commentText[1:]
+// groupLines returns the lines of the comments of the group. If Multiline is set, the lines continuing the lines
+// starting with keywords, until an empty line or a line starting with a keyword, are merged into them and left
+// out: the following lines of block comments and the more indented line comments following line comments.
+func groupLines(group *ast.CommentGroup, fset *token.FileSet, settings *config.CompiledSettings) [][]commentLine {
+	lines := make([][]commentLine, len(group.List))
+	for i, c := range group.List {
+		lines[i] = commentLines(c.Text)
+	}
+
+	if !settings.Multiline {
+		return lines
+	}
+
+	continues := func(line commentLine) bool {
+		return len(line.text) > 0 && !startsWithKeyword(line, settings)
+	}
+
+	for i, c := range group.List {
+		if isDirective(c.Text) {
+			continue
+		}
+
+		for j := 0; j < len(lines[i]); j++ {
+			line := lines[i][j]
+			if !startsWithKeyword(line, settings) {
+				continue
+			}
+
+			if strings.HasPrefix(c.Text, "//") {
+				for k := i + 1; k < len(group.List) && strings.HasPrefix(group.List[k].Text, "//") &&
+					!isDirective(group.List[k].Text) && len(lines[k]) == 1 &&
+					lines[k][0].offset > line.offset && continues(lines[k][0]); k++ {
+					line = line.merge(group.List[k], fset, lines[k][0])
+					lines[k] = nil
+				}
+			} else {
+				for j+1 < len(lines[i]) && continues(lines[i][j+1]) {
+					line = line.merge(c, fset, lines[i][j+1])
+					lines[i] = append(lines[i][:j+1], lines[i][j+2:]...)
+				}
+			}
+
+			lines[i][j] = line
 		}
-	case '*':
-		commentText = commentText[2 : len(commentText)-2]
 	}
 
-	return commentText
+	return lines
 }
 
-func hasAlphanumRuneAdjacent(rest []byte) bool {
-	if len(rest) == 0 {
-		return false
+// continuedEnd returns the end of the comment line on the line merged with its continuation lines, see groupLines.
+func continuedEnd(comment *ast.Comment, fset *token.FileSet, lines []commentLine, line int) (token.Position, bool) {
+	for _, l := range lines {
+		if l.continued && linePosition(comment, fset, l).Line == line {
+			return l.end, true
+		}
 	}
 
-	switch rest[0] { // most common cases
-	case ':', ' ', '(':
-		return false
+	return token.Position{}, false
+}
+
+// startsWithKeyword reports whether the line starts with a keyword.
+func startsWithKeyword(line commentLine, settings *config.CompiledSettings) bool {
+	_, start, ok := settings.MatchKeyword(trimLine(line, settings).text)
+	return ok && start == 0
+}
+
+// merge returns the line with the text of the continuation line of the comment appended.
+func (l commentLine) merge(comment *ast.Comment, fset *token.FileSet, next commentLine) commentLine {
+	text := make([]byte, 0, len(l.text)+1+len(next.text))
+	l.text = append(append(append(text, l.text...), ' '), next.text...)
+	l.continued = true
+	l.end = textEnd(linePosition(comment, fset, next), next.text)
+
+	return l
+}
+
+// linePosition returns position of the line text within the file.
+func linePosition(comment *ast.Comment, fset *token.FileSet, line commentLine) token.Position {
+	if line.line == 0 {
+		return fset.Position(comment.Pos() + token.Pos(line.offset))
+	}
+
+	// lines are computed from the file as the comment text doesn't contain carriage returns
+	start := fset.Position(comment.Pos())
+	if tf := fset.File(comment.Pos()); tf != nil && start.Line+line.line <= tf.LineCount() {
+		return fset.Position(tf.LineStart(start.Line+line.line) + token.Pos(line.offset))
 	}
 
-	r, _ := utf8.DecodeRune(rest)
+	start.Line += line.line
+	start.Column = line.offset + 1
 
-	return unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsDigit(r)
+	return start
 }
 
 // Run runs the godox linter on given file.
 // Godox searches for comments starting with given keywords and reports them.
+// Comments annotated with a nolint:godox or godox:ignore[:keyword,...] directive are suppressed.
+//
+// Run compiles the settings on every call and panics if they are invalid or the message template fails,
+// use GoDoxSettings.Compile together with RunCompiledContext to handle the errors.
 func Run(file *ast.File, fset *token.FileSet, settings *config.GoDoxSettings) []Message {
-	var messages []Message
 	if len(settings.Keywords) == 0 {
-		settings.Keywords = defaultKeywords
+		settings.Keywords = config.DefaultKeywords
 	}
 
-	for _, c := range file.Comments {
-		for _, ci := range c.List {
-			if settings.Format {
-				messages = append(messages, getMessagesFormat(ci, fset, settings.FormatRules)...)
-			} else {
-				messages = append(messages, getMessages(ci, fset, settings.Keywords)...)
+	compiled, err := settings.Compile()
+	if err != nil {
+		panic(err)
+	}
+
+	return RunCompiled(file, fset, compiled)
+}
+
+// RunCompiled runs the godox linter on given file using compiled settings.
+// It panics if the message template fails, use RunCompiledContext to handle the errors.
+func RunCompiled(file *ast.File, fset *token.FileSet, settings *config.CompiledSettings) []Message {
+	messages, err := RunCompiledContext(context.Background(), file, fset, settings)
+	if err != nil {
+		panic(err)
+	}
+
+	return messages
+}
+
+// RunFunc runs the godox linter on given file, calling fn with the messages as they are found
+// until fn returns false. Settings are compiled, and errors panic, the same way as by Run.
+func RunFunc(file *ast.File, fset *token.FileSet, settings *config.GoDoxSettings, fn func(Message) bool) {
+	if len(settings.Keywords) == 0 {
+		settings.Keywords = config.DefaultKeywords
+	}
+
+	compiled, err := settings.Compile()
+	if err != nil {
+		panic(err)
+	}
+
+	if _, err := RunCompiledFunc(file, fset, compiled, fn); err != nil {
+		panic(err)
+	}
+}
+
+// RunContext runs the godox linter on given file like Run, returning invalid settings as errors.
+// The scan stops with the context error once the context is done.
+func RunContext(ctx context.Context, file *ast.File, fset *token.FileSet, settings *config.GoDoxSettings) ([]Message, error) {
+	if len(settings.Keywords) == 0 {
+		settings.Keywords = config.DefaultKeywords
+	}
+
+	compiled, err := settings.Compile()
+	if err != nil {
+		return nil, err
+	}
+
+	return RunCompiledContext(ctx, file, fset, compiled)
+}
+
+// RunCompiledContext runs the godox linter on given file using compiled settings,
+// the scan stops with the context error once the context is done.
+func RunCompiledContext(ctx context.Context, file *ast.File, fset *token.FileSet, settings *config.CompiledSettings) ([]Message, error) {
+	var messages []Message
+
+	_, err := runCompiled(ctx, file, fset, settings, func(m Message) bool {
+		messages = append(messages, m)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// RunCompiledFunc runs the godox linter on given file using compiled settings, calling fn with the messages
+// as they are found until fn returns false. It reports whether all the messages were passed to fn
+// and the error of the message template.
+func RunCompiledFunc(file *ast.File, fset *token.FileSet, settings *config.CompiledSettings, fn func(Message) bool) (bool, error) {
+	return runCompiled(context.Background(), file, fset, settings, fn)
+}
+
+// runCompiled calls fn with the messages of the file until it returns false or the context is done,
+// checking the context before every comment group.
+func runCompiled(ctx context.Context, file *ast.File, fset *token.FileSet, settings *config.CompiledSettings,
+	fn func(Message) bool,
+) (bool, error) {
+	if filename := fset.Position(file.Package).Filename; filename != "" && !settings.IncludesPath(filename) {
+		return true, nil
+	}
+
+	if settings.SkipGenerated && isGenerated(file) {
+		return true, nil
+	}
+
+	groups, lines := suppressions(file, fset)
+	disabled := disabledRules(file, fset)
+
+	var docs map[*ast.CommentGroup]bool
+	if settings.ForbidExportedDocs {
+		docs = exportedDocs(file)
+	}
+
+	var licenses map[*ast.CommentGroup]bool
+	if settings.SkipLicenseHeaders {
+		licenses = licenseHeaders(file)
+	}
+
+	comments := cgoComments(file)
+
+	literals := make(map[*ast.CommentGroup]bool)
+	if settings.ScanStrings {
+		for _, g := range stringLiterals(file) {
+			literals[g] = true
+			comments = append(comments[:len(comments):len(comments)], g)
+		}
+	}
+
+	for _, c := range comments {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+
+		if licenses[c] {
+			continue
+		}
+
+		decl, kind := enclosingDeclaration(file, fset, c.Pos())
+		commented := groupLines(c, fset, settings)
+
+		for i, ci := range c.List {
+			var found []Message
+
+			switch {
+			case literals[c]:
+				found = getMessagesString(ci, fset, settings)
+			case isDirective(ci.Text):
+				continue
+			case docs[c]:
+				found = getMessagesExportedDoc(ci, commented[i], fset, settings)
+			case settings.Format || settings.Combined:
+				found = getMessagesFormat(ci, commented[i], fset, settings)
+			default:
+				found = getMessages(ci, commented[i], fset, settings)
+			}
+
+			for _, m := range found {
+				if end, ok := continuedEnd(ci, fset, commented[i], m.Line); ok {
+					m.TextEnd = end
+				}
+
+				if settings.IsRuleDisabled(m.RuleID) || !settings.HasPriority(m.Priority) || !settings.HasLabel(m.Labels) {
+					continue
+				}
+
+				if groups[c].suppresses(m.Keyword, m.Canonical) || lines[m.Line].suppresses(m.Keyword, m.Canonical) ||
+					disabled.disables(m.Line, m.RuleID, m.FormatRule) || m.Snoozed.After(settings.Now) {
+					if !settings.ReportSuppressed {
+						continue
+					}
+
+					m.Suppressed = true
+				}
+
+				m.Declaration, m.DeclarationKind = decl, kind
+
+				if settings.Template != nil {
+					templated, err := applyTemplate(m, settings.Template)
+					if err != nil {
+						return false, err
+					}
+
+					m = templated
+				}
+
+				if !fn(m) {
+					return false, nil
+				}
 			}
 		}
 	}
 
-	return messages
+	return true, nil
 }
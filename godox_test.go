@@ -1,12 +1,21 @@
 package godox_test
 
 import (
+	"context"
+	"errors"
 	"flag"
+	"fmt"
+	"go/ast"
 	"go/parser"
 	"go/token"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"text/template"
+	"time"
 
 	"github.com/matoous/godox"
 	"github.com/matoous/godox/config"
@@ -69,6 +78,15 @@ func TestParse(t *testing.T) {
 		{
 			path: "./fixtures/04",
 		},
+		{
+			path: "./fixtures/06",
+			result: []string{
+				`fixtures/06/example1.go:3: Line contains TODO/BUG/FIXME: "TODO: reported (Line 3)"`,
+				`fixtures/06/example1.go:12: Line contains TODO/BUG/FIXME: "FIXME: reported (Line 12)"`,
+				`fixtures/06/example1.go:18: Line contains TODO/BUG/FIXME: "TODO: reported, other linter (Line 18) /..."`,
+				`fixtures/06/example1.go:20: Line contains TODO/BUG/FIXME: "FIXME: reported (Line 20) //nolinter"`,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -186,3 +204,2698 @@ func TestFormat(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatForbid(t *testing.T) {
+	t.Parallel()
+
+	const src = `package main
+
+// TODO(alice): temporary workaround
+// TODO(bob): ask build.corp.example.com
+// TODO: missing owner
+// FIXME: a temporary fix
+// FIXME: fine
+`
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages := godox.Run(file, fset, &config.GoDoxSettings{
+		Format: true,
+		FormatRules: []config.GoDoxFormatRule{
+			{Keyword: "TODO", RegularExpression: `^TODO\(\w+\): `, Forbid: `(?i)\btemporary\b|\.corp\.example\.com`},
+			{Keyword: "FIXME", Forbid: `(?i)\btemporary\b`},
+		},
+	})
+
+	expected := []string{
+		`main.go:3: Line matches the forbidden pattern: (?i)\btemporary\b|\.corp\.example\.com, "TODO(alice): temporary workaround"`,
+		`main.go:4: Line matches the forbidden pattern: (?i)\btemporary\b|\.corp\.example\.com, "TODO(bob): ask build.corp.example.com"`,
+		`main.go:5: Line does not match the expected format: ^TODO\(\w+\): , "TODO: missing owner"`,
+		`main.go:6: Line matches the forbidden pattern: (?i)\btemporary\b, "FIXME: a temporary fix"`,
+	}
+
+	actual := make([]string, 0, len(messages))
+	for _, m := range messages {
+		actual = append(actual, m.Message)
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("not equal\nexpected: %v\nactual: %v", expected, actual)
+	}
+
+	if messages[0].RuleID != godox.RuleForbiddenPattern {
+		t.Errorf("not equal\nexpected: %v\nactual: %v", godox.RuleForbiddenPattern, messages[0].RuleID)
+	}
+}
+
+func TestDisabledRules(t *testing.T) {
+	t.Parallel()
+
+	const src = `package main
+
+// TODO: missing owner
+// FIXME fix the format
+
+//godox:disable owner,fixme-format
+// TODO: disabled owner
+// FIXME disabled format
+//godox:enable owner
+
+// TODO: enabled owner
+// FIXME still disabled format
+`
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules := []config.GoDoxFormatRule{
+		{Keyword: "TODO", RegularExpression: `^TODO: `},
+		{ID: "fixme-format", Keyword: "FIXME", RegularExpression: `^FIXME: `},
+	}
+
+	tests := []struct {
+		name     string
+		settings config.GoDoxSettings
+		expected []string
+	}{
+		{
+			name:     "directives",
+			settings: config.GoDoxSettings{Format: true, FormatRules: rules, RequireOwner: true},
+			expected: []string{"3:owner:", "4:format:fixme-format", "11:owner:"},
+		},
+		{
+			name:     "suppressed by directives",
+			settings: config.GoDoxSettings{Format: true, FormatRules: rules, RequireOwner: true, ReportSuppressed: true},
+			expected: []string{
+				"3:owner:", "4:format:fixme-format", "7:owner::suppressed", "8:format:fixme-format:suppressed",
+				"11:owner:", "12:format:fixme-format:suppressed",
+			},
+		},
+		{
+			name:     "disabled rules",
+			settings: config.GoDoxSettings{Format: true, FormatRules: rules, RequireOwner: true, DisabledRules: []string{"owner"}},
+			expected: []string{"4:format:fixme-format"},
+		},
+		{
+			name: "disabled format rule",
+			settings: config.GoDoxSettings{
+				Format:        true,
+				FormatRules:   []config.GoDoxFormatRule{{Keyword: "TODO", RegularExpression: `^TODO\(`}, rules[1]},
+				DisabledRules: []string{"format-0"},
+			},
+			expected: []string{"4:format:fixme-format"},
+		},
+	}
+
+	for _, tt := range tests {
+		var actual []string
+
+		for _, m := range godox.Run(file, fset, &tt.settings) {
+			s := fmt.Sprintf("%d:%s:%s", m.Line, m.RuleID, m.FormatRule)
+			if m.Suppressed {
+				s += ":suppressed"
+			}
+
+			actual = append(actual, s)
+		}
+
+		if !reflect.DeepEqual(actual, tt.expected) {
+			t.Errorf("%s: not equal\nexpected: %v\nactual: %v", tt.name, tt.expected, actual)
+		}
+	}
+}
+
+func TestFormatDefaultRule(t *testing.T) {
+	t.Parallel()
+
+	const src = `package main
+
+// TODO(alice): fine
+// TODO: Without owner
+// FIXME: Fine
+// FIXME: lower case
+// HACK: lower case alias
+// BUG(bob): lower case
+`
+
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	settings := config.GoDoxSettings{
+		Format:  true,
+		Aliases: map[string]string{"HACK": "FIXME"},
+		FormatRules: []config.GoDoxFormatRule{
+			{Keyword: config.DefaultFormatRuleKeyword, RegularExpression: `^\w+(?:\(\w+\))?: [A-Z]`},
+			{Keyword: "TODO", RegularExpression: `^TODO\(\w+\): `},
+		},
+	}
+
+	var actual []string
+	for _, m := range godox.Run(f, fset, &settings) {
+		actual = append(actual, fmt.Sprintf("%d %s %s", m.Line, m.Keyword, m.FormatRule))
+	}
+
+	expected := []string{"4 TODO format-1", "6 FIXME format-0", "7 HACK format-0", "8 BUG format-0"}
+	if fmt.Sprint(actual) != fmt.Sprint(expected) {
+		t.Errorf("not equal\nexpected: %q\nactual: %q", expected, actual)
+	}
+}
+
+func TestFormatFix(t *testing.T) {
+	t.Parallel()
+
+	const src = `package main
+
+// todo fix this
+//TODO fix
+// TODO(alice) - add tests
+/* Todo: block */
+// TODO: already fine
+// TODO
+// TODOS are not fixed
+// TODO(%s) cannot be fixed
+func main() {}
+`
+
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages := godox.Run(f, fset, &config.GoDoxSettings{
+		Format: true,
+		FormatRules: []config.GoDoxFormatRule{
+			{Keyword: "TODO", RegularExpression: `^TODO(\([a-z]+\))?: \w`},
+		},
+	})
+
+	expected := []string{
+		"3:4 todo fix this -> TODO: fix this",
+		"4:3 TODO fix ->  TODO: fix",
+		"5:4 TODO(alice) - add tests -> TODO(alice): add tests",
+		"6:4 Todo: block -> TODO: block",
+		"8:4 TODO -> ",
+		"10:4 TODO(%s) cannot be fixed -> ",
+	}
+
+	var actual []string
+	for _, m := range messages {
+		actual = append(actual, fmt.Sprintf("%d:%d %s -> %s", m.Line, m.Column, m.Text, m.Fix))
+
+		if m.Fix != "" && src[m.Pos.Offset:m.Pos.Offset+len(m.Text)] != m.Text {
+			t.Errorf("text %q is not at the offset %d", m.Text, m.Pos.Offset)
+		}
+	}
+
+	if fmt.Sprint(actual) != fmt.Sprint(expected) {
+		t.Errorf("not equal\nexpected: %q\nactual: %q", expected, actual)
+	}
+
+	// the fix template uses the capture groups of the fix pattern
+	messages = godox.Run(f, fset, &config.GoDoxSettings{
+		Format: true,
+		FormatRules: []config.GoDoxFormatRule{{
+			Keyword:           "TODO",
+			RegularExpression: `^TODO\[\w+\] \w`,
+			FixPattern:        `(?i)^todo\((\w+)\)\W*(.*)$`,
+			Fix:               "TODO[$1] $2",
+		}},
+	})
+
+	if fix := messages[2].Fix; fix != "TODO[alice] add tests" {
+		t.Errorf("not equal\nexpected: %s\nactual: %s", "TODO[alice] add tests", fix)
+	}
+
+	for i, m := range messages {
+		if i != 2 && m.Fix != "" {
+			t.Errorf("unexpected fix %q of %q", m.Fix, m.Text)
+		}
+	}
+}
+
+func TestRunFunc(t *testing.T) {
+	t.Parallel()
+
+	const src = "package main\n\n// TODO: first\n// FIXME: second\n// BUG: third\n"
+
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var texts []string
+
+	godox.RunFunc(f, fset, &config.GoDoxSettings{}, func(m godox.Message) bool {
+		texts = append(texts, m.Text)
+		return len(texts) < 2
+	})
+
+	if expected := []string{"TODO: first", "FIXME: second"}; fmt.Sprint(texts) != fmt.Sprint(expected) {
+		t.Errorf("not equal\nexpected: %q\nactual: %q", expected, texts)
+	}
+
+	compiled, err := (&config.GoDoxSettings{Keywords: []string{"BUG"}}).Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done, err := godox.RunCompiledFunc(f, fset, compiled, func(godox.Message) bool { return true })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !done {
+		t.Error("expected all messages to be passed")
+	}
+}
+
+func TestRunContext(t *testing.T) {
+	t.Parallel()
+
+	const src = "package main\n\n// TODO: first\n"
+
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages, err := godox.RunContext(context.Background(), f, fset, &config.GoDoxSettings{})
+	if err != nil || len(messages) != 1 {
+		t.Errorf("unexpected messages %v, error %v", messages, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := godox.RunContext(ctx, f, fset, &config.GoDoxSettings{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context canceled error, got %v", err)
+	}
+
+	if _, err := godox.RunContext(context.Background(), f, fset, &config.GoDoxSettings{DeadlineMode: "never"}); err == nil {
+		t.Error("expected error for invalid settings")
+	}
+}
+
+func TestRunSource(t *testing.T) {
+	t.Parallel()
+
+	// the source doesn't compile, with enough syntax errors to stop a parser which doesn't collect all of them
+	src := "package main\n\n// TODO: first\nfunc main() {\n" + strings.Repeat("\t)\n", 20) + "\t// FIXME: unsaved\n"
+
+	messages, err := godox.RunSource(context.Background(), "main.go", []byte(src), &config.GoDoxSettings{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var actual []string
+	for _, m := range messages {
+		actual = append(actual, m.String())
+	}
+
+	expected := []string{
+		`main.go:3: Line contains TODO/BUG/FIXME: "TODO: first"`,
+		`main.go:25: Line contains TODO/BUG/FIXME: "FIXME: unsaved"`,
+	}
+	if fmt.Sprint(actual) != fmt.Sprint(expected) {
+		t.Errorf("not equal\nexpected: %q\nactual: %q", expected, actual)
+	}
+
+	messages, err = godox.RunSource(context.Background(), "vendor/main.go", []byte(src), &config.GoDoxSettings{
+		ExcludePaths: []string{"vendor/**"},
+	})
+	if err != nil || len(messages) != 0 {
+		t.Errorf("expected excluded file to be skipped, got %v, error %v", messages, err)
+	}
+}
+
+func TestCgoPreamble(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		src    string
+		result []string
+	}{
+		{
+			name: "line comments",
+			src:  "package main\n\n// // TODO: C comment\n// int x; /* FIXME: block\n// continued */ // BUG: after\nimport \"C\"\n",
+			result: []string{
+				`main.go:3: Line contains TODO/BUG/FIXME: "TODO: C comment"`,
+				`main.go:4: Line contains TODO/BUG/FIXME: "FIXME: block"`,
+				`main.go:5: Line contains TODO/BUG/FIXME: "BUG: after"`,
+			},
+		},
+		{
+			name:   "block comment",
+			src:    "package main\n\n/*\nTODO: C code\nchar *s = \"// TODO: string\";\n// FIXME: C comment\n*/\nimport \"C\"\n",
+			result: []string{`main.go:6: Line contains TODO/BUG/FIXME: "FIXME: C comment"`},
+		},
+		{
+			name:   "grouped import",
+			src:    "package main\n\n// TODO: doc\nimport (\n\t\"fmt\"\n\n\t// TODO: C code\n\t\"C\"\n)\n",
+			result: []string{`main.go:3: Line contains TODO/BUG/FIXME: "TODO: doc"`},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt //nolint // reason tt is ok on this context
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			for _, engine := range []string{config.EngineParser, config.EngineFast} {
+				messages, err := godox.RunSource(context.Background(), "main.go", []byte(tt.src),
+					&config.GoDoxSettings{Engine: engine})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				var actual []string
+				for _, m := range messages {
+					actual = append(actual, m.String())
+				}
+
+				if fmt.Sprint(actual) != fmt.Sprint(tt.result) {
+					t.Errorf("%s: not equal\nexpected: %q\nactual: %q", engine, tt.result, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestRawComments(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		filename string
+		src      string
+		result   []string
+	}{
+		{
+			filename: "api/service.proto",
+			src:      "syntax = \"proto3\";\n\n// TODO: version the service\nservice Owners { /* FIXME: paging */ }\n",
+			result: []string{
+				`api/service.proto:3:4: Line contains TODO/BUG/FIXME: "TODO: version the service"`,
+				`api/service.proto:4:21: Line contains TODO/BUG/FIXME: "FIXME: paging"`,
+			},
+		},
+		{
+			filename: "deploy.yaml",
+			src:      "# TODO: drop the replicas\nreplicas: 2 # FIXME: scale\r\n",
+			result: []string{
+				`deploy.yaml:1:3: Line contains TODO/BUG/FIXME: "TODO: drop the replicas"`,
+				`deploy.yaml:2:15: Line contains TODO/BUG/FIXME: "FIXME: scale"`,
+			},
+		},
+		{
+			filename: "README.md",
+			src:      "# TODO list\n\n<!--\n  TODO: document the flags\n-->\n",
+			result:   []string{`README.md:4:3: Line contains TODO/BUG/FIXME: "TODO: document the flags"`},
+		},
+		{
+			filename: "schema.sql",
+			src:      "SELECT 1; -- BUG: wrong answer\n/* TODO: views */\n",
+			result: []string{
+				`schema.sql:1:14: Line contains TODO/BUG/FIXME: "BUG: wrong answer"`,
+				`schema.sql:2:4: Line contains TODO/BUG/FIXME: "TODO: views"`,
+			},
+		},
+		{
+			filename: "scripts/run",
+			src:      "#!/bin/sh\n# TODO: set -e\n// FIXME: unknown\n",
+			result: []string{
+				`scripts/run:2:3: Line contains TODO/BUG/FIXME: "TODO: set -e"`,
+				`scripts/run:3:4: Line contains TODO/BUG/FIXME: "FIXME: unknown"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt //nolint // reason tt is ok on this context
+		t.Run(tt.filename, func(t *testing.T) {
+			t.Parallel()
+
+			messages, err := godox.RunSource(context.Background(), tt.filename, []byte(tt.src), &config.GoDoxSettings{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var actual []string
+			for _, m := range messages {
+				actual = append(actual, fmt.Sprintf("%s:%d:%d: %s", m.Pos.Filename, m.Line, m.Column,
+					strings.TrimPrefix(m.Message, fmt.Sprintf("%s:%d: ", m.Pos.Filename, m.Line))))
+			}
+
+			if fmt.Sprint(actual) != fmt.Sprint(tt.result) {
+				t.Errorf("not equal\nexpected: %q\nactual: %q", tt.result, actual)
+			}
+		})
+	}
+}
+
+func TestDirectives(t *testing.T) {
+	t.Parallel()
+
+	src := "//go:build linux\n\npackage main\n\n//go:generate stringer -type Kind\n//go:linkname now time.now\n" +
+		"//export Answer\n//nolint:errcheck\n// go: not a directive\n//lint:ignore reason\n//todo:fix the thing\n//line main.go:1\n"
+
+	for _, engine := range []string{config.EngineParser, config.EngineFast} {
+		messages, err := godox.RunSource(context.Background(), "main.go", []byte(src), &config.GoDoxSettings{
+			Keywords: []string{"go", "line", "export", "nolint", "lint", "todo"},
+			Engine:   engine,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var actual []string
+		for _, m := range messages {
+			actual = append(actual, m.String())
+		}
+
+		expected := []string{`main.go:9: Line contains go/line/export/nolint/lint/todo: "go: not a directive"`}
+		if fmt.Sprint(actual) != fmt.Sprint(expected) {
+			t.Errorf("%s: not equal\nexpected: %q\nactual: %q", engine, expected, actual)
+		}
+	}
+}
+
+func TestSkipLicenseHeaders(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		filename string
+		src      string
+		skip     bool
+		result   []string
+	}{
+		{
+			name:     "go",
+			filename: "main.go",
+			src: "// Copyright 2024 The Authors. All rights reserved.\n// NOTE: the terms apply to the changes too.\n\n" +
+				"//go:build linux\n\n// TODO: document the package\npackage main\n\n// BUG: reported\n",
+			skip: true,
+			result: []string{
+				`main.go:6: Line contains TODO/BUG/FIXME: "TODO: document the package"`,
+				`main.go:9: Line contains TODO/BUG/FIXME: "BUG: reported"`,
+			},
+		},
+		{
+			name:     "go without skipping",
+			filename: "main.go",
+			src:      "// SPDX-License-Identifier: MIT\n// TODO: reported\n\npackage main\n",
+			result:   []string{`main.go:2: Line contains TODO/BUG/FIXME: "TODO: reported"`},
+		},
+		{
+			name:     "assembly",
+			filename: "asm.s",
+			src:      "// Copyright (c) 2024 The Authors.\n// BUG: not reported\n\n// BUG: reported\n",
+			skip:     true,
+			result:   []string{`asm.s:4: Line contains TODO/BUG/FIXME: "BUG: reported"`},
+		},
+		{
+			name:     "no license",
+			filename: "main.go",
+			src:      "// TODO: not a license\npackage main\n",
+			skip:     true,
+			result:   []string{`main.go:1: Line contains TODO/BUG/FIXME: "TODO: not a license"`},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt //nolint // reason tt is ok on this context
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			messages, err := godox.RunSource(context.Background(), tt.filename, []byte(tt.src),
+				&config.GoDoxSettings{SkipLicenseHeaders: tt.skip})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var actual []string
+			for _, m := range messages {
+				actual = append(actual, m.String())
+			}
+
+			if fmt.Sprint(actual) != fmt.Sprint(tt.result) {
+				t.Errorf("not equal\nexpected: %q\nactual: %q", tt.result, actual)
+			}
+		})
+	}
+}
+
+func TestScanStrings(t *testing.T) {
+	t.Parallel()
+
+	src := "package main\n\nimport \"todo\"\n\ntype T struct {\n\tName string `json:\"todo\"`\n}\n\n" +
+		"const query = `SELECT name FROM owners\n  -- TODO: index the owners\n`\n\n" +
+		"func main() {\n\tprintln(\"\\tFIXME: escaped\") // TODO: comment\n}\n"
+
+	tests := []struct {
+		name     string
+		settings config.GoDoxSettings
+		result   []string
+	}{
+		{
+			name:   "disabled",
+			result: []string{`main.go:14:33: Line contains TODO/BUG/FIXME: "TODO: comment"`},
+		},
+		{
+			name:     "enabled",
+			settings: config.GoDoxSettings{ScanStrings: true},
+			result: []string{
+				`main.go:14:33: Line contains TODO/BUG/FIXME: "TODO: comment"`,
+				`main.go:10:6: String literal contains TODO: "TODO: index the owners" string-literal query`,
+				`main.go:14:13: String literal contains FIXME: "FIXME: escaped" string-literal main`,
+			},
+		},
+		{
+			name:     "disabled rule",
+			settings: config.GoDoxSettings{ScanStrings: true, DisabledRules: []string{godox.RuleStringLiteral}},
+			result:   []string{`main.go:14:33: Line contains TODO/BUG/FIXME: "TODO: comment"`},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt //nolint // reason tt is ok on this context
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			messages, err := godox.RunSource(context.Background(), "main.go", []byte(src), &tt.settings)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var actual []string
+
+			for _, m := range messages {
+				line := fmt.Sprintf("%s:%d:%d: %s", m.Pos.Filename, m.Line, m.Column,
+					strings.TrimPrefix(m.Message, fmt.Sprintf("%s:%d: ", m.Pos.Filename, m.Line)))
+				if m.RuleID == godox.RuleStringLiteral {
+					line += " " + m.RuleID + " " + m.Declaration
+				}
+
+				actual = append(actual, line)
+			}
+
+			if fmt.Sprint(actual) != fmt.Sprint(tt.result) {
+				t.Errorf("not equal\nexpected: %q\nactual: %q", tt.result, actual)
+			}
+		})
+	}
+}
+
+func TestMultiline(t *testing.T) {
+	t.Parallel()
+
+	src := "package main\n\n// TODO: drop the legacy client once\n//   all the services migrated, see #123\n" +
+		"// not a continuation\n\n/*\nFIXME: retry the requests\nwith backoff\n\nthe rest of the comment\n*/\n\n" +
+		"// TODO(alice): first\n//   TODO: second\n"
+
+	tests := []struct {
+		name      string
+		multiline bool
+		result    []string
+	}{
+		{
+			name: "disabled",
+			result: []string{
+				"3-3:37:TODO: drop the legacy client once|",
+				"8-8:26:FIXME: retry the requests|",
+				"14-14:22:TODO(alice): first|",
+				"15-15:18:TODO: second|",
+			},
+		},
+		{
+			name:      "enabled",
+			multiline: true,
+			result: []string{
+				"3-4:41:TODO: drop the legacy client once all the services migrated, see #123|#123",
+				"8-9:13:FIXME: retry the requests with backoff|",
+				"14-14:22:TODO(alice): first|",
+				"15-15:18:TODO: second|",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt //nolint // reason tt is ok on this context
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			messages, err := godox.RunSource(context.Background(), "main.go", []byte(src),
+				&config.GoDoxSettings{Multiline: tt.multiline})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var actual []string
+			for _, m := range messages {
+				actual = append(actual, fmt.Sprintf("%d-%d:%d:%s|%s", m.Line, m.TextEnd.Line, m.TextEnd.Column, m.Text, m.Issue))
+			}
+
+			if fmt.Sprint(actual) != fmt.Sprint(tt.result) {
+				t.Errorf("not equal\nexpected: %q\nactual: %q", tt.result, actual)
+			}
+		})
+	}
+}
+
+func TestBlockCommentDecorations(t *testing.T) {
+	t.Parallel()
+
+	src := "package main\n\n/*\n * Package docs.\n *\n * TODO: handle the timeouts\n *\tFIXME: tabs\n */\n\n" +
+		"/** BUG: Javadoc style */\n\n/*\n*ptr = nil // TODO: commented out\n*/\n"
+
+	messages, err := godox.RunSource(context.Background(), "main.go", []byte(src), &config.GoDoxSettings{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var actual []string
+	for _, m := range messages {
+		actual = append(actual, fmt.Sprintf("%d:%d:%s", m.Line, m.Column, m.Text))
+	}
+
+	expected := []string{"6:4:TODO: handle the timeouts", "7:4:FIXME: tabs", "10:5:BUG: Javadoc style"}
+	if fmt.Sprint(actual) != fmt.Sprint(expected) {
+		t.Errorf("not equal\nexpected: %q\nactual: %q", expected, actual)
+	}
+}
+
+func TestTrimListMarkers(t *testing.T) {
+	t.Parallel()
+
+	src := "package main\n\n// Remaining work:\n// - TODO: handle timeouts\n// 1. FIXME retry logic\n" +
+		"// 12) BUG: overflow\n//   * @todo nested item\n// -TODO: not a list item\n"
+
+	tests := []struct {
+		name   string
+		trim   bool
+		result []string
+	}{
+		{
+			name: "disabled",
+		},
+		{
+			name: "enabled",
+			trim: true,
+			result: []string{
+				"4:6:TODO: handle timeouts",
+				"5:7:FIXME retry logic",
+				"6:8:BUG: overflow",
+				"7:9:todo nested item",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt //nolint // reason tt is ok on this context
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			messages, err := godox.RunSource(context.Background(), "main.go", []byte(src),
+				&config.GoDoxSettings{TrimListMarkers: tt.trim})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var actual []string
+			for _, m := range messages {
+				actual = append(actual, fmt.Sprintf("%d:%d:%s", m.Line, m.Column, m.Text))
+			}
+
+			if fmt.Sprint(actual) != fmt.Sprint(tt.result) {
+				t.Errorf("not equal\nexpected: %q\nactual: %q", tt.result, actual)
+			}
+		})
+	}
+}
+
+func TestFastEngine(t *testing.T) {
+	t.Parallel()
+
+	filenames, err := filepath.Glob("fixtures/*/*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filenames = append(filenames, "godox.go", "godox_test.go", "suppress.go")
+
+	// the comments are grouped the same way, which matters for the suppressions
+	settings := config.GoDoxSettings{ReportSuppressed: true, SkipGenerated: true}
+
+	expected, err := godox.RunFiles(context.Background(), filenames, &settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the fast engine doesn't parse the declarations
+	for i := range expected {
+		expected[i].Declaration, expected[i].DeclarationKind = "", ""
+	}
+
+	settings.Engine = config.EngineFast
+
+	actual, err := godox.RunFiles(context.Background(), filenames, &settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("messages are not equal\nexpected: %v\nactual: %v", expected, actual)
+	}
+
+	// syntax errors don't stop the scan
+	dir, err := ioutil.TempDir("", "godox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "main.go")
+	if err := ioutil.WriteFile(filename, []byte("package main\n\nfunc main() {\n\t// TODO: unfinished\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	messages, err := godox.RunFiles(context.Background(), []string{filename}, &settings)
+	if err != nil || len(messages) != 1 {
+		t.Errorf("unexpected messages %v, error %v", messages, err)
+	}
+
+	if _, err := (&config.GoDoxSettings{Engine: "regexp"}).Compile(); err == nil {
+		t.Error("expected error for unknown engine")
+	}
+}
+
+func TestDeduplicate(t *testing.T) {
+	t.Parallel()
+
+	filenames := []string{"fixtures/03/main.go", "fixtures/01/example1.go", "fixtures/03/main.go"}
+
+	tests := []struct {
+		settings config.GoDoxSettings
+		expected []string
+	}{
+		{
+			settings: config.GoDoxSettings{Keywords: []string{"FIXME"}},
+			expected: []string{
+				"fixtures/01/example1.go:27", "fixtures/03/main.go:9", "fixtures/03/main.go:9",
+				"fixtures/03/main.go:16", "fixtures/03/main.go:16",
+			},
+		},
+		{
+			settings: config.GoDoxSettings{Keywords: []string{"FIXME"}, Deduplicate: true},
+			expected: []string{"fixtures/01/example1.go:27", "fixtures/03/main.go:9", "fixtures/03/main.go:16"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt //nolint // reason tt is ok on this context
+		t.Run(fmt.Sprint(tt.settings.Deduplicate), func(t *testing.T) {
+			t.Parallel()
+
+			messages, err := godox.RunFiles(context.Background(), filenames, &tt.settings)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var actual []string
+			for _, m := range messages {
+				actual = append(actual, fmt.Sprintf("%s:%d", m.Pos.Filename, m.Line))
+			}
+
+			if fmt.Sprint(actual) != fmt.Sprint(tt.expected) {
+				t.Errorf("not equal\nexpected: %q\nactual: %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestKeywordEnd(t *testing.T) {
+	t.Parallel()
+
+	const src = "package main\n\n// todo: lower case\nvar x = 1 // see x, FIXME: anywhere\n/* @BUG: x */\n// 要修正 later\n"
+
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages := godox.Run(f, fset, &config.GoDoxSettings{Anywhere: true, Keywords: []string{"TODO", "BUG", "FIXME", "要修正"}})
+
+	var actual []string
+	for _, m := range messages {
+		actual = append(actual, fmt.Sprintf("%d:%d-%d %s", m.Line, m.Column, m.End.Column, src[m.Pos.Offset:m.End.Offset]))
+	}
+
+	expected := []string{"3:4-8 todo", "4:21-26 FIXME", "5:5-8 BUG", "6:4-13 要修正"}
+	if fmt.Sprint(actual) != fmt.Sprint(expected) {
+		t.Errorf("not equal\nexpected: %q\nactual: %q", expected, actual)
+	}
+}
+
+func TestMaxMessageLength(t *testing.T) {
+	t.Parallel()
+
+	const src = "package main\n\n// TODO: a comment longer than the truncation limit of forty characters\n"
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	length := func(n int) *int { return &n }
+
+	tests := []struct {
+		length   *int
+		expected string
+	}{
+		{
+			expected: `main.go:3: Line contains TODO/BUG/FIXME: "TODO: a comment longer than the truncati..."`,
+		},
+		{
+			length:   length(10),
+			expected: `main.go:3: Line contains TODO/BUG/FIXME: "TODO: a co..."`,
+		},
+		{
+			length:   length(0),
+			expected: `main.go:3: Line contains TODO/BUG/FIXME: "TODO: a comment longer than the truncation limit of forty characters"`,
+		},
+	}
+
+	for _, tt := range tests {
+		messages := godox.Run(file, fset, &config.GoDoxSettings{MaxMessageLength: tt.length})
+		if len(messages) != 1 || messages[0].Message != tt.expected {
+			t.Errorf("not equal\nexpected: %v\nactual: %v", tt.expected, messages)
+			continue
+		}
+
+		if text := "TODO: a comment longer than the truncation limit of forty characters"; messages[0].Text != text {
+			t.Errorf("not equal\nexpected: %v\nactual: %v", text, messages[0].Text)
+		}
+	}
+}
+
+func TestMessageTemplate(t *testing.T) {
+	t.Parallel()
+
+	const src = `package main
+
+func (s *Server) Start() {
+	// TODO(alice): listen on #12
+}
+`
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages, err := godox.RunContext(context.Background(), file, fset, &config.GoDoxSettings{
+		MessageTemplate: "{{.Severity}}: {{.Keyword}} by {{.Owner}} in {{.Declaration}} ({{.Issue}}), column {{.Pos.Column}}",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "main.go:4: warning: TODO by alice in (*Server).Start (#12), column 5"
+	if len(messages) != 1 || messages[0].Message != expected {
+		t.Fatalf("not equal\nexpected: %v\nactual: %v", expected, messages)
+	}
+
+	if description := messages[0].Description(); description != "warning: TODO by alice in (*Server).Start (#12), column 5" {
+		t.Errorf("unexpected description %q", description)
+	}
+
+	_, err = (&config.GoDoxSettings{MessageTemplate: "{{.Missing}}"}).Compile()
+	if err == nil || !strings.HasPrefix(err.Error(), "message template: ") {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	compiled, err := (&config.GoDoxSettings{Keywords: []string{"TODO"}, MessageTemplate: "{{.Keyword}}"}).Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	compiled.Template = template.Must(template.New("message").Parse("{{index .Labels 0}}"))
+
+	_, err = godox.RunCompiledContext(context.Background(), file, fset, compiled)
+	if err == nil || !strings.HasPrefix(err.Error(), "message template: ") {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if _, err = godox.RunCompiledFunc(file, fset, compiled, func(godox.Message) bool { return true }); err == nil {
+		t.Error("expected the template error")
+	}
+}
+
+func TestDeclaration(t *testing.T) {
+	t.Parallel()
+
+	const src = `// TODO: package doc
+package main
+
+// TODO: before the import
+import "fmt"
+
+// FIXME: rename
+type Server struct {
+	// TODO: field
+	addr string
+}
+
+// TODO: method
+func (s *Server) Start() {
+	// FIXME: body
+	fmt.Println(s.addr)
+}
+
+func (s Server) Addr() string { return s.addr } // TODO: value receiver
+
+func (l *List[T]) Push(v T) {} // TODO: generic receiver
+
+var (
+	// TODO: grouped
+	a, b = 1, 2
+	c    = 3 // BUG: line comment
+)
+
+const d = 4 // TODO: const
+
+func main() {}
+
+// TODO: trailing
+`
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages := godox.Run(file, fset, &config.GoDoxSettings{})
+
+	expected := []string{
+		":",
+		":",
+		"Server:type",
+		"Server:type",
+		"(*Server).Start:method",
+		"(*Server).Start:method",
+		"Server.Addr:method",
+		"(*List[T]).Push:method",
+		"a, b:var",
+		"c:var",
+		"d:const",
+		":",
+	}
+
+	actual := make([]string, 0, len(messages))
+	for _, m := range messages {
+		actual = append(actual, m.Declaration+":"+m.DeclarationKind)
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("not equal\nexpected: %v\nactual: %v", expected, actual)
+	}
+}
+
+func TestCombined(t *testing.T) {
+	t.Parallel()
+
+	const src = `package main
+
+// TODO: matches the format
+// TODO fix the format
+// FIXME: no format rule
+`
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules := []config.GoDoxFormatRule{{Keyword: "TODO", RegularExpression: `^TODO: `}}
+
+	tests := []struct {
+		name     string
+		settings config.GoDoxSettings
+		expected []string
+	}{
+		{
+			name:     "format",
+			settings: config.GoDoxSettings{Format: true, FormatRules: rules},
+			expected: []string{"4:format"},
+		},
+		{
+			name:     "combined",
+			settings: config.GoDoxSettings{Combined: true, FormatRules: rules},
+			expected: []string{"4:format", "5:keyword"},
+		},
+	}
+
+	for _, tt := range tests {
+		var actual []string
+		for _, m := range godox.Run(file, fset, &tt.settings) {
+			actual = append(actual, fmt.Sprintf("%d:%s", m.Line, m.RuleID))
+		}
+
+		if !reflect.DeepEqual(actual, tt.expected) {
+			t.Errorf("%s: not equal\nexpected: %v\nactual: %v", tt.name, tt.expected, actual)
+		}
+	}
+}
+
+func TestReportAllMatches(t *testing.T) {
+	t.Parallel()
+
+	const src = `package main
+
+// TODO: first, FIXME: second
+// see TODO: not at the start
+// todo fix the format
+`
+
+	tests := []struct {
+		name     string
+		settings config.GoDoxSettings
+		expected []string
+	}{
+		{
+			name:     "first match",
+			settings: config.GoDoxSettings{},
+			expected: []string{"3:4:keyword", "5:4:keyword"},
+		},
+		{
+			name:     "all matches",
+			settings: config.GoDoxSettings{ReportAllMatches: true},
+			expected: []string{"3:4:keyword", "3:17:keyword", "5:4:keyword"},
+		},
+		{
+			name:     "all matches anywhere",
+			settings: config.GoDoxSettings{ReportAllMatches: true, Anywhere: true},
+			expected: []string{"3:4:keyword", "3:17:keyword", "4:8:keyword", "5:4:keyword"},
+		},
+		{
+			name: "first format rule",
+			settings: config.GoDoxSettings{
+				Format: true,
+				FormatRules: []config.GoDoxFormatRule{
+					{Keyword: "TODO", RegularExpression: `^TODO: `},
+					{Keyword: "TODO", RegularExpression: `^TODO\(\w+\)`},
+				},
+			},
+			expected: []string{"3:4:format", "5:4:format"},
+		},
+		{
+			name: "all format rules",
+			settings: config.GoDoxSettings{
+				Format:           true,
+				ReportAllMatches: true,
+				FormatRules: []config.GoDoxFormatRule{
+					{Keyword: "TODO", RegularExpression: `^TODO: `},
+					{Keyword: "TODO", RegularExpression: `^TODO\(\w+\)`},
+				},
+			},
+			expected: []string{"3:4:format", "5:4:format", "5:4:format"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt //nolint // reason tt is ok on this context
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fset := token.NewFileSet()
+
+			file, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var actual []string
+			for _, m := range godox.Run(file, fset, &tt.settings) {
+				actual = append(actual, fmt.Sprintf("%d:%d:%s", m.Line, m.Column, m.RuleID))
+			}
+
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("not equal\nexpected: %v\nactual: %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestMinDescriptionLength(t *testing.T) {
+	t.Parallel()
+
+	const src = `package main
+
+// TODO
+// TODO(alice): migrate to v2 client once released
+// TODO(alice): fix
+// FIXME!!!
+// HACK: alias of FIXME
+// BUG no minimum length
+`
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages := godox.Run(file, fset, &config.GoDoxSettings{
+		MinDescriptionLength: map[string]int{"TODO": 10, "fixme": 5},
+	})
+
+	expected := []string{
+		`main.go:3: Description is 0 characters long, at least 10 required: "TODO"`,
+		`main.go:5: Description is 3 characters long, at least 10 required: "TODO(alice): fix"`,
+		`main.go:6: Description is 0 characters long, at least 5 required: "FIXME!!!"`,
+		`main.go:8: Line contains TODO/BUG/FIXME: "BUG no minimum length"`,
+	}
+
+	actual := make([]string, 0, len(messages))
+	for _, m := range messages {
+		actual = append(actual, m.Message)
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("not equal\nexpected: %v\nactual: %v", expected, actual)
+	}
+}
+
+func TestForbiddenKeywords(t *testing.T) {
+	t.Parallel()
+
+	const src = `package main
+
+// TODO(#12): allowed with an issue
+// TODO: missing issue
+// FIXME(#13): forbidden even with an issue
+// HACK: alias of a forbidden keyword
+// KLUDGE: forbidden, not a keyword
+`
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages := godox.Run(file, fset, &config.GoDoxSettings{
+		ForbiddenKeywords:     []string{"FIXME", "kludge"},
+		Aliases:               map[string]string{"HACK": "FIXME"},
+		RequireIssueReference: true,
+	})
+
+	expected := []string{
+		"4:issue:warning",
+		"5:forbidden-keyword:error",
+		"6:forbidden-keyword:error",
+		"7:forbidden-keyword:error",
+	}
+
+	actual := make([]string, 0, len(messages))
+	for _, m := range messages {
+		actual = append(actual, fmt.Sprintf("%d:%s:%s", m.Line, m.RuleID, m.Severity))
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("not equal\nexpected: %v\nactual: %v", expected, actual)
+	}
+
+	if expected := `main.go:6: Line contains forbidden keyword HACK: "HACK: alias of a forbidden keyword"`; messages[2].Message != expected {
+		t.Errorf("not equal\nexpected: %v\nactual: %v", expected, messages[2].Message)
+	}
+}
+
+func TestForbidExportedDocs(t *testing.T) {
+	t.Parallel()
+
+	const src = `// Package main does things.
+// TODO: document
+package main
+
+// Server serves.
+// TODO: document the fields
+type Server struct{}
+
+// Start starts.
+// FIXME: blocks forever
+func (s *Server) Start() {
+	// TODO: inline
+}
+
+// stop stops.
+// TODO unexported
+func (s *Server) stop() {}
+
+// TODO: unexported type
+type handler struct{}
+
+// Serve serves on the unexported type.
+// TODO: not published
+func (h handler) Serve() {}
+
+const (
+	// Version of the server.
+	// BUG: outdated
+	Version = "1"
+)
+`
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages := godox.Run(file, fset, &config.GoDoxSettings{
+		ForbidExportedDocs: true,
+		Format:             true,
+		FormatRules:        []config.GoDoxFormatRule{{Keyword: "TODO", RegularExpression: `^TODO: \w`}},
+	})
+
+	expected := []string{
+		"2:exported-doc:error",
+		"6:exported-doc:error",
+		"10:exported-doc:error",
+		"16:format:warning",
+		"28:exported-doc:error",
+	}
+
+	actual := make([]string, 0, len(messages))
+	for _, m := range messages {
+		actual = append(actual, fmt.Sprintf("%d:%s:%s", m.Line, m.RuleID, m.Severity))
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("not equal\nexpected: %v\nactual: %v", expected, actual)
+	}
+
+	if expected := `main.go:10: Exported documentation contains FIXME: "FIXME: blocks forever"`; messages[2].Message != expected {
+		t.Errorf("not equal\nexpected: %v\nactual: %v", expected, messages[2].Message)
+	}
+}
+
+func TestLongKeywords(t *testing.T) {
+	t.Parallel()
+
+	const src = "package main\n\n// OPT\n// DEPR\n// DEPRECATE it\n// OPTIMIZE: loop\n"
+
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		settings config.GoDoxSettings
+	}{
+		{
+			name:     "keywords",
+			settings: config.GoDoxSettings{Keywords: []string{"DEPRECATED", "OPTIMIZE"}},
+		},
+		{
+			name: "format",
+			settings: config.GoDoxSettings{
+				Format: true,
+				FormatRules: []config.GoDoxFormatRule{
+					{Keyword: "DEPRECATED", RegularExpression: `^DEPRECATED:`},
+					{Keyword: "OPTIMIZE", RegularExpression: `^OPTIMIZE\(`},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt //nolint // reason tt is ok on this context
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			messages := godox.Run(f, fset, &tt.settings)
+			if len(messages) != 1 || messages[0].Keyword != "OPTIMIZE" || messages[0].Line != 6 {
+				t.Errorf("expected single OPTIMIZE message on line 6, got %v", messages)
+			}
+		})
+	}
+}
+
+func TestCaseSensitive(t *testing.T) {
+	t.Parallel()
+
+	const src = "package main\n\n// TODO: first\n// todo later maybe\n// fixme: second\n// Bug: third\n"
+
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		settings config.GoDoxSettings
+		lines    []int
+	}{
+		{
+			name:  "default",
+			lines: []int{3, 4, 5, 6},
+		},
+		{
+			name:     "global",
+			settings: config.GoDoxSettings{CaseSensitive: true},
+			lines:    []int{3},
+		},
+		{
+			name: "per keyword",
+			settings: config.GoDoxSettings{
+				CaseSensitive:         true,
+				CaseSensitiveKeywords: map[string]bool{"fixme": false},
+			},
+			lines: []int{3, 5},
+		},
+		{
+			name:     "only keyword",
+			settings: config.GoDoxSettings{CaseSensitiveKeywords: map[string]bool{"TODO": true}},
+			lines:    []int{3, 5, 6},
+		},
+		{
+			name: "format",
+			settings: config.GoDoxSettings{
+				Format:        true,
+				CaseSensitive: true,
+				FormatRules:   []config.GoDoxFormatRule{{Keyword: "TODO", RegularExpression: `^TODO\(`}},
+			},
+			lines: []int{3},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt //nolint // reason tt is ok on this context
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var lines []int
+			for _, m := range godox.Run(f, fset, &tt.settings) {
+				lines = append(lines, m.Line)
+			}
+
+			if fmt.Sprint(lines) != fmt.Sprint(tt.lines) {
+				t.Errorf("not equal\nexpected: %v\nactual: %v", tt.lines, lines)
+			}
+		})
+	}
+}
+
+func TestAnywhere(t *testing.T) {
+	t.Parallel()
+
+	const src = "package main\n\n// see handler.go, TODO: refactor\n/*\n  NOTE(x): fix later, FIXME\n*/\n// BUG: first\n// MYTODO\n"
+
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"main.go:3:20: Line contains TODO/BUG/FIXME: \"TODO: refactor\"",
+		"main.go:5:23: Line contains TODO/BUG/FIXME: \"FIXME\"",
+		"main.go:7:4: Line contains TODO/BUG/FIXME: \"BUG: first\"",
+	}
+
+	messages := godox.Run(f, fset, &config.GoDoxSettings{Anywhere: true})
+	if len(messages) != len(expected) {
+		t.Fatalf("expected %d messages, got %d: %v", len(expected), len(messages), messages)
+	}
+
+	for i, m := range messages {
+		if actual := m.Pos.String() + ": " + m.Description(); actual != expected[i] {
+			t.Errorf("not equal\nexpected: %s\nactual: %s", expected[i], actual)
+		}
+
+		if src[m.Pos.Offset:m.Pos.Offset+len(m.Keyword)] != m.Keyword {
+			t.Errorf("offset %d doesn't point to the keyword %s", m.Pos.Offset, m.Keyword)
+		}
+	}
+}
+
+func TestAliases(t *testing.T) {
+	t.Parallel()
+
+	const src = "package main\n\n// HACK: first\n\nvar x = 1 // XXX: second //godox:ignore:FIXME\n\n// WIP: third\n"
+
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	settings := config.GoDoxSettings{
+		Aliases:    map[string]string{"HACK": "FIXME", "XXX": "FIXME", "WIP": "TODO"},
+		Severities: map[string]string{"FIXME": config.SeverityError},
+	}
+
+	var actual []string
+	for _, m := range godox.Run(f, fset, &settings) {
+		actual = append(actual, fmt.Sprintf("%s:%s:%s", m.Keyword, m.Canonical, m.Severity))
+	}
+
+	expected := []string{"HACK:FIXME:error", "WIP:TODO:warning"}
+	if fmt.Sprint(actual) != fmt.Sprint(expected) {
+		t.Errorf("not equal\nexpected: %v\nactual: %v", expected, actual)
+	}
+}
+
+func TestAnnotations(t *testing.T) {
+	t.Parallel()
+
+	const src = "package main\n\n// @todo first\n/*\n  @fixme(alice) second\n*/\n// @ TODO: not an annotation\n// @@todo neither\n"
+
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		settings config.GoDoxSettings
+		expected []string
+	}{
+		{
+			name:     "keywords",
+			expected: []string{"main.go:3:5 TODO todo first", "main.go:5:4 FIXME fixme(alice) second"},
+		},
+		{
+			name: "format",
+			settings: config.GoDoxSettings{
+				Format:      true,
+				FormatRules: []config.GoDoxFormatRule{{Keyword: "TODO", RegularExpression: `^TODO\(`}},
+			},
+			expected: []string{"main.go:3:5 TODO todo first"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt //nolint // reason tt is ok on this context
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var actual []string
+			for _, m := range godox.Run(f, fset, &tt.settings) {
+				actual = append(actual, fmt.Sprintf("%s %s %s", m.Pos, m.Keyword, m.Text))
+			}
+
+			if fmt.Sprint(actual) != fmt.Sprint(tt.expected) {
+				t.Errorf("not equal\nexpected: %q\nactual: %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPriority(t *testing.T) {
+	t.Parallel()
+
+	const src = "package main\n\n// TODO(P2): later\n// TODO(alice, p0): now\n// FIXME!!: urgent\n// TODO(bob)!: soon\n// TODO: whenever\n"
+
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		settings config.GoDoxSettings
+		expected []string
+	}{
+		{
+			name:     "all",
+			expected: []string{"3 P2 ", "4 P0 alice", "5 P0 ", "6 P1 bob", "7  "},
+		},
+		{
+			name:     "minimum priority",
+			settings: config.GoDoxSettings{MinPriority: "p1"},
+			expected: []string{"4 P0 alice", "5 P0 ", "6 P1 bob"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt //nolint // reason tt is ok on this context
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var actual []string
+			for _, m := range godox.Run(f, fset, &tt.settings) {
+				actual = append(actual, fmt.Sprintf("%d %s %s", m.Line, m.Priority, m.Owner))
+			}
+
+			if fmt.Sprint(actual) != fmt.Sprint(tt.expected) {
+				t.Errorf("not equal\nexpected: %q\nactual: %q", tt.expected, actual)
+			}
+		})
+	}
+
+	messages := godox.Run(f, fset, &config.GoDoxSettings{})
+	godox.SortByPriority(messages)
+
+	var lines []int
+	for _, m := range messages {
+		lines = append(lines, m.Line)
+	}
+
+	if expected := []int{4, 5, 6, 3, 7}; fmt.Sprint(lines) != fmt.Sprint(expected) {
+		t.Errorf("not equal\nexpected: %v\nactual: %v", expected, lines)
+	}
+}
+
+func TestLabels(t *testing.T) {
+	t.Parallel()
+
+	const src = "package main\n\n// TODO: clean up #perf #v2 #perf\n// FIXME: [Perf] see #123\n// TODO(alice): no labels, e.g.# or [42]\n"
+
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		settings config.GoDoxSettings
+		expected []string
+	}{
+		{
+			name:     "all",
+			expected: []string{"3 [perf v2]", "4 [Perf]", "5 []"},
+		},
+		{
+			name:     "labels",
+			settings: config.GoDoxSettings{Labels: []string{"PERF"}},
+			expected: []string{"3 [perf v2]", "4 [Perf]"},
+		},
+		{
+			name:     "unknown label",
+			settings: config.GoDoxSettings{Labels: []string{"v3"}},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt //nolint // reason tt is ok on this context
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var actual []string
+			for _, m := range godox.Run(f, fset, &tt.settings) {
+				actual = append(actual, fmt.Sprintf("%d %v", m.Line, m.Labels))
+			}
+
+			if fmt.Sprint(actual) != fmt.Sprint(tt.expected) {
+				t.Errorf("not equal\nexpected: %q\nactual: %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestEffort(t *testing.T) {
+	t.Parallel()
+
+	const src = "package main\n\n// TODO [3d]: rewrite the parser\n// TODO(alice) [4h]: faster\n// FIXME: [2w] [1d]\n// TODO: [3x] or [d]\n"
+
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var actual []time.Duration
+	for _, m := range godox.Run(f, fset, &config.GoDoxSettings{}) {
+		actual = append(actual, m.Effort)
+	}
+
+	if expected := []time.Duration{24 * time.Hour, 4 * time.Hour, 80 * time.Hour, 0}; fmt.Sprint(actual) != fmt.Sprint(expected) {
+		t.Errorf("not equal\nexpected: %v\nactual: %v", expected, actual)
+	}
+}
+
+func TestSnooze(t *testing.T) {
+	t.Parallel()
+
+	const src = "package main\n\n// TODO(alice)[snooze:2999-09-01]: later\n// FIXME [snooze:2020-09-01]: now\n// TODO: [snooze:someday]\n"
+
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		settings config.GoDoxSettings
+		expected []string
+	}{
+		{
+			name:     "snoozed",
+			expected: []string{"4 error 2020-09-01 false", "5 warning 0001-01-01 false"},
+		},
+		{
+			name:     "suppressed",
+			settings: config.GoDoxSettings{ReportSuppressed: true},
+			expected: []string{"3 warning 2999-09-01 true", "4 error 2020-09-01 false", "5 warning 0001-01-01 false"},
+		},
+		{
+			name:     "not deadlines",
+			settings: config.GoDoxSettings{DeadlineMode: config.DeadlineModeExpired},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt //nolint // reason tt is ok on this context
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var actual []string
+			for _, m := range godox.Run(f, fset, &tt.settings) {
+				actual = append(actual, fmt.Sprintf("%d %s %s %t", m.Line, m.Severity, m.Snoozed.Format("2006-01-02"), m.Suppressed))
+			}
+
+			if fmt.Sprint(actual) != fmt.Sprint(tt.expected) {
+				t.Errorf("not equal\nexpected: %q\nactual: %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestUnicodeKeywords(t *testing.T) {
+	t.Parallel()
+
+	const src = "package main\n\n// 要修正：境界チェックを追加する\n/*\n  待办：添加测试，添加测试，添加测试，添加测试，添加测试，添加测试，添加测试，添加测试，添加测试\n*/\n"
+
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		settings config.GoDoxSettings
+	}{
+		{
+			name:     "keywords",
+			settings: config.GoDoxSettings{Keywords: []string{"要修正", "待办"}},
+		},
+		{
+			name: "format",
+			settings: config.GoDoxSettings{
+				Format: true,
+				FormatRules: []config.GoDoxFormatRule{
+					{Keyword: "要修正", RegularExpression: `^要修正\(`},
+					{Keyword: "待办", RegularExpression: `^待办\(`},
+				},
+			},
+		},
+	}
+
+	expected := []string{
+		"main.go:3:4 要修正 \"要修正：境界チェックを追加する\"",
+		"main.go:5:3 待办 \"待办：添加测试，添加测试，添加测试，添加测试，添加测试，添加测试，添加测试，添加...\"",
+	}
+
+	for _, tt := range tests {
+		tt := tt //nolint // reason tt is ok on this context
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var actual []string
+			for _, m := range godox.Run(f, fset, &tt.settings) {
+				description := m.Description()
+				actual = append(actual, fmt.Sprintf("%s %s %s", m.Pos, m.Keyword, description[strings.IndexByte(description, '"'):]))
+			}
+
+			if fmt.Sprint(actual) != fmt.Sprint(expected) {
+				t.Errorf("not equal\nexpected: %q\nactual: %q", expected, actual)
+			}
+		})
+	}
+}
+
+func TestSkipGenerated(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		src       string
+		generated bool
+	}{
+		{src: "// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage main\n\n// TODO: x\n", generated: true},
+		{src: "// Package main.\n\n// Code generated by mockgen. DO NOT EDIT.\npackage main\n\n// TODO: x\n", generated: true},
+		{src: "package main\n\n// Code generated by mockgen. DO NOT EDIT.\n\n// TODO: x\n"},
+		{src: "// Code generated by hand, edit freely.\npackage main\n\n// TODO: x\n"},
+	}
+
+	for _, tt := range tests {
+		fset := token.NewFileSet()
+
+		f, err := parser.ParseFile(fset, "main.go", tt.src, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		messages := godox.Run(f, fset, &config.GoDoxSettings{SkipGenerated: true})
+		if generated := len(messages) == 0; generated != tt.generated {
+			t.Errorf("%q: expected generated %t, got %d messages", tt.src, tt.generated, len(messages))
+		}
+
+		if messages := godox.Run(f, fset, &config.GoDoxSettings{}); len(messages) != 1 {
+			t.Errorf("%q: expected 1 message without skipping generated files, got %d", tt.src, len(messages))
+		}
+	}
+}
+
+func TestPaths(t *testing.T) {
+	t.Parallel()
+
+	filenames := []string{
+		"./fixtures/03/main.go",
+		"./fixtures/06/example1.go",
+		"./fixtures/07/example1.go",
+	}
+
+	tests := []struct {
+		name     string
+		settings config.GoDoxSettings
+		files    []string
+	}{
+		{
+			name:  "all",
+			files: []string{"fixtures/03/main.go", "fixtures/06/example1.go", "fixtures/07/example1.go"},
+		},
+		{
+			name:     "exclude",
+			settings: config.GoDoxSettings{ExcludePaths: []string{"06/**", "/fixtures/07/*.go"}},
+			files:    []string{"fixtures/03/main.go"},
+		},
+		{
+			name:     "include",
+			settings: config.GoDoxSettings{IncludePaths: []string{"fixtures/{03,06}/*.go"}},
+			files:    []string{"fixtures/03/main.go", "fixtures/06/example1.go"},
+		},
+		{
+			name: "include and exclude",
+			settings: config.GoDoxSettings{
+				IncludePaths: []string{"**/*.go"},
+				ExcludePaths: []string{"main.go"},
+			},
+			files: []string{"fixtures/06/example1.go", "fixtures/07/example1.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt //nolint // reason tt is ok on this context
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			messages, err := godox.RunFiles(context.Background(), filenames, &tt.settings)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var files []string
+
+			for _, m := range messages {
+				if name := filepath.ToSlash(filepath.Clean(m.Pos.Filename)); len(files) == 0 || files[len(files)-1] != name {
+					files = append(files, name)
+				}
+			}
+
+			if fmt.Sprint(files) != fmt.Sprint(tt.files) {
+				t.Errorf("not equal\nexpected: %v\nactual: %v", tt.files, files)
+			}
+
+			// the paths are checked when running on parsed files too
+			fset := token.NewFileSet()
+
+			f, err := parser.ParseFile(fset, filenames[0], nil, parser.ParseComments)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if included := len(godox.Run(f, fset, &tt.settings)) > 0; included != (tt.files[0] == "fixtures/03/main.go") {
+				t.Errorf("unexpected messages for %s", filenames[0])
+			}
+		})
+	}
+}
+
+func TestNestedConfigs(t *testing.T) {
+	t.Parallel()
+
+	filenames := []string{
+		"fixtures/10/main.go",
+		"fixtures/10/experiments/experiments.go",
+		"fixtures/10/pkg/api/api.go",
+	}
+
+	messages, err := godox.RunFiles(context.Background(), filenames, &config.GoDoxSettings{ConfigRoot: "fixtures/10"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		`fixtures/10/experiments/experiments.go:4: Line contains BUG: "BUG: still reported"`,
+		`fixtures/10/main.go:3: Line contains TODO/BUG/FIXME: "TODO: root"`,
+		`fixtures/10/pkg/api/api.go:3: Line does not match the expected format: ^TODO\(\w+\), "TODO: missing owner"`,
+	}
+
+	var actual []string
+	for _, m := range messages {
+		actual = append(actual, m.Message)
+	}
+
+	if fmt.Sprint(actual) != fmt.Sprint(expected) {
+		t.Errorf("not equal\nexpected: %q\nactual: %q", expected, actual)
+	}
+}
+
+func TestStats(t *testing.T) {
+	t.Parallel()
+
+	messages := []godox.Message{
+		{Pos: token.Position{Filename: "pkg/a.go"}, Keyword: "TODO", Canonical: "TODO", Owner: "alice", Severity: godox.SeverityWarning},
+		{Pos: token.Position{Filename: "pkg/a.go"}, Keyword: "HACK", Canonical: "FIXME", Severity: godox.SeverityError, Labels: []string{"perf", "v2"}, Effort: 4 * time.Hour},
+		{Pos: token.Position{Filename: "./pkg/b.go"}, Keyword: "TODO", Canonical: "TODO", Owner: "alice", Severity: godox.SeverityWarning, Labels: []string{"perf"}, Effort: 72 * time.Hour},
+		{Pos: token.Position{Filename: "main.go"}, Keyword: "BUG", Severity: godox.SeverityInfo},
+		{Pos: token.Position{Filename: "main.go"}, Keyword: "TODO", Canonical: "TODO", Suppressed: true},
+	}
+
+	expected := godox.Statistics{
+		Total:      4,
+		Suppressed: 1,
+		Keywords:   map[string]int{"TODO": 2, "FIXME": 1, "BUG": 1},
+		Severities: map[godox.Severity]int{godox.SeverityWarning: 2, godox.SeverityError: 1, godox.SeverityInfo: 1},
+		Packages:   map[string]int{"pkg": 3, ".": 1},
+		Files:      map[string]int{"pkg/a.go": 2, "pkg/b.go": 1, "main.go": 1},
+		Owners:     map[string]int{"alice": 2},
+		Labels:     map[string]int{"perf": 2, "v2": 1},
+		Unowned:    2,
+		Effort: godox.EffortStatistics{
+			Hours:    76,
+			Packages: map[string]int{"pkg": 76},
+			Owners:   map[string]int{"alice": 72, "": 4},
+		},
+	}
+
+	if actual := godox.Stats(messages); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("not equal\nexpected: %+v\nactual: %+v", expected, actual)
+	}
+}
+
+func TestBudgets(t *testing.T) {
+	t.Parallel()
+
+	settings, err := (&config.GoDoxSettings{
+		Budgets: map[string]int{"pkg/payments/**": 0, "internal/legacy/**": 2, "**/*.go": 4},
+	}).Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages := []godox.Message{
+		{Pos: token.Position{Filename: "pkg/payments/a.go"}, Keyword: "TODO"},
+		{Pos: token.Position{Filename: "./pkg/payments/b.go"}, Keyword: "FIXME"},
+		{Pos: token.Position{Filename: "pkg/payments/b.go"}, Keyword: "TODO", Suppressed: true},
+		{Pos: token.Position{Filename: "internal/legacy/a.go"}, Keyword: "TODO"},
+		{Pos: token.Position{Filename: "internal/legacy/b.go"}, Keyword: "TODO"},
+		{Pos: token.Position{Filename: "main.go"}, Keyword: "BUG"},
+	}
+
+	expected := []*godox.BudgetError{
+		{Pattern: "**/*.go", Max: 4, Count: 5},
+		{Pattern: "pkg/payments/**", Max: 0, Count: 2},
+	}
+
+	actual := godox.CheckBudgets(messages, settings)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("not equal\nexpected: %+v\nactual: %+v", expected, actual)
+	}
+
+	if msg := actual[1].Error(); msg != "budget of pkg/payments/** exceeded: 2 findings, at most 0 allowed" {
+		t.Errorf("unexpected error: %s", msg)
+	}
+}
+
+func TestMessageFields(t *testing.T) {
+	t.Parallel()
+
+	const src = `package main
+
+/*
+Some comment
+	FIXME: handle the error properly, this is a long line
+*/
+`
+
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages := godox.Run(f, fset, &config.GoDoxSettings{})
+	if len(messages) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(messages))
+	}
+
+	m := messages[0]
+
+	if m.Keyword != "FIXME" {
+		t.Errorf("unexpected keyword %q", m.Keyword)
+	}
+
+	if m.Text != "FIXME: handle the error properly, this is a long line" {
+		t.Errorf("unexpected text %q", m.Text)
+	}
+
+	if m.Line != 5 || m.Column != 2 || m.Pos.Line != 5 || m.Pos.Column != 2 || m.Pos.Offset != 31 {
+		t.Errorf("unexpected position %v (line %d, column %d)", m.Pos, m.Line, m.Column)
+	}
+
+	if m.Severity != godox.SeverityWarning {
+		t.Errorf("unexpected severity %q", m.Severity)
+	}
+
+	if m.RuleID != godox.RuleKeyword {
+		t.Errorf("unexpected rule %q", m.RuleID)
+	}
+
+	if m.String() != m.Message {
+		t.Errorf("String() should return the formatted message, got %q", m.String())
+	}
+}
+
+func TestReportSuppressed(t *testing.T) {
+	t.Parallel()
+
+	const filename = "./fixtures/06/example1.go"
+
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages := godox.Run(f, fset, &config.GoDoxSettings{ReportSuppressed: true})
+
+	var suppressed []int
+
+	for _, m := range messages {
+		if m.Suppressed {
+			suppressed = append(suppressed, m.Line)
+		}
+	}
+
+	expected := []int{4, 7, 11, 15}
+	if len(messages) != 8 || fmt.Sprint(suppressed) != fmt.Sprint(expected) {
+		t.Errorf("expected 8 messages with suppressed lines %v, got %d with %v", expected, len(messages), suppressed)
+	}
+}
+
+func TestMalformedComments(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		text     string
+		expected int
+	}{
+		{text: ""},
+		{text: "/"},
+		{text: "//"},
+		{text: "/*"},
+		{text: "/**/"},
+		{text: "/*/"},
+		{text: "TODO: not a comment"},
+		{text: "/* TODO: not closed", expected: 1},
+		{text: "// TODO: synthetic", expected: 1},
+	}
+
+	for _, tt := range tests {
+		tt := tt //nolint // reason tt is ok on this context
+		t.Run(fmt.Sprintf("%q", tt.text), func(t *testing.T) {
+			t.Parallel()
+
+			file := &ast.File{
+				Name:     ast.NewIdent("main"),
+				Comments: []*ast.CommentGroup{{List: []*ast.Comment{{Text: tt.text}}}},
+			}
+
+			messages := godox.Run(file, token.NewFileSet(), &config.GoDoxSettings{})
+			if len(messages) != tt.expected {
+				t.Errorf("expected %d messages, got %d", tt.expected, len(messages))
+			}
+		})
+	}
+}
+
+func TestPositions(t *testing.T) {
+	t.Parallel()
+
+	const src = "package main\r\n\r\n/*\r\n  TODO: first\r\n\t\tFIXME: second\r\n*/\r\nvar x = 1 //   BUG: third\r\n"
+
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"main.go:4:3", "main.go:5:3", "main.go:7:16"}
+
+	messages := godox.Run(f, fset, &config.GoDoxSettings{})
+	if len(messages) != len(expected) {
+		t.Fatalf("expected %d messages, got %d", len(expected), len(messages))
+	}
+
+	for i, m := range messages {
+		if m.Pos.String() != expected[i] {
+			t.Errorf("expected position %s, got %s", expected[i], m.Pos)
+		}
+
+		if src[m.Pos.Offset:m.Pos.Offset+len(m.Keyword)] != m.Keyword {
+			t.Errorf("offset %d doesn't point to the keyword %s", m.Pos.Offset, m.Keyword)
+		}
+	}
+}
+
+func TestRequireIssueReference(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		settings config.GoDoxSettings
+		result   []string
+		issues   []string
+	}{
+		{
+			name:     "keywords",
+			settings: config.GoDoxSettings{},
+			result: []string{
+				`fixtures/07/example1.go:3: Line contains TODO/BUG/FIXME: "TODO(#12): reference to a GitHub issue"`,
+				`fixtures/07/example1.go:4: Line contains TODO/BUG/FIXME: "TODO: see matoous/godox#34"`,
+				`fixtures/07/example1.go:5: Line contains TODO/BUG/FIXME: "FIXME: PROJ-56 is a Jira issue"`,
+				`fixtures/07/example1.go:6: Line contains TODO/BUG/FIXME: "TODO: https://github.com/matoous/godox/i..."`,
+				`fixtures/07/example1.go:7: Line contains TODO/BUG/FIXME: "TODO: no reference (Line 7)"`,
+				`fixtures/07/example1.go:8: Line contains TODO/BUG/FIXME: "BUG: hash without number # (Line 8)"`,
+			},
+			issues: []string{
+				"github #12",
+				"github matoous/godox#34",
+				"jira PROJ-56",
+				"url https://github.com/matoous/godox/issues/78",
+				" ",
+				" ",
+			},
+		},
+		{
+			name:     "required",
+			settings: config.GoDoxSettings{RequireIssueReference: true},
+			result: []string{
+				`fixtures/07/example1.go:7: Line does not reference an issue: "TODO: no reference (Line 7)"`,
+				`fixtures/07/example1.go:8: Line does not reference an issue: "BUG: hash without number # (Line 8)"`,
+			},
+			issues: []string{" ", " "},
+		},
+		{
+			name: "custom patterns",
+			settings: config.GoDoxSettings{
+				RequireIssueReference: true,
+				IssuePatterns: []config.IssuePattern{
+					{Tracker: "jira", RegularExpression: `\bPROJ-\d+\b`},
+				},
+			},
+			result: []string{
+				`fixtures/07/example1.go:3: Line does not reference an issue: "TODO(#12): reference to a GitHub issue"`,
+				`fixtures/07/example1.go:4: Line does not reference an issue: "TODO: see matoous/godox#34"`,
+				`fixtures/07/example1.go:6: Line does not reference an issue: "TODO: https://github.com/matoous/godox/i..."`,
+				`fixtures/07/example1.go:7: Line does not reference an issue: "TODO: no reference (Line 7)"`,
+				`fixtures/07/example1.go:8: Line does not reference an issue: "BUG: hash without number # (Line 8)"`,
+			},
+			issues: []string{" ", " ", " ", " ", " "},
+		},
+		{
+			name: "format",
+			settings: config.GoDoxSettings{
+				Format:                true,
+				RequireIssueReference: true,
+				FormatRules: []config.GoDoxFormatRule{
+					{Keyword: "TODO", RegularExpression: `^TODO: .+$`},
+				},
+			},
+			result: []string{
+				`fixtures/07/example1.go:3: Line does not match the expected format: ^TODO: .+$, "TODO(#12): reference to a GitHub issue"`,
+				`fixtures/07/example1.go:7: Line does not reference an issue: "TODO: no reference (Line 7)"`,
+			},
+			issues: []string{"github #12", " "},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt //nolint // reason tt is ok on this context
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fset := token.NewFileSet()
+
+			f, err := parser.ParseFile(fset, "./fixtures/07/example1.go", nil, parser.ParseComments)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			messages := godox.Run(f, fset, &tt.settings)
+			if len(messages) != len(tt.result) {
+				t.Fatalf("expected %d messages, got %d:\n%q", len(tt.result), len(messages), messages)
+			}
+
+			for i, m := range messages {
+				if m.Message != tt.result[i] {
+					t.Errorf("not equal\nexpected: %s\nactual: %s", tt.result[i], m.Message)
+				}
+
+				if issue := m.IssueTracker + " " + m.Issue; issue != tt.issues[i] {
+					t.Errorf("expected issue %q, got %q", tt.issues[i], issue)
+				}
+			}
+		})
+	}
+}
+
+func TestDeadlines(t *testing.T) {
+	t.Parallel()
+
+	const src = `package main
+
+// TODO(2025-06-01): expired
+// TODO(2025-08-01): not expired yet
+// TODO: no deadline
+// FIXME: invalid date 2025-13-01
+`
+
+	tests := []struct {
+		mode       string
+		result     []string
+		severities []godox.Severity
+	}{
+		{
+			mode: "",
+			result: []string{
+				`main.go:3: Line contains TODO/BUG/FIXME: "TODO(2025-06-01): expired"`,
+				`main.go:4: Line contains TODO/BUG/FIXME: "TODO(2025-08-01): not expired yet"`,
+				`main.go:5: Line contains TODO/BUG/FIXME: "TODO: no deadline"`,
+				`main.go:6: Line contains TODO/BUG/FIXME: "FIXME: invalid date 2025-13-01"`,
+			},
+			severities: []godox.Severity{"warning", "warning", "warning", "warning"},
+		},
+		{
+			mode: config.DeadlineModeExpired,
+			result: []string{
+				`main.go:3: Deadline 2025-06-01 has passed: "TODO(2025-06-01): expired"`,
+			},
+			severities: []godox.Severity{"warning"},
+		},
+		{
+			mode: config.DeadlineModeEscalate,
+			result: []string{
+				`main.go:3: Line contains TODO/BUG/FIXME: "TODO(2025-06-01): expired"`,
+				`main.go:4: Line contains TODO/BUG/FIXME: "TODO(2025-08-01): not expired yet"`,
+				`main.go:5: Line contains TODO/BUG/FIXME: "TODO: no deadline"`,
+				`main.go:6: Line contains TODO/BUG/FIXME: "FIXME: invalid date 2025-13-01"`,
+			},
+			severities: []godox.Severity{"error", "warning", "warning", "warning"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt //nolint // reason tt is ok on this context
+		t.Run(tt.mode, func(t *testing.T) {
+			t.Parallel()
+
+			fset := token.NewFileSet()
+
+			f, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			settings := config.GoDoxSettings{DeadlineMode: tt.mode}
+
+			compiled, err := settings.Compile()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			compiled.Now = time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+
+			messages := godox.RunCompiled(f, fset, compiled)
+			if len(messages) != len(tt.result) {
+				t.Fatalf("expected %d messages, got %d:\n%q", len(tt.result), len(messages), messages)
+			}
+
+			for i, m := range messages {
+				if m.Message != tt.result[i] {
+					t.Errorf("not equal\nexpected: %s\nactual: %s", tt.result[i], m.Message)
+				}
+
+				if m.Severity != tt.severities[i] {
+					t.Errorf("expected severity %s, got %s", tt.severities[i], m.Severity)
+				}
+			}
+
+			if first := messages[0]; first.Deadline.Format("2006-01-02") != "2025-06-01" || !first.Expired {
+				t.Errorf("unexpected deadline %v (expired %t)", first.Deadline, first.Expired)
+			}
+		})
+	}
+}
+
+func TestRunPackages(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		settings config.GoDoxSettings
+		result   []string
+	}{
+		{
+			name: "default",
+			result: []string{
+				`fixtures/08/cgo.go:5: Line contains TODO/BUG/FIXME: "FIXME: free the strings"`,
+				`fixtures/08/cgo.go:7: Line contains TODO/BUG/FIXME: "BUG: not"`,
+				`fixtures/08/cgo.go:11: Line contains TODO/BUG/FIXME: "TODO: cgo file"`,
+				`fixtures/08/plain.go:3: Line contains TODO/BUG/FIXME: "TODO: plain file"`,
+				`fixtures/11/asm.s:3: Line contains TODO/BUG/FIXME: "TODO: vectorize the loop"`,
+				`fixtures/11/asm.s:5: Line contains TODO/BUG/FIXME: "FIXME: load the answer"`,
+			},
+		},
+		{
+			name:     "fast engine",
+			settings: config.GoDoxSettings{Engine: config.EngineFast},
+			result: []string{
+				`fixtures/08/cgo.go:5: Line contains TODO/BUG/FIXME: "FIXME: free the strings"`,
+				`fixtures/08/cgo.go:7: Line contains TODO/BUG/FIXME: "BUG: not"`,
+				`fixtures/08/cgo.go:11: Line contains TODO/BUG/FIXME: "TODO: cgo file"`,
+				`fixtures/08/plain.go:3: Line contains TODO/BUG/FIXME: "TODO: plain file"`,
+				`fixtures/11/asm.s:3: Line contains TODO/BUG/FIXME: "TODO: vectorize the loop"`,
+				`fixtures/11/asm.s:5: Line contains TODO/BUG/FIXME: "FIXME: load the answer"`,
+			},
+		},
+		{
+			name:     "included files",
+			settings: config.GoDoxSettings{Include: []string{"*.sql", "Dockerfile"}},
+			result: []string{
+				`fixtures/08/Dockerfile:3: Line contains TODO/BUG/FIXME: "TODO: pin the digest"`,
+				`fixtures/08/cgo.go:5: Line contains TODO/BUG/FIXME: "FIXME: free the strings"`,
+				`fixtures/08/cgo.go:7: Line contains TODO/BUG/FIXME: "BUG: not"`,
+				`fixtures/08/cgo.go:11: Line contains TODO/BUG/FIXME: "TODO: cgo file"`,
+				`fixtures/08/plain.go:3: Line contains TODO/BUG/FIXME: "TODO: plain file"`,
+				`fixtures/08/schema.sql:1: Line contains TODO/BUG/FIXME: "TODO: index the owners"`,
+				`fixtures/08/schema.sql:2: Line contains TODO/BUG/FIXME: "FIXME: unique names"`,
+				`fixtures/11/asm.s:3: Line contains TODO/BUG/FIXME: "TODO: vectorize the loop"`,
+				`fixtures/11/asm.s:5: Line contains TODO/BUG/FIXME: "FIXME: load the answer"`,
+			},
+		},
+		{
+			name:     "tags and tests",
+			settings: config.GoDoxSettings{BuildTags: []string{"special"}, Tests: true},
+			result: []string{
+				`fixtures/08/cgo.go:5: Line contains TODO/BUG/FIXME: "FIXME: free the strings"`,
+				`fixtures/08/cgo.go:7: Line contains TODO/BUG/FIXME: "BUG: not"`,
+				`fixtures/08/cgo.go:11: Line contains TODO/BUG/FIXME: "TODO: cgo file"`,
+				`fixtures/08/plain.go:3: Line contains TODO/BUG/FIXME: "TODO: plain file"`,
+				`fixtures/08/plain_test.go:3: Line contains TODO/BUG/FIXME: "TODO: test file"`,
+				`fixtures/08/tagged.go:6: Line contains TODO/BUG/FIXME: "TODO: tagged file"`,
+				`fixtures/11/asm.s:3: Line contains TODO/BUG/FIXME: "TODO: vectorize the loop"`,
+				`fixtures/11/asm.s:5: Line contains TODO/BUG/FIXME: "FIXME: load the answer"`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt //nolint // reason tt is ok on this context
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			messages, err := godox.RunPackages(context.Background(), []string{"./fixtures/08", "./fixtures/11"}, &tt.settings)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(messages) != len(tt.result) {
+				t.Fatalf("expected %d messages, got %d:\n%q", len(tt.result), len(messages), messages)
+			}
+
+			for i, m := range messages {
+				if m.Message != tt.result[i] {
+					t.Errorf("not equal\nexpected: %s\nactual: %s", tt.result[i], m.Message)
+				}
+			}
+		})
+	}
+
+	if _, err := godox.RunPackages(context.Background(), []string{"./fixtures/nonexistent"}, &config.GoDoxSettings{}); err == nil {
+		t.Error("expected error for nonexistent package")
+	}
+}
+
+func TestRunPackagesFunc(t *testing.T) {
+	t.Parallel()
+
+	settings := &config.GoDoxSettings{BuildTags: []string{"special"}, Tests: true}
+
+	files := make(map[string]int)
+
+	err := godox.RunPackagesFunc(context.Background(), []string{"./fixtures/08", "./fixtures/11"}, settings, func(messages []godox.Message) error {
+		for _, m := range messages {
+			files[m.Pos.Filename]++
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]int{
+		filepath.Join("fixtures", "08", "cgo.go"):        3,
+		filepath.Join("fixtures", "08", "plain.go"):      1,
+		filepath.Join("fixtures", "08", "plain_test.go"): 1,
+		filepath.Join("fixtures", "08", "tagged.go"):     1,
+		filepath.Join("fixtures", "11", "asm.s"):         2,
+	}
+
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("not equal\nexpected: %v\nactual: %v", expected, files)
+	}
+
+	// the scanning stops on the first error
+	stop := errors.New("stop")
+	calls := 0
+
+	err = godox.RunPackagesFunc(context.Background(), []string{"./fixtures/08", "./fixtures/11"}, settings, func([]godox.Message) error {
+		calls++
+		return stop
+	})
+	if err != stop || calls != 1 {
+		t.Errorf("expected the stop error after 1 call, got %v after %d calls", err, calls)
+	}
+}
+
+func TestSeverities(t *testing.T) {
+	t.Parallel()
+
+	const src = `package main
+
+// TODO: warning by default
+// FIXME: configured as error
+// BUG: configured as info
+`
+
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages := godox.Run(f, fset, &config.GoDoxSettings{
+		Severities: map[string]string{"fixme": "error", "BUG": "info"},
+	})
+
+	expected := []godox.Severity{godox.SeverityWarning, godox.SeverityError, godox.SeverityInfo}
+	if len(messages) != len(expected) {
+		t.Fatalf("expected %d messages, got %d", len(expected), len(messages))
+	}
+
+	for i, m := range messages {
+		if m.Severity != expected[i] {
+			t.Errorf("%s: expected severity %s, got %s", m.Keyword, expected[i], m.Severity)
+		}
+	}
+
+	if !godox.SeverityError.AtLeast(godox.SeverityWarning) || godox.SeverityInfo.AtLeast(godox.SeverityWarning) {
+		t.Error("unexpected severity order")
+	}
+}
+
+func TestRequireOwner(t *testing.T) {
+	t.Parallel()
+
+	const src = `package main
+
+// TODO(alice): owner
+// TODO(@bob, 2025-06-01): owner with a deadline
+// TODO(2025-06-01): deadline only
+// FIXME: no owner
+// TODO (carol): space before the owner
+`
+
+	tests := []struct {
+		name     string
+		settings config.GoDoxSettings
+		result   []string
+		owners   []string
+	}{
+		{
+			name:     "parse",
+			settings: config.GoDoxSettings{},
+			result: []string{
+				`main.go:3: Line contains TODO/BUG/FIXME: "TODO(alice): owner"`,
+				`main.go:4: Line contains TODO/BUG/FIXME: "TODO(@bob, 2025-06-01): owner with a dea..."`,
+				`main.go:5: Line contains TODO/BUG/FIXME: "TODO(2025-06-01): deadline only"`,
+				`main.go:6: Line contains TODO/BUG/FIXME: "FIXME: no owner"`,
+				`main.go:7: Line contains TODO/BUG/FIXME: "TODO (carol): space before the owner"`,
+			},
+			owners: []string{"alice", "bob", "", "", ""},
+		},
+		{
+			name:     "required",
+			settings: config.GoDoxSettings{RequireOwner: true},
+			result: []string{
+				`main.go:5: Line does not specify an owner: "TODO(2025-06-01): deadline only"`,
+				`main.go:6: Line does not specify an owner: "FIXME: no owner"`,
+				`main.go:7: Line does not specify an owner: "TODO (carol): space before the owner"`,
+			},
+			owners: []string{"", "", ""},
+		},
+		{
+			name:     "roster",
+			settings: config.GoDoxSettings{OwnerRoster: "config/testdata/roster.txt"},
+			result: []string{
+				`main.go:4: Owner bob is not in the roster: "TODO(@bob, 2025-06-01): owner with a dea..."`,
+			},
+			owners: []string{"bob"},
+		},
+		{
+			name:     "roster and required owner",
+			settings: config.GoDoxSettings{RequireOwner: true, OwnerRoster: "config/testdata/roster.txt"},
+			result: []string{
+				`main.go:4: Owner bob is not in the roster: "TODO(@bob, 2025-06-01): owner with a dea..."`,
+				`main.go:5: Line does not specify an owner: "TODO(2025-06-01): deadline only"`,
+				`main.go:6: Line does not specify an owner: "FIXME: no owner"`,
+				`main.go:7: Line does not specify an owner: "TODO (carol): space before the owner"`,
+			},
+			owners: []string{"bob", "", "", ""},
+		},
+		{
+			name:     "custom pattern",
+			settings: config.GoDoxSettings{RequireOwner: true, OwnerPattern: `^\s*\((\w+)\)`},
+			result: []string{
+				`main.go:4: Line does not specify an owner: "TODO(@bob, 2025-06-01): owner with a dea..."`,
+				`main.go:5: Line does not specify an owner: "TODO(2025-06-01): deadline only"`,
+				`main.go:6: Line does not specify an owner: "FIXME: no owner"`,
+			},
+			owners: []string{"", "", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt //nolint // reason tt is ok on this context
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			fset := token.NewFileSet()
+
+			f, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			messages := godox.Run(f, fset, &tt.settings)
+			if len(messages) != len(tt.result) {
+				t.Fatalf("expected %d messages, got %d:\n%q", len(tt.result), len(messages), messages)
+			}
+
+			for i, m := range messages {
+				if m.Message != tt.result[i] {
+					t.Errorf("not equal\nexpected: %s\nactual: %s", tt.result[i], m.Message)
+				}
+
+				if m.Owner != tt.owners[i] {
+					t.Errorf("expected owner %q, got %q", tt.owners[i], m.Owner)
+				}
+			}
+		})
+	}
+}
+
+func TestRunFiles(t *testing.T) {
+	t.Parallel()
+
+	var filenames []string
+
+	_ = filepath.Walk("./fixtures", func(path string, info os.FileInfo, _ error) error {
+		if !info.IsDir() && filepath.Ext(path) == ".go" {
+			filenames = append(filenames, path)
+		}
+
+		return nil
+	})
+
+	var expected []godox.Message
+
+	for _, filename := range filenames {
+		fset := token.NewFileSet()
+
+		f, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected = append(expected, godox.Run(f, fset, &config.GoDoxSettings{})...)
+	}
+
+	for _, concurrency := range []int{0, 1, 3, 100} {
+		messages, err := godox.RunFiles(context.Background(), filenames, &config.GoDoxSettings{Concurrency: concurrency})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if fmt.Sprint(messages) != fmt.Sprint(expected) {
+			t.Errorf("concurrency %d: messages are not equal\nexpected: %v\nactual: %v", concurrency, expected, messages)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := godox.RunFiles(ctx, filenames, &config.GoDoxSettings{}); err == nil {
+		t.Error("expected error for cancelled context")
+	}
+
+	if _, err := godox.RunFiles(context.Background(), []string{"./fixtures/nonexistent.go"}, &config.GoDoxSettings{}); err == nil {
+		t.Error("expected error for nonexistent file")
+	}
+}
+
+func TestCache(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "godox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "main.go")
+	write := func(src string) {
+		if err := ioutil.WriteFile(filename, []byte(src), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	settings := config.GoDoxSettings{CacheDir: filepath.Join(dir, "cache")}
+
+	for _, src := range []string{
+		"package main\n\n// TODO: first\n",
+		"package main\n\n// TODO: first\n",
+		"package main\n\n// TODO: first\n// FIXME: second\n",
+	} {
+		write(src)
+
+		fset := token.NewFileSet()
+
+		f, err := parser.ParseFile(fset, filename, nil, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := godox.Run(f, fset, &config.GoDoxSettings{})
+
+		// the second run is served from the cache
+		for i := 0; i < 2; i++ {
+			messages, err := godox.RunFiles(context.Background(), []string{filename}, &settings)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(messages, expected) {
+				t.Errorf("messages are not equal\nexpected: %#v\nactual: %#v", expected, messages)
+			}
+		}
+	}
+
+	entries, err := filepath.Glob(filepath.Join(dir, "cache", "*", "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 2 {
+		t.Errorf("expected 2 cache entries, got %d", len(entries))
+	}
+}
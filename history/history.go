@@ -0,0 +1,333 @@
+// Package history scans historical revisions of a git repository and returns the time series of the numbers
+// of findings, e.g. to show the long-term trajectory of the debt. The revisions are read using the git plumbing
+// commands, the working tree is left as it is.
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/matoous/godox"
+	"github.com/matoous/godox/config"
+)
+
+// SchemaVersion is the version of the JSON output schema.
+const SchemaVersion = 1
+
+// Point is the number of findings of the revision checked in at the time.
+type Point struct {
+	Time   time.Time `json:"time"`
+	Commit string    `json:"commit"`
+	Total  int       `json:"total"`
+	// Keywords are keyed by the canonical keywords.
+	Keywords map[string]int `json:"keywords"`
+}
+
+// Series are the points sorted by time.
+type Series []Point
+
+// Options of the history.
+type Options struct {
+	// Dir is a directory of the repository, the working directory if empty.
+	Dir string
+	// Rev is the revision whose history is scanned, HEAD if empty.
+	Rev string
+	// Since is the time of the first point, it is required. Until is the time of the last point, now if zero.
+	Since time.Time
+	Until time.Time
+	// Step is the time between the points.
+	Step time.Duration
+}
+
+// Run scans the last revision checked in at every step since the start, the points before the first commit
+// are left out. The file names are relative to the root of the repository.
+func Run(ctx context.Context, opts Options, settings *config.GoDoxSettings) (Series, error) {
+	if opts.Since.IsZero() {
+		return nil, fmt.Errorf("start of the history is required")
+	}
+
+	if opts.Step <= 0 {
+		return nil, fmt.Errorf("invalid step %s", opts.Step)
+	}
+
+	dir, rev, until := opts.Dir, opts.Rev, opts.Until
+	if dir == "" {
+		dir = "."
+	}
+
+	if rev == "" {
+		rev = "HEAD"
+	}
+
+	if until.IsZero() {
+		until = time.Now()
+	}
+
+	var (
+		series Series
+		// scanned are the points of the commits already scanned, the same commit is last at multiple steps
+		// when nothing was checked in between
+		scanned = make(map[string]Point)
+	)
+
+	for t := opts.Since; !t.After(until); t = t.Add(opts.Step) {
+		out, err := git(ctx, dir, "rev-list", "-1", "--first-parent", "--before="+t.Format(time.RFC3339), rev, "--")
+		if err != nil {
+			return nil, err
+		}
+
+		commit := strings.TrimSpace(string(out))
+		if commit == "" {
+			continue
+		}
+
+		p, ok := scanned[commit]
+		if !ok {
+			var messages []godox.Message
+			if messages, err = Scan(ctx, dir, commit, settings); err != nil {
+				return nil, err
+			}
+
+			stats := godox.Stats(messages)
+			p = Point{Commit: commit, Total: stats.Total, Keywords: stats.Keywords}
+			scanned[commit] = p
+		}
+
+		p.Time = t
+		series = append(series, p)
+	}
+
+	return series, nil
+}
+
+// Scan runs the godox linter on the Go files of the revision, the file names are relative to the root
+// of the repository. Like the go command, vendor and testdata directories and directories starting with
+// a dot or an underscore are skipped, test files are scanned only if Tests is enabled.
+// The build constraints are not evaluated.
+func Scan(ctx context.Context, dir, rev string, settings *config.GoDoxSettings) ([]godox.Message, error) {
+	out, err := git(ctx, dir, "ls-tree", "-r", "-z", "--full-tree", rev)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		filenames []string
+		objects   = make(map[string]string)
+	)
+
+	for _, entry := range strings.Split(string(out), "\x00") {
+		// <mode> SP <type> SP <object> TAB <file>
+		tab := strings.IndexByte(entry, '\t')
+		if tab < 0 {
+			continue
+		}
+
+		fields, name := strings.Fields(entry[:tab]), entry[tab+1:]
+		if len(fields) != 3 || fields[0] == "120000" || fields[1] != "blob" || !goFile(name, settings.Tests) {
+			continue
+		}
+
+		filename := filepath.FromSlash(name)
+		filenames = append(filenames, filename)
+		objects[filename] = fields[2]
+	}
+
+	sort.Strings(filenames)
+
+	contents, err := catFiles(ctx, dir, objects)
+	if err != nil {
+		return nil, err
+	}
+
+	return godox.RunFilesFrom(ctx, filenames, settings, func(filename string) ([]byte, error) {
+		return contents[filename], nil
+	})
+}
+
+// goFile reports whether the slash separated file name is a Go file scanned by the go command.
+func goFile(name string, tests bool) bool {
+	if path.Ext(name) != ".go" || (!tests && strings.HasSuffix(name, "_test.go")) {
+		return false
+	}
+
+	for _, elem := range strings.Split(name, "/") {
+		if elem == "vendor" || elem == "testdata" || strings.HasPrefix(elem, ".") || strings.HasPrefix(elem, "_") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// catFiles returns the contents of the objects, by the keys of the objects, using a single git process.
+func catFiles(ctx context.Context, dir string, objects map[string]string) (map[string][]byte, error) {
+	keys := make([]string, 0, len(objects))
+
+	var input bytes.Buffer
+
+	for k, object := range objects {
+		keys = append(keys, k)
+		fmt.Fprintln(&input, object)
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "cat-file", "--batch")
+	cmd.Dir = dir
+	cmd.Stdin = &input
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git cat-file --batch: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	contents, err := parseBatch(out, keys)
+	if err != nil {
+		return nil, fmt.Errorf("git cat-file --batch: %w", err)
+	}
+
+	return contents, nil
+}
+
+// parseBatch parses the output of git cat-file --batch, returning the contents of the objects by the keys
+// in the order of the objects.
+func parseBatch(out []byte, keys []string) (map[string][]byte, error) {
+	r := bufio.NewReader(bytes.NewReader(out))
+	contents := make(map[string][]byte, len(keys))
+
+	for _, k := range keys {
+		// <object> SP <type> SP <size> LF <contents> LF
+		header, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+
+		fields := strings.Fields(header)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("unexpected header %q", header)
+		}
+
+		size, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("unexpected header %q", header)
+		}
+
+		content := make([]byte, size+1)
+		if _, err := io.ReadFull(r, content); err != nil {
+			return nil, err
+		}
+
+		contents[k] = content[:size]
+	}
+
+	return contents, nil
+}
+
+func git(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+
+	return out, nil
+}
+
+// keywords returns the sorted keywords of all points.
+func (s Series) keywords() []string {
+	seen := make(map[string]bool)
+
+	var keywords []string
+
+	for _, p := range s {
+		for k := range p.Keywords {
+			if !seen[k] {
+				seen[k] = true
+				keywords = append(keywords, k)
+			}
+		}
+	}
+
+	sort.Strings(keywords)
+
+	return keywords
+}
+
+// rows returns the header and the rows of the date, the abbreviated commit, the total and the numbers
+// of findings per keyword of the points.
+func (s Series) rows() [][]string {
+	keywords := s.keywords()
+	rows := [][]string{append([]string{"date", "commit", "total"}, keywords...)}
+
+	for _, p := range s {
+		commit := p.Commit
+		if len(commit) > 12 {
+			commit = commit[:12]
+		}
+
+		row := []string{p.Time.Format("2006-01-02"), commit, strconv.Itoa(p.Total)}
+		for _, k := range keywords {
+			row = append(row, strconv.Itoa(p.Keywords[k]))
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+// WriteText writes the series as an aligned table.
+func (s Series) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	for _, row := range s.rows() {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+// WriteCSV writes the series as CSV, e.g. to plot the trajectory in a spreadsheet.
+func (s Series) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(s.rows()); err != nil {
+		return err
+	}
+
+	return cw.Error()
+}
+
+// WriteJSON writes the series as JSON.
+func (s Series) WriteJSON(w io.Writer) error {
+	points := []Point(s)
+	if points == nil {
+		points = []Point{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(struct {
+		SchemaVersion int     `json:"schema_version"`
+		Points        []Point `json:"points"`
+	}{SchemaVersion: SchemaVersion, Points: points})
+}
@@ -0,0 +1,106 @@
+package history_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/matoous/godox/config"
+	"github.com/matoous/godox/history"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "godox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	commit := func(date string, files map[string]string) {
+		for name, content := range files {
+			filename := filepath.Join(dir, filepath.FromSlash(name))
+			if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := ioutil.WriteFile(filename, []byte(content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		for _, args := range [][]string{{"add", "."}, {"commit", "-q", "-m", date}} {
+			cmd := exec.Command("git", append([]string{"-c", "user.name=Alice", "-c", "user.email=alice@example.com"}, args...)...)
+			cmd.Dir = dir
+			cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("git %v: %v: %s", args, err, out)
+			}
+		}
+	}
+
+	if out, err := exec.Command("git", "init", "-q", dir).CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v: %s", err, out)
+	}
+
+	commit("2022-12-28T12:00:00Z", map[string]string{
+		"main.go": "package main\n\n// TODO: first\n",
+	})
+	commit("2023-01-05T12:00:00Z", map[string]string{
+		"main.go":          "package main\n\n// TODO: first\n// TODO: second\n",
+		"pkg/pkg.go":       "package pkg\n\n// FIXME: third\n",
+		"pkg/pkg_test.go":  "package pkg\n\n// TODO: test\n",
+		"vendor/vendor.go": "package vendor\n\n// TODO: vendored\n",
+		"notes.txt":        "TODO: not Go\n",
+	})
+
+	series, err := history.Run(context.Background(), history.Options{
+		Dir:   dir,
+		Since: time.Date(2022, 12, 25, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2023, 1, 15, 0, 0, 0, 0, time.UTC),
+		Step:  7 * 24 * time.Hour,
+	}, &config.GoDoxSettings{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := series.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// there is no commit before the first point, the last two points are of the same commit
+	if len(series) != 3 || series[1].Commit != series[2].Commit {
+		t.Fatalf("unexpected series %+v", series)
+	}
+
+	c1, c2 := series[0].Commit[:12], series[1].Commit[:12]
+
+	expected := "date,commit,total,FIXME,TODO\n" +
+		"2023-01-01," + c1 + ",1,0,1\n" +
+		"2023-01-08," + c2 + ",3,1,2\n" +
+		"2023-01-15," + c2 + ",3,1,2\n"
+	if buf.String() != expected {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expected, buf.String())
+	}
+
+	messages, err := history.Scan(context.Background(), dir, series[2].Commit, &config.GoDoxSettings{Tests: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(messages) != 4 || messages[3].Pos.Filename != filepath.Join("pkg", "pkg_test.go") {
+		t.Errorf("unexpected messages %v", messages)
+	}
+
+	if _, err := history.Run(context.Background(), history.Options{Dir: dir, Step: time.Hour}, &config.GoDoxSettings{}); err == nil {
+		t.Error("expected error without the start of the history")
+	}
+}
@@ -0,0 +1,98 @@
+// Package edit applies text edits to source files.
+package edit
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+)
+
+// Edit replaces the bytes from Start to End of the content with New.
+type Edit struct {
+	Start int
+	End   int
+	New   string
+}
+
+// Apply returns the content with the edits applied, the edits must not overlap.
+func Apply(content []byte, edits []Edit) ([]byte, error) {
+	sorted := append([]Edit(nil), edits...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	var (
+		buf  bytes.Buffer
+		last int
+	)
+
+	for _, e := range sorted {
+		if e.Start < last || e.End < e.Start || e.End > len(content) {
+			return nil, fmt.Errorf("invalid edit %d-%d", e.Start, e.End)
+		}
+
+		buf.Write(content[last:e.Start])
+		buf.WriteString(e.New)
+		last = e.End
+	}
+
+	buf.Write(content[last:])
+
+	return buf.Bytes(), nil
+}
+
+// ApplyFile applies the edits to the file, keeping its permissions.
+func ApplyFile(filename string, edits []Edit) error {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+
+	content, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+
+	edited, err := Apply(content, edits)
+	if err != nil {
+		return fmt.Errorf("%s: %w", filename, err)
+	}
+
+	return ioutil.WriteFile(filename, edited, info.Mode().Perm())
+}
+
+// ErrNoLine is returned for lines outside of the content.
+var ErrNoLine = errors.New("no such line")
+
+// AppendToLine returns edit appending the text to the comment line, before the trailing white space
+// and the end of a block comment, if the comment ends on the line.
+func AppendToLine(content []byte, line int, text string) (Edit, error) {
+	start := 0
+
+	for n := 1; n < line; n++ {
+		i := bytes.IndexByte(content[start:], '\n')
+		if i < 0 {
+			return Edit{}, ErrNoLine
+		}
+
+		start += i + 1
+	}
+
+	if line < 1 || start > len(content) {
+		return Edit{}, ErrNoLine
+	}
+
+	end := len(content)
+	if i := bytes.IndexByte(content[start:], '\n'); i >= 0 {
+		end = start + i
+	}
+
+	if i := bytes.LastIndex(content[start:end], []byte("*/")); i >= 0 {
+		end = start + i
+	}
+
+	end = start + len(bytes.TrimRight(content[start:end], " \t\r"))
+
+	return Edit{Start: end, End: end, New: text}, nil
+}
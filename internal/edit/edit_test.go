@@ -0,0 +1,85 @@
+package edit_test
+
+import (
+	"testing"
+
+	"github.com/matoous/godox/internal/edit"
+)
+
+func TestAppendToLine(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		content  string
+		line     int
+		expected string
+	}{
+		{
+			name:     "line comment",
+			content:  "package main\n\n// TODO: refactor\nfunc main() {}\n",
+			line:     3,
+			expected: "package main\n\n// TODO: refactor [#1]\nfunc main() {}\n",
+		},
+		{
+			name:     "trailing white space",
+			content:  "package main\n\n// TODO: refactor  \r\n",
+			line:     3,
+			expected: "package main\n\n// TODO: refactor [#1]  \r\n",
+		},
+		{
+			name:     "block comment",
+			content:  "package main\n\n/* TODO: refactor */\n",
+			line:     3,
+			expected: "package main\n\n/* TODO: refactor [#1] */\n",
+		},
+		{
+			name:     "last line without newline",
+			content:  "package main\n\nfunc main() {} // TODO: refactor",
+			line:     3,
+			expected: "package main\n\nfunc main() {} // TODO: refactor [#1]",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt //nolint // reason tt is ok on this context
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e, err := edit.AppendToLine([]byte(tt.content), tt.line, " [#1]")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual, err := edit.Apply([]byte(tt.content), []edit.Edit{e})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(actual) != tt.expected {
+				t.Errorf("not equal\nexpected: %q\nactual: %q", tt.expected, actual)
+			}
+		})
+	}
+
+	if _, err := edit.AppendToLine([]byte("package main\n"), 3, " [#1]"); err != edit.ErrNoLine {
+		t.Errorf("expected error for missing line, got %v", err)
+	}
+}
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	actual, err := edit.Apply([]byte("abcdef"), []edit.Edit{{Start: 4, End: 5, New: "E"}, {Start: 0, End: 2, New: "AB"}, {Start: 3, End: 3, New: "-"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(actual) != "ABc-dEf" {
+		t.Errorf("not equal\nexpected: %q\nactual: %q", "ABc-dEf", actual)
+	}
+
+	if _, err := edit.Apply([]byte("abcdef"), []edit.Edit{{Start: 0, End: 3}, {Start: 2, End: 4}}); err == nil {
+		t.Error("expected error for overlapping edits")
+	}
+}
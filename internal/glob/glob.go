@@ -0,0 +1,125 @@
+// Package glob implements matching of slash separated paths with doublestar style glob patterns.
+package glob
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Pattern is a compiled glob pattern. Besides the path.Match syntax, patterns support ** matching
+// any number of path elements and {a,b} alternatives.
+type Pattern struct {
+	re       *regexp.Regexp
+	anchored bool
+}
+
+// Compile compiles the glob pattern. Patterns starting with / are matched against the whole path,
+// other patterns against the whole path or any of its trailing elements, e.g. vendor/** matches
+// both vendor/a.go and pkg/vendor/a.go.
+func Compile(pattern string) (*Pattern, error) {
+	var (
+		b     strings.Builder
+		depth int
+	)
+
+	anchored := strings.HasPrefix(pattern, "/")
+	p := strings.TrimPrefix(pattern, "/")
+
+	b.WriteString("^")
+
+	for i := 0; i < len(p); i++ {
+		switch c := p[i]; c {
+		case '*':
+			if !strings.HasPrefix(p[i:], "**") {
+				b.WriteString("[^/]*")
+				continue
+			}
+
+			i++
+
+			if strings.HasPrefix(p[i+1:], "/") {
+				// **/ matches zero or more directories
+				i++
+				b.WriteString("(?:.*/)?")
+			} else {
+				b.WriteString(".*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(p[i+1:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("glob %q: %w", pattern, path.ErrBadPattern)
+			}
+
+			class := p[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+
+			b.WriteString("[" + class + "]")
+			i += end + 1
+		case '{':
+			depth++
+			b.WriteString("(?:")
+		case '}':
+			if depth == 0 {
+				return nil, fmt.Errorf("glob %q: %w", pattern, path.ErrBadPattern)
+			}
+
+			depth--
+			b.WriteString(")")
+		case ',':
+			if depth > 0 {
+				b.WriteString("|")
+			} else {
+				b.WriteString(",")
+			}
+		case '\\':
+			if i+1 < len(p) {
+				i++
+			}
+
+			b.WriteString(regexp.QuoteMeta(p[i : i+1]))
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	if depth != 0 {
+		return nil, fmt.Errorf("glob %q: %w", pattern, path.ErrBadPattern)
+	}
+
+	b.WriteString("$")
+
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, fmt.Errorf("glob %q: %w", pattern, path.ErrBadPattern)
+	}
+
+	return &Pattern{re: re, anchored: anchored}, nil
+}
+
+// Match reports whether the slash separated path matches the pattern.
+func (p *Pattern) Match(name string) bool {
+	name = strings.TrimPrefix(path.Clean(name), "./")
+
+	if p.anchored {
+		return p.re.MatchString(strings.TrimPrefix(name, "/"))
+	}
+
+	for {
+		if p.re.MatchString(name) {
+			return true
+		}
+
+		i := strings.IndexByte(name, '/')
+		if i < 0 {
+			return false
+		}
+
+		name = name[i+1:]
+	}
+}
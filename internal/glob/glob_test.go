@@ -0,0 +1,55 @@
+package glob_test
+
+import (
+	"testing"
+
+	"github.com/matoous/godox/internal/glob"
+)
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		pattern string
+		path    string
+		match   bool
+	}{
+		{pattern: "vendor/**", path: "vendor/a/b.go", match: true},
+		{pattern: "vendor/**", path: "pkg/vendor/b.go", match: true},
+		{pattern: "vendor/**", path: "pkg/vendors/b.go"},
+		{pattern: "/vendor/**", path: "pkg/vendor/b.go"},
+		{pattern: "/vendor/**", path: "vendor/b.go", match: true},
+		{pattern: "**/testdata/**", path: "a/testdata/b/c.go", match: true},
+		{pattern: "**/testdata/**", path: "testdata/c.go", match: true},
+		{pattern: "*.pb.go", path: "api/v1/service.pb.go", match: true},
+		{pattern: "api/*.go", path: "api/v1/service.go"},
+		{pattern: "api/**/*.go", path: "api/service.go", match: true},
+		{pattern: "migrations/{up,down}/*.go", path: "db/migrations/down/1.go", match: true},
+		{pattern: "migrations/{up,down}/*.go", path: "db/migrations/left/1.go"},
+		{pattern: "file?.go", path: "file1.go", match: true},
+		{pattern: "file[0-9].go", path: "file1.go", match: true},
+		{pattern: "file[!0-9].go", path: "file1.go"},
+		{pattern: "third_party", path: "./third_party", match: true},
+	}
+
+	for _, tt := range tests {
+		p, err := glob.Compile(tt.pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if match := p.Match(tt.path); match != tt.match {
+			t.Errorf("%s %s: expected %t, got %t", tt.pattern, tt.path, tt.match, match)
+		}
+	}
+}
+
+func TestCompileError(t *testing.T) {
+	t.Parallel()
+
+	for _, pattern := range []string{"[a", "{a,b", "a}"} {
+		if _, err := glob.Compile(pattern); err == nil {
+			t.Errorf("%s: expected error", pattern)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+// Package matcher implements matching of many keywords at the start of a comment line at once.
+package matcher
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// Matcher matches keywords at the start of a line, ignoring case unless the keyword is case sensitive.
+// Keywords are stored in a trie keyed by case folded runes so the cost of matching depends on the length
+// of the keyword found in the line rather than on the number of keywords.
+type Matcher struct {
+	keywords      []string
+	caseSensitive []bool
+	root          *node
+}
+
+type node struct {
+	// ascii children are looked up directly, others using the map
+	ascii    [utf8.RuneSelf]*node
+	children map[rune]*node
+	// keywords are the indexes of the keywords ending in the node in ascending order
+	keywords []int
+}
+
+func newNode() *node {
+	return &node{}
+}
+
+func (n *node) child(r rune) *node {
+	if r < utf8.RuneSelf {
+		return n.ascii[r]
+	}
+
+	return n.children[r]
+}
+
+func (n *node) add(r rune) *node {
+	if child := n.child(r); child != nil {
+		return child
+	}
+
+	child := newNode()
+
+	if r < utf8.RuneSelf {
+		n.ascii[r] = child
+	} else {
+		if n.children == nil {
+			n.children = make(map[rune]*node)
+		}
+
+		n.children[r] = child
+	}
+
+	return child
+}
+
+// New returns matcher for the keywords. Keywords are matched with the priority of their order.
+func New(keywords []string) *Matcher {
+	return NewCaseSensitive(keywords, nil)
+}
+
+// NewCaseSensitive returns matcher for the keywords, keywords are case sensitive if the value
+// with the same index is true. Keywords without the value are matched ignoring case.
+func NewCaseSensitive(keywords []string, caseSensitive []bool) *Matcher {
+	m := &Matcher{keywords: keywords, caseSensitive: caseSensitive, root: newNode()}
+
+	for i, kw := range keywords {
+		if kw == "" {
+			continue
+		}
+
+		n := m.root
+		for _, r := range kw {
+			n = n.add(fold(r))
+		}
+
+		n.keywords = append(n.keywords, i)
+	}
+
+	return m
+}
+
+// Match returns the keyword the line starts with and its length in bytes. The keyword must not be
+// directly followed by a letter or a number. If the line starts with more keywords, the first
+// one in the order given to New is returned.
+func (m *Matcher) Match(line []byte) (keyword string, size int, ok bool) {
+	best := -1
+	n := m.root
+
+	for offset := 0; offset < len(line); {
+		r, width := rune(line[offset]), 1
+		if r >= utf8.RuneSelf {
+			r, width = utf8.DecodeRune(line[offset:])
+		}
+
+		if n = n.child(fold(r)); n == nil {
+			break
+		}
+
+		offset += width
+
+		if len(n.keywords) == 0 || HasAlphanumRuneAdjacent(line[offset:]) {
+			continue
+		}
+
+		for _, i := range n.keywords {
+			if best >= 0 && best < i {
+				break
+			}
+
+			if !m.isCaseSensitive(i) || string(line[:offset]) == m.keywords[i] {
+				best, size = i, offset
+
+				break
+			}
+		}
+	}
+
+	if best < 0 {
+		return "", 0, false
+	}
+
+	return m.keywords[best], size, true
+}
+
+// Find returns the first keyword found in the line at a word boundary, i.e. at the start of the line
+// or after a rune which is not a letter or a number, and its offset and length in bytes.
+func (m *Matcher) Find(line []byte) (keyword string, start, size int, ok bool) {
+	boundary := true
+
+	for offset := 0; offset < len(line); {
+		r, width := rune(line[offset]), 1
+		if r >= utf8.RuneSelf {
+			r, width = utf8.DecodeRune(line[offset:])
+		}
+
+		if boundary {
+			if keyword, size, ok := m.Match(line[offset:]); ok {
+				return keyword, offset, size, true
+			}
+		}
+
+		boundary = !isAlphanum(r)
+		offset += width
+	}
+
+	return "", 0, 0, false
+}
+
+// Found is a keyword found in a line.
+type Found struct {
+	Keyword string
+	// Start and Size of the keyword in bytes.
+	Start, Size int
+}
+
+// FindAll returns all keywords found in the line at word boundaries, see Find, in the order of their offsets.
+func (m *Matcher) FindAll(line []byte) []Found {
+	var found []Found
+
+	boundary := true
+
+	for offset := 0; offset < len(line); {
+		if boundary {
+			if keyword, size, ok := m.Match(line[offset:]); ok {
+				found = append(found, Found{Keyword: keyword, Start: offset, Size: size})
+				offset += size
+
+				last, _ := utf8.DecodeLastRune(line[:offset])
+				boundary = !isAlphanum(last)
+
+				continue
+			}
+		}
+
+		r, width := rune(line[offset]), 1
+		if r >= utf8.RuneSelf {
+			r, width = utf8.DecodeRune(line[offset:])
+		}
+
+		boundary = !isAlphanum(r)
+		offset += width
+	}
+
+	return found
+}
+
+func (m *Matcher) isCaseSensitive(i int) bool {
+	return i < len(m.caseSensitive) && m.caseSensitive[i]
+}
+
+// HasKeyword reports whether the line starts with the keyword, ignoring case unless caseSensitive is set.
+// The keyword must not be directly followed by a letter or a number. Keywords longer than the line never match.
+func HasKeyword(line []byte, keyword string, caseSensitive bool) bool {
+	offset := 0
+
+	// runes are compared one by one as the case folded runes can differ in size
+	for _, kr := range keyword {
+		if offset >= len(line) {
+			return false
+		}
+
+		r, width := utf8.DecodeRune(line[offset:])
+		if r != kr && (caseSensitive || fold(r) != fold(kr)) {
+			return false
+		}
+
+		offset += width
+	}
+
+	return !HasAlphanumRuneAdjacent(line[offset:])
+}
+
+// HasAlphanumRuneAdjacent reports whether the text starts with a letter or a number. Ideographic and kana
+// characters are not considered as the scripts using them don't separate words by spaces.
+func HasAlphanumRuneAdjacent(rest []byte) bool {
+	if len(rest) == 0 {
+		return false
+	}
+
+	switch rest[0] { // most common cases
+	case ':', ' ', '(':
+		return false
+	}
+
+	r, _ := utf8.DecodeRune(rest)
+
+	return isAlphanum(r)
+}
+
+func isAlphanum(r rune) bool {
+	if r >= utf8.RuneSelf && unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) {
+		return false
+	}
+
+	return unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsDigit(r)
+}
+
+// fold returns canonical rune of the case folding orbit of the rune, the same for all runes
+// considered equal by strings.EqualFold.
+func fold(r rune) rune {
+	switch {
+	case 'a' <= r && r <= 'z':
+		return r - 'a' + 'A'
+	case r < utf8.RuneSelf:
+		return r
+	}
+
+	min := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < min {
+			min = f
+		}
+	}
+
+	return min
+}
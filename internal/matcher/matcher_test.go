@@ -0,0 +1,248 @@
+package matcher_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/matoous/godox/internal/matcher"
+)
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	m := matcher.New([]string{"TODO", "FIXME", "TODOS", "FIX", "ſtop"})
+
+	tests := []struct {
+		line    string
+		keyword string
+		size    int
+	}{
+		{line: "TODO: something", keyword: "TODO", size: 4},
+		{line: "todo(alice) something", keyword: "TODO", size: 4},
+		{line: "TODOS are fine", keyword: "TODOS", size: 5},
+		{line: "FIXME", keyword: "FIXME", size: 5},
+		{line: "FIX this", keyword: "FIX", size: 3},
+		{line: "FIXTURE"},
+		{line: "TODOC"},
+		{line: "STOP here", keyword: "ſtop", size: 4},
+		{line: "TOD"},
+		{line: ""},
+		{line: "\xff\xfe"},
+	}
+
+	for _, tt := range tests {
+		keyword, size, ok := m.Match([]byte(tt.line))
+		if keyword != tt.keyword || size != tt.size || ok != (tt.keyword != "") {
+			t.Errorf("%q: expected (%q, %d), got (%q, %d, %t)", tt.line, tt.keyword, tt.size, keyword, size, ok)
+		}
+	}
+}
+
+func TestMatchPriority(t *testing.T) {
+	t.Parallel()
+
+	// both keywords match, the first one given wins
+	m := matcher.New([]string{"TODO:", "TODO"})
+
+	if keyword, _, _ := m.Match([]byte("TODO: x")); keyword != "TODO:" {
+		t.Errorf("expected TODO:, got %q", keyword)
+	}
+}
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+
+	m := matcher.New([]string{"TODO", "FIXME"})
+
+	tests := []struct {
+		line    string
+		keyword string
+		start   int
+	}{
+		{line: "TODO: x", keyword: "TODO"},
+		{line: "see handler.go — TODO: refactor", keyword: "TODO", start: 19},
+		{line: "NOTE(x): fix later, FIXME", keyword: "FIXME", start: 20},
+		{line: "(todo) x", keyword: "TODO", start: 1},
+		{line: "MYTODO and TODOS"},
+		{line: ""},
+	}
+
+	for _, tt := range tests {
+		keyword, start, _, ok := m.Find([]byte(tt.line))
+		if keyword != tt.keyword || start != tt.start || ok != (tt.keyword != "") {
+			t.Errorf("%q: expected (%q, %d), got (%q, %d)", tt.line, tt.keyword, tt.start, keyword, start)
+		}
+	}
+}
+
+func TestFindAll(t *testing.T) {
+	t.Parallel()
+
+	m := matcher.New([]string{"TODO", "FIXME"})
+
+	tests := []struct {
+		line     string
+		expected []matcher.Found
+	}{
+		{
+			line: "TODO: x, FIXME: y, todo",
+			expected: []matcher.Found{
+				{Keyword: "TODO", Start: 0, Size: 4},
+				{Keyword: "FIXME", Start: 9, Size: 5},
+				{Keyword: "TODO", Start: 19, Size: 4},
+			},
+		},
+		{line: "TODOFIXME MYTODO"},
+		{line: ""},
+	}
+
+	for _, tt := range tests {
+		if found := m.FindAll([]byte(tt.line)); !reflect.DeepEqual(found, tt.expected) {
+			t.Errorf("%q: not equal\nexpected: %v\nactual: %v", tt.line, tt.expected, found)
+		}
+	}
+}
+
+func TestUnicodeKeywords(t *testing.T) {
+	t.Parallel()
+
+	keywords := []string{"要修正", "待办", "ÄNDERN"}
+	m := matcher.New(keywords)
+
+	tests := []struct {
+		line    string
+		keyword string
+		start   int
+	}{
+		{line: "要修正：境界チェック", keyword: "要修正"},
+		{line: "待办事项", keyword: "待办"},
+		{line: "待", start: -1},
+		{line: "ändern: später", keyword: "ÄNDERN"},
+		{line: "änderndes", start: -1},
+		{line: "バグを要修正", keyword: "要修正", start: 9},
+	}
+
+	for _, tt := range tests {
+		keyword, start, _, ok := m.Find([]byte(tt.line))
+		if keyword != tt.keyword || ok != (tt.keyword != "") || (ok && start != tt.start) {
+			t.Errorf("%q: expected (%q, %d), got (%q, %d)", tt.line, tt.keyword, tt.start, keyword, start)
+		}
+
+		if tt.start != 0 {
+			continue
+		}
+
+		var found string
+
+		for _, kw := range keywords {
+			if matcher.HasKeyword([]byte(tt.line), kw, false) {
+				found = kw
+			}
+		}
+
+		if found != tt.keyword {
+			t.Errorf("%q: expected %q to have keyword, got %q", tt.line, tt.keyword, found)
+		}
+	}
+}
+
+func TestMatchCaseSensitive(t *testing.T) {
+	t.Parallel()
+
+	m := matcher.NewCaseSensitive([]string{"TODO", "todo", "FIXME", "BUG"}, []bool{true, true, false})
+
+	tests := []struct {
+		line    string
+		keyword string
+	}{
+		{line: "TODO: x", keyword: "TODO"},
+		{line: "todo later", keyword: "todo"},
+		{line: "Todo later"},
+		{line: "fixme", keyword: "FIXME"},
+		{line: "bug", keyword: "BUG"},
+	}
+
+	for _, tt := range tests {
+		if keyword, _, ok := m.Match([]byte(tt.line)); keyword != tt.keyword || ok != (tt.keyword != "") {
+			t.Errorf("%q: expected %q, got %q", tt.line, tt.keyword, keyword)
+		}
+	}
+
+	if matcher.HasKeyword([]byte("Todo: x"), "TODO", true) || !matcher.HasKeyword([]byte("TODO: x"), "TODO", true) {
+		t.Error("expected only exact match of case sensitive TODO")
+	}
+}
+
+func TestLongKeywords(t *testing.T) {
+	t.Parallel()
+
+	keywords := []string{"OPTIMIZE", "DEPRECATED"}
+	m := matcher.New(keywords)
+
+	for _, line := range []string{"OPT", "OPTIMIZ", "DEPR", "DEPRECATE", "DEPRECATEDX"} {
+		if keyword, _, ok := m.Match([]byte(line)); ok {
+			t.Errorf("%q: expected no match, got %q", line, keyword)
+		}
+
+		for _, kw := range keywords {
+			if matcher.HasKeyword([]byte(line), kw, false) {
+				t.Errorf("%q: expected no match of %q", line, kw)
+			}
+		}
+	}
+
+	if !matcher.HasKeyword([]byte("deprecated: x"), "DEPRECATED", false) {
+		t.Error("expected match of DEPRECATED")
+	}
+}
+
+// naive is the matching previously done by comparing every keyword with the line.
+func naive(keywords []string, line []byte) (string, bool) {
+	for _, kw := range keywords {
+		if matcher.HasKeyword(line, kw, false) {
+			return kw, true
+		}
+	}
+
+	return "", false
+}
+
+func benchmarkKeywords(n int) []string {
+	keywords := []string{"TODO", "BUG", "FIXME"}
+	for i := len(keywords); i < n; i++ {
+		keywords = append(keywords, fmt.Sprintf("KEYWORD%d", i))
+	}
+
+	return keywords
+}
+
+var benchmarkLines = [][]byte{
+	[]byte("This comment doesn't contain any keyword at all"),
+	[]byte("KEYWORD40: near the end of the list"),
+	[]byte("TODO: at the beginning of the list"),
+	[]byte("Keep in mind that this is just a comment"),
+}
+
+func BenchmarkMatcher(b *testing.B) {
+	for _, n := range []int{3, 50} {
+		keywords := benchmarkKeywords(n)
+		m := matcher.New(keywords)
+
+		b.Run(fmt.Sprintf("trie/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, line := range benchmarkLines {
+					m.Match(line)
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("naive/%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, line := range benchmarkLines {
+					naive(keywords, line)
+				}
+			}
+		})
+	}
+}
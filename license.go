@@ -0,0 +1,31 @@
+package godox
+
+import (
+	"go/ast"
+	"regexp"
+)
+
+// licenseRe matches the common markers of the copyright and license notices.
+var licenseRe = regexp.MustCompile(`(?i)\bcopyright\b|\bSPDX-License-Identifier:|\blicensed under\b|` +
+	`\ball rights reserved\b|\bpermission is hereby granted\b|\(c\) \d{4}|©`)
+
+// licenseHeaders returns the comment groups of the license headers of the file, those preceding the package
+// clause with the license markers, or the first comment group if the file has no package clause.
+func licenseHeaders(file *ast.File) map[*ast.CommentGroup]bool {
+	headers := make(map[*ast.CommentGroup]bool)
+
+	for i, c := range file.Comments {
+		if file.Package.IsValid() && c.Pos() > file.Package || !file.Package.IsValid() && i > 0 {
+			break
+		}
+
+		for _, ci := range c.List {
+			if licenseRe.MatchString(ci.Text) {
+				headers[c] = true
+				break
+			}
+		}
+	}
+
+	return headers
+}
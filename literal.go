@@ -0,0 +1,98 @@
+package godox
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+
+	"github.com/matoous/godox/config"
+)
+
+// stringLiterals returns the string literals of the file as comment groups of block comments with the text
+// of the literals at the positions of the literals, the escape sequences are replaced by spaces. The import
+// paths and struct tags are left out.
+func stringLiterals(file *ast.File) []*ast.CommentGroup {
+	var (
+		groups []*ast.CommentGroup
+		tags   = make(map[*ast.BasicLit]bool)
+	)
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.ImportSpec:
+			return false
+		case *ast.Field:
+			if n.Tag != nil {
+				tags[n.Tag] = true
+			}
+		case *ast.BasicLit:
+			if n.Kind != token.STRING || tags[n] || len(n.Value) < len(`""`) {
+				break
+			}
+
+			text := n.Value[1 : len(n.Value)-1]
+			if n.Value[0] == '"' {
+				text = blankEscapes(text)
+			}
+
+			// the block comment marker takes the place of the quote and the character before it
+			groups = append(groups, &ast.CommentGroup{List: []*ast.Comment{{Slash: n.ValuePos - 1, Text: "/*" + text + "*/"}}})
+		}
+
+		return true
+	})
+
+	return groups
+}
+
+// blankEscapes replaces the escape sequences of the interpreted string literal by spaces, so the keywords
+// following them are found at their positions, e.g. in "\tTODO: indent".
+func blankEscapes(text string) string {
+	b := []byte(text)
+
+	for i := 0; i < len(b); i++ {
+		if b[i] != '\\' || i+1 == len(b) {
+			continue
+		}
+
+		n := 2
+
+		switch c := b[i+1]; {
+		case c == 'x':
+			n = 4
+		case c == 'u':
+			n = 6
+		case c == 'U':
+			n = 10
+		case '0' <= c && c <= '7':
+			n = 4
+		}
+
+		for j := i; j < i+n && j < len(b); j++ {
+			b[j] = ' '
+		}
+
+		i += n - 1
+	}
+
+	return string(b)
+}
+
+// getMessagesString returns the messages of the keywords found anywhere in the lines of the string literal.
+func getMessagesString(comment *ast.Comment, fset *token.FileSet, settings *config.CompiledSettings) []Message {
+	var messages []Message
+
+	for _, line := range commentLines(comment.Text) {
+		for _, match := range settings.FindKeywords(line.text) {
+			rest := line.from(match.Start)
+			kw, text := match.Keyword, rest.text
+
+			m := newMessage(linePosition(comment, fset, rest), kw, RuleStringLiteral, text,
+				fmt.Sprintf("String literal contains %s: ", settings.Canonical(kw)), settings)
+
+			messages = append(messages, annotate([]Message{m}, kw, text, settings)...)
+		}
+	}
+
+	return messages
+}
@@ -0,0 +1,464 @@
+// Package lsp implements a language server publishing the findings of the open Go files as diagnostics,
+// with code actions applying the suggested fixes of the format rules.
+//
+// The server speaks JSON-RPC 2.0 over a stream, e.g. the standard input and output, and supports
+// the initialize, shutdown and exit lifecycle, full synchronization of the text documents and
+// the textDocument/codeAction requests.
+package lsp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/matoous/godox"
+	"github.com/matoous/godox/config"
+)
+
+// JSON-RPC error codes.
+const (
+	codeParseError     = -32700
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// Diagnostic severities.
+const (
+	severityError       = 1
+	severityWarning     = 2
+	severityInformation = 3
+)
+
+// messageTypeError is the type of the logged errors.
+const messageTypeError = 1
+
+// textDocumentSyncFull sends the whole content of the documents on every change.
+const textDocumentSyncFull = 1
+
+// Server publishes the findings of the open documents as diagnostics.
+type Server struct {
+	// Timeout limits each lint of a document if set.
+	Timeout time.Duration
+
+	settings *config.GoDoxSettings
+
+	mu sync.Mutex
+	w  io.Writer
+	// documents are the contents of the open documents and findings are their messages by URIs
+	documents map[string][]byte
+	findings  map[string][]godox.Message
+}
+
+// NewServer returns server running the linter with the settings.
+func NewServer(settings *config.GoDoxSettings) *Server {
+	return &Server{
+		settings:  settings,
+		documents: make(map[string][]byte),
+		findings:  make(map[string][]godox.Message),
+	}
+}
+
+type request struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id,omitempty"`
+	Method  string           `json:"method"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id"`
+	Result  interface{}      `json:"result"`
+	Error   *responseError   `json:"error,omitempty"`
+}
+
+type responseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type notification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+// Position is zero based line and UTF-16 character offset within the line.
+type Position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+// Range is a span in a text document, the End is exclusive.
+type Range struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// Diagnostic is a finding shown in the editor.
+type Diagnostic struct {
+	Range    Range  `json:"range"`
+	Severity int    `json:"severity"`
+	Code     string `json:"code,omitempty"`
+	Source   string `json:"source"`
+	Message  string `json:"message"`
+}
+
+// TextEdit replaces the range of the document with the new text.
+type TextEdit struct {
+	Range   Range  `json:"range"`
+	NewText string `json:"newText"`
+}
+
+// WorkspaceEdit are the edits of the documents by URIs.
+type WorkspaceEdit struct {
+	Changes map[string][]TextEdit `json:"changes"`
+}
+
+// CodeAction is a quick fix of a diagnostic.
+type CodeAction struct {
+	Title       string        `json:"title"`
+	Kind        string        `json:"kind"`
+	Diagnostics []Diagnostic  `json:"diagnostics,omitempty"`
+	Edit        WorkspaceEdit `json:"edit"`
+}
+
+type textDocumentItem struct {
+	URI  string `json:"uri"`
+	Text string `json:"text"`
+}
+
+type textDocumentIdentifier struct {
+	URI string `json:"uri"`
+}
+
+type didOpenParams struct {
+	TextDocument textDocumentItem `json:"textDocument"`
+}
+
+type didChangeParams struct {
+	TextDocument   textDocumentIdentifier `json:"textDocument"`
+	ContentChanges []struct {
+		Text string `json:"text"`
+	} `json:"contentChanges"`
+}
+
+type didCloseParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+}
+
+type codeActionParams struct {
+	TextDocument textDocumentIdentifier `json:"textDocument"`
+	Range        Range                  `json:"range"`
+}
+
+type publishDiagnosticsParams struct {
+	URI         string       `json:"uri"`
+	Diagnostics []Diagnostic `json:"diagnostics"`
+}
+
+// errExit is returned by handle once the exit notification is received.
+var errExit = errors.New("exit")
+
+// Serve reads the requests from r and writes the responses and diagnostics to w until the exit notification
+// is received, the input ends or the context is canceled.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	s.w = w
+	in := textproto.NewReader(bufio.NewReader(r))
+
+	for ctx.Err() == nil {
+		body, err := readMessage(in)
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		var req request
+		if err := json.Unmarshal(body, &req); err != nil {
+			if err := s.reply(nil, nil, &responseError{Code: codeParseError, Message: err.Error()}); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err := s.handle(ctx, req); err != nil {
+			if err == errExit {
+				return nil
+			}
+
+			return err
+		}
+	}
+
+	return ctx.Err()
+}
+
+// handle handles the request, only errors writing the responses are returned.
+func (s *Server) handle(ctx context.Context, req request) error {
+	var (
+		result interface{}
+		rerr   *responseError
+	)
+
+	switch req.Method {
+	case "initialize":
+		result = map[string]interface{}{
+			"capabilities": map[string]interface{}{
+				"textDocumentSync":   textDocumentSyncFull,
+				"codeActionProvider": true,
+			},
+			"serverInfo": map[string]string{"name": "godox"},
+		}
+	case "shutdown":
+	case "exit":
+		return errExit
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if rerr = decode(req.Params, &params); rerr == nil {
+			return s.update(ctx, params.TextDocument.URI, []byte(params.TextDocument.Text))
+		}
+	case "textDocument/didChange":
+		var params didChangeParams
+		if rerr = decode(req.Params, &params); rerr == nil && len(params.ContentChanges) > 0 {
+			// the documents are synchronized in full, the last change has the whole content
+			text := params.ContentChanges[len(params.ContentChanges)-1].Text
+			return s.update(ctx, params.TextDocument.URI, []byte(text))
+		}
+	case "textDocument/didClose":
+		var params didCloseParams
+		if rerr = decode(req.Params, &params); rerr == nil {
+			return s.close(params.TextDocument.URI)
+		}
+	case "textDocument/codeAction":
+		var params codeActionParams
+		if rerr = decode(req.Params, &params); rerr == nil {
+			result = s.codeActions(params.TextDocument.URI, params.Range)
+		}
+	default:
+		if req.ID != nil {
+			rerr = &responseError{Code: codeMethodNotFound, Message: "method not found: " + req.Method}
+		}
+	}
+
+	// notifications have no response
+	if req.ID == nil {
+		return nil
+	}
+
+	return s.reply(req.ID, result, rerr)
+}
+
+// update lints the content of the document and publishes its diagnostics.
+func (s *Server) update(ctx context.Context, uri string, content []byte) error {
+	if s.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, s.Timeout)
+		defer cancel()
+	}
+
+	messages, err := godox.RunSource(ctx, filename(uri), content, s.settings)
+	if err != nil {
+		return s.notify("window/logMessage", map[string]interface{}{"type": messageTypeError, "message": "godox: " + err.Error()})
+	}
+
+	var findings []godox.Message
+
+	for _, m := range messages {
+		if !m.Suppressed {
+			findings = append(findings, m)
+		}
+	}
+
+	s.mu.Lock()
+	s.documents[uri] = content
+	s.findings[uri] = findings
+	s.mu.Unlock()
+
+	diagnostics := make([]Diagnostic, 0, len(findings))
+	for _, m := range findings {
+		diagnostics = append(diagnostics, diagnostic(content, m))
+	}
+
+	return s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{URI: uri, Diagnostics: diagnostics})
+}
+
+// close forgets the document and clears its diagnostics.
+func (s *Server) close(uri string) error {
+	s.mu.Lock()
+	delete(s.documents, uri)
+	delete(s.findings, uri)
+	s.mu.Unlock()
+
+	return s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{URI: uri, Diagnostics: []Diagnostic{}})
+}
+
+// codeActions returns the fixes of the findings of the document on the lines of the range.
+func (s *Server) codeActions(uri string, r Range) []CodeAction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	content := s.documents[uri]
+	actions := []CodeAction{}
+
+	for _, m := range s.findings[uri] {
+		if m.Fix == "" {
+			continue
+		}
+
+		// the fixes are offered anywhere on the lines of the diagnostics
+		d := diagnostic(content, m)
+		if d.Range.End.Line < r.Start.Line || r.End.Line < d.Range.Start.Line {
+			continue
+		}
+
+		actions = append(actions, CodeAction{
+			Title:       "Rewrite to " + strconv.Quote(m.Fix),
+			Kind:        "quickfix",
+			Diagnostics: []Diagnostic{d},
+			Edit:        WorkspaceEdit{Changes: map[string][]TextEdit{uri: {{Range: d.Range, NewText: m.Fix}}}},
+		})
+	}
+
+	return actions
+}
+
+// diagnostic returns the diagnostic of the message spanning the comment line.
+func diagnostic(content []byte, m godox.Message) Diagnostic {
+	severity := severityWarning
+
+	switch m.Severity {
+	case godox.SeverityError:
+		severity = severityError
+	case godox.SeverityInfo:
+		severity = severityInformation
+	}
+
+	// the text merged with the continuation lines ends on a later line
+	end := m.TextEnd.Offset
+	if m.TextEnd.Line == 0 {
+		end = m.Pos.Offset + len(m.Text)
+	}
+
+	return Diagnostic{
+		Range: Range{
+			Start: position(content, m.Pos.Offset),
+			End:   position(content, end),
+		},
+		Severity: severity,
+		Code:     m.RuleID,
+		Source:   "godox",
+		Message:  m.Description(),
+	}
+}
+
+// position converts the byte offset within the content to the position.
+func position(content []byte, offset int) Position {
+	if offset > len(content) {
+		offset = len(content)
+	}
+
+	var p Position
+
+	start := 0
+
+	for i := 0; i < offset; i++ {
+		if content[i] == '\n' {
+			p.Line++
+			start = i + 1
+		}
+	}
+
+	for line := content[start:offset]; len(line) > 0; {
+		r, size := utf8.DecodeRune(line)
+		p.Character += len(utf16.Encode([]rune{r}))
+		line = line[size:]
+	}
+
+	return p
+}
+
+// filename returns the path of the file URI, other URIs are used as they are.
+func filename(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+
+	return filepath.FromSlash(u.Path)
+}
+
+func decode(params json.RawMessage, v interface{}) *responseError {
+	if err := json.Unmarshal(params, v); err != nil {
+		return &responseError{Code: codeInvalidParams, Message: err.Error()}
+	}
+
+	return nil
+}
+
+func (s *Server) reply(id *json.RawMessage, result interface{}, rerr *responseError) error {
+	return s.write(response{JSONRPC: "2.0", ID: id, Result: result, Error: rerr})
+}
+
+func (s *Server) notify(method string, params interface{}) error {
+	return s.write(notification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+// write writes the message with the Content-Length header.
+func (s *Server) write(v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := fmt.Fprintf(s.w, "Content-Length: %d\r\n\r\n", len(body)); err != nil {
+		return err
+	}
+
+	_, err = s.w.Write(body)
+
+	return err
+}
+
+// readMessage reads the body of the message following the headers.
+func readMessage(in *textproto.Reader) ([]byte, error) {
+	header, err := in.ReadMIMEHeader()
+	if err != nil {
+		if err == io.EOF && len(header) == 0 {
+			return nil, io.EOF
+		}
+
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+
+	length, err := strconv.Atoi(strings.TrimSpace(header.Get("Content-Length")))
+	if err != nil || length < 0 {
+		return nil, fmt.Errorf("invalid Content-Length %q", header.Get("Content-Length"))
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(in.R, body); err != nil {
+		return nil, fmt.Errorf("read body: %w", err)
+	}
+
+	return body, nil
+}
@@ -0,0 +1,211 @@
+package lsp_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/matoous/godox/config"
+	"github.com/matoous/godox/lsp"
+)
+
+const uri = "file:///src/main.go"
+
+// frame returns the messages with the Content-Length headers.
+func frame(t *testing.T, messages ...interface{}) io.Reader {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	for _, m := range messages {
+		body, err := json.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		fmt.Fprintf(&buf, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	}
+
+	return &buf
+}
+
+// unframe returns the bodies of the messages written by the server.
+func unframe(t *testing.T, r io.Reader) []map[string]interface{} {
+	t.Helper()
+
+	var (
+		in       = textproto.NewReader(bufio.NewReader(r))
+		messages []map[string]interface{}
+	)
+
+	for {
+		header, err := in.ReadMIMEHeader()
+		if err == io.EOF {
+			return messages
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		length, err := strconv.Atoi(header.Get("Content-Length"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body := make([]byte, length)
+		if _, err := io.ReadFull(in.R, body); err != nil {
+			t.Fatal(err)
+		}
+
+		var m map[string]interface{}
+		if err := json.Unmarshal(body, &m); err != nil {
+			t.Fatal(err)
+		}
+
+		messages = append(messages, m)
+	}
+}
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+
+	settings := &config.GoDoxSettings{
+		Format: true,
+		FormatRules: []config.GoDoxFormatRule{
+			{Keyword: "TODO", RegularExpression: `^TODO: \w`},
+		},
+	}
+
+	input := frame(t,
+		map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": map[string]interface{}{}},
+		map[string]interface{}{"jsonrpc": "2.0", "method": "initialized", "params": map[string]interface{}{}},
+		map[string]interface{}{"jsonrpc": "2.0", "method": "textDocument/didOpen", "params": map[string]interface{}{
+			"textDocument": map[string]interface{}{"uri": uri, "text": "package main\n\n// TODO: fine\n"},
+		}},
+		map[string]interface{}{"jsonrpc": "2.0", "method": "textDocument/didChange", "params": map[string]interface{}{
+			"textDocument":   map[string]interface{}{"uri": uri},
+			"contentChanges": []map[string]string{{"text": "package main\n\nvar ü = 1 /* todo fix */\n"}},
+		}},
+		map[string]interface{}{"jsonrpc": "2.0", "id": 2, "method": "textDocument/codeAction", "params": map[string]interface{}{
+			"textDocument": map[string]interface{}{"uri": uri},
+			"range": map[string]interface{}{
+				"start": map[string]int{"line": 2, "character": 0},
+				"end":   map[string]int{"line": 2, "character": 0},
+			},
+		}},
+		map[string]interface{}{"jsonrpc": "2.0", "id": 3, "method": "textDocument/hover", "params": map[string]interface{}{}},
+		map[string]interface{}{"jsonrpc": "2.0", "method": "textDocument/didClose", "params": map[string]interface{}{
+			"textDocument": map[string]interface{}{"uri": uri},
+		}},
+		map[string]interface{}{"jsonrpc": "2.0", "id": 4, "method": "shutdown"},
+		map[string]interface{}{"jsonrpc": "2.0", "method": "exit"},
+	)
+
+	var output bytes.Buffer
+	if err := lsp.NewServer(settings).Serve(context.Background(), input, &output); err != nil {
+		t.Fatal(err)
+	}
+
+	messages := unframe(t, &output)
+	if len(messages) != 7 {
+		t.Fatalf("expected 7 messages, got %d: %v", len(messages), messages)
+	}
+
+	capabilities := messages[0]["result"].(map[string]interface{})["capabilities"]
+	if expected := map[string]interface{}{"textDocumentSync": 1.0, "codeActionProvider": true}; !reflect.DeepEqual(capabilities, expected) {
+		t.Errorf("not equal\nexpected: %v\nactual: %v", expected, capabilities)
+	}
+
+	// the document is fine when opened, the change adds a finding after a non-ASCII character
+	if diagnostics := messages[1]["params"].(map[string]interface{})["diagnostics"]; len(diagnostics.([]interface{})) != 0 {
+		t.Errorf("unexpected diagnostics %v", diagnostics)
+	}
+
+	rng := map[string]interface{}{
+		"start": map[string]interface{}{"line": 2.0, "character": 13.0},
+		"end":   map[string]interface{}{"line": 2.0, "character": 21.0},
+	}
+
+	diagnostic := map[string]interface{}{
+		"range":    rng,
+		"severity": 2.0,
+		"code":     "format",
+		"source":   "godox",
+		"message":  `Line does not match the expected format: ^TODO: \w, "todo fix"`,
+	}
+
+	if actual := messages[2]["params"].(map[string]interface{})["diagnostics"]; !reflect.DeepEqual(actual, []interface{}{diagnostic}) {
+		t.Errorf("not equal\nexpected: %v\nactual: %v", []interface{}{diagnostic}, actual)
+	}
+
+	action := map[string]interface{}{
+		"title":       `Rewrite to "TODO: fix"`,
+		"kind":        "quickfix",
+		"diagnostics": []interface{}{diagnostic},
+		"edit": map[string]interface{}{"changes": map[string]interface{}{
+			uri: []interface{}{map[string]interface{}{"range": rng, "newText": "TODO: fix"}},
+		}},
+	}
+
+	if actual := messages[3]["result"]; !reflect.DeepEqual(actual, []interface{}{action}) {
+		t.Errorf("not equal\nexpected: %v\nactual: %v", []interface{}{action}, actual)
+	}
+
+	if code := messages[4]["error"].(map[string]interface{})["code"]; code != -32601.0 {
+		t.Errorf("unexpected error code %v", code)
+	}
+
+	if diagnostics := messages[5]["params"].(map[string]interface{})["diagnostics"]; len(diagnostics.([]interface{})) != 0 {
+		t.Errorf("unexpected diagnostics %v", diagnostics)
+	}
+
+	if _, ok := messages[6]["result"]; !ok || messages[6]["id"] != 4.0 {
+		t.Errorf("unexpected shutdown response %v", messages[6])
+	}
+}
+
+func TestServerMultiline(t *testing.T) {
+	t.Parallel()
+
+	input := frame(t,
+		map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": map[string]interface{}{}},
+		map[string]interface{}{"jsonrpc": "2.0", "method": "textDocument/didOpen", "params": map[string]interface{}{
+			"textDocument": map[string]interface{}{"uri": uri, "text": "package main\n\n// TODO: first line\n//   continued here\n"},
+		}},
+		map[string]interface{}{"jsonrpc": "2.0", "id": 2, "method": "shutdown"},
+		map[string]interface{}{"jsonrpc": "2.0", "method": "exit"},
+	)
+
+	var output bytes.Buffer
+	if err := lsp.NewServer(&config.GoDoxSettings{Multiline: true}).Serve(context.Background(), input, &output); err != nil {
+		t.Fatal(err)
+	}
+
+	messages := unframe(t, &output)
+	if len(messages) != 3 {
+		t.Fatalf("expected 3 messages, got %d: %v", len(messages), messages)
+	}
+
+	// the range ends at the end of the continuation line
+	diagnostics := messages[1]["params"].(map[string]interface{})["diagnostics"].([]interface{})
+	if len(diagnostics) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %v", diagnostics)
+	}
+
+	expected := map[string]interface{}{
+		"start": map[string]interface{}{"line": 2.0, "character": 3.0},
+		"end":   map[string]interface{}{"line": 3.0, "character": 19.0},
+	}
+
+	if actual := diagnostics[0].(map[string]interface{})["range"]; !reflect.DeepEqual(actual, expected) {
+		t.Errorf("not equal\nexpected: %v\nactual: %v", expected, actual)
+	}
+}
@@ -0,0 +1,180 @@
+package godox
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/matoous/godox/config"
+)
+
+// RunPackages loads packages matching the patterns and runs the godox linter on all their files.
+// The patterns are the same as for the go command, e.g. ./... or std.
+//
+// Only files matching the build constraints, including the BuildTags from the settings, are scanned.
+// Files using cgo are scanned in their original form, only the C comments of their cgo preambles are scanned.
+// The assembly files, .s, of the packages are scanned as well. Test files are scanned if Tests is enabled.
+// Other files matching the Include patterns, e.g. *.proto, are looked up in the local directories of the
+// patterns, e.g. ./api, or in the directories and their subdirectories for patterns like ./..., skipping
+// the directories ignored by the go command.
+// File names in the messages are relative to the current working directory.
+// The files are scanned in parallel, see RunFiles.
+func RunPackages(ctx context.Context, patterns []string, settings *config.GoDoxSettings) ([]Message, error) {
+	compiled, err := settings.Compile()
+	if err != nil {
+		return nil, err
+	}
+
+	filenames, err := packageFiles(ctx, patterns, compiled)
+	if err != nil {
+		return nil, err
+	}
+
+	return runFiles(ctx, filenames, compiled, ioutil.ReadFile)
+}
+
+// RunPackagesFunc runs the godox linter on the packages like RunPackages, calling fn with the sorted messages
+// of each file as soon as the file is scanned instead of returning all of them at once, e.g. to stream them.
+// The fn is not called concurrently, but the files are scanned in parallel so the order of the files is not
+// defined and the messages are not deduplicated across the files. Once fn returns an error the scanning stops
+// and the error is returned.
+func RunPackagesFunc(ctx context.Context, patterns []string, settings *config.GoDoxSettings, fn func([]Message) error) error {
+	compiled, err := settings.Compile()
+	if err != nil {
+		return err
+	}
+
+	filenames, err := packageFiles(ctx, patterns, compiled)
+	if err != nil {
+		return err
+	}
+
+	return scanFiles(ctx, filenames, compiled, ioutil.ReadFile, func(_ int, messages []Message, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if len(messages) == 0 {
+			return nil
+		}
+
+		if compiled.Deduplicate {
+			messages = Deduplicate(messages)
+		} else {
+			SortMessages(messages)
+		}
+
+		return fn(messages)
+	})
+}
+
+// packageFiles returns sorted list of Go and assembly files of the packages matching the patterns and of the
+// included other files, relative to the working directory if possible.
+func packageFiles(ctx context.Context, patterns []string, settings *config.CompiledSettings) ([]string, error) {
+	var buildFlags []string
+	if len(settings.BuildTags) > 0 {
+		buildFlags = append(buildFlags, "-tags="+strings.Join(settings.BuildTags, ","))
+	}
+
+	pkgs, err := packages.Load(&packages.Config{
+		Context:    ctx,
+		Mode:       packages.NeedName | packages.NeedFiles,
+		BuildFlags: buildFlags,
+		// files importing C would be otherwise left out if there is no C compiler available
+		Env:   append(os.Environ(), "CGO_ENABLED=1"),
+		Tests: settings.Tests,
+	}, patterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	// test variants of a package share files with the package itself
+	files := make(map[string]struct{})
+
+	for _, pkg := range pkgs {
+		for _, e := range pkg.Errors {
+			return nil, e
+		}
+
+		for _, f := range pkg.GoFiles {
+			files[f] = struct{}{}
+		}
+
+		for _, f := range pkg.OtherFiles {
+			if isAssembly(f) {
+				files[f] = struct{}{}
+			}
+		}
+	}
+
+	if len(settings.Include) > 0 {
+		if err := includedFiles(patterns, settings, files); err != nil {
+			return nil, err
+		}
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	filenames := make([]string, 0, len(files))
+	for f := range files {
+		filenames = append(filenames, relative(wd, f))
+	}
+
+	sort.Strings(filenames)
+
+	return filenames, nil
+}
+
+// includedFiles adds the files matching the Include patterns in the local directories of the package patterns
+// to the files.
+func includedFiles(patterns []string, settings *config.CompiledSettings, files map[string]struct{}) error {
+	for _, pattern := range patterns {
+		dir := strings.TrimSuffix(pattern, "/...")
+		recursive := dir != pattern
+
+		if !strings.HasPrefix(dir, ".") && !filepath.IsAbs(dir) {
+			continue
+		}
+
+		err := filepath.Walk(dir, func(name string, info os.FileInfo, err error) error {
+			switch {
+			case err != nil:
+				return err
+			case info.IsDir() && name != dir && (!recursive || ignoredDir(info.Name())):
+				return filepath.SkipDir
+			case !info.IsDir() && !isGoSource(name) && !isAssembly(name) && settings.IncludesFile(name):
+				files[name] = struct{}{}
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// relative returns filename relative to the working directory if possible.
+func relative(wd, filename string) string {
+	rel, err := filepath.Rel(wd, filename)
+	if err != nil {
+		return filename
+	}
+
+	return rel
+}
+
+// ignoredDir reports whether the go command ignores the directory.
+func ignoredDir(name string) bool {
+	return name == "testdata" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")
+}
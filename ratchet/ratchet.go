@@ -0,0 +1,142 @@
+// Package ratchet enforces reduction of the findings by recording their counts per package and failing
+// when the counts increase, the recorded counts are lowered as the findings are resolved.
+package ratchet
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/matoous/godox"
+)
+
+// SchemaVersion is the version of the ratchet file schema.
+const SchemaVersion = 1
+
+// DefaultFile is the default name of the ratchet file.
+const DefaultFile = ".godox-ratchet.json"
+
+// Ratchet is the maximum number of findings per package.
+type Ratchet struct {
+	SchemaVersion int `json:"schema_version"`
+	// Packages are keyed by the slash separated directories of the files, packages without findings are left out.
+	Packages map[string]int `json:"packages"`
+}
+
+// Increase is a package with more findings than recorded.
+type Increase struct {
+	Package string
+	Max     int
+	Count   int
+}
+
+func (i Increase) String() string {
+	return fmt.Sprintf("%s: %d findings, at most %d allowed", i.Package, i.Count, i.Max)
+}
+
+// New creates ratchet from the messages, suppressed messages are not counted.
+func New(messages []godox.Message) *Ratchet {
+	r := &Ratchet{SchemaVersion: SchemaVersion, Packages: make(map[string]int)}
+
+	for pkg, count := range godox.Stats(messages).Packages {
+		r.Packages[pkg] = count
+	}
+
+	return r
+}
+
+// Check compares the counts of the messages with the ratchet and returns the packages with more findings
+// than recorded, sorted by the packages, packages missing in the ratchet have no findings allowed.
+// The returned ratchet has the counts of the packages with fewer findings lowered. The packages without findings
+// might not have been scanned, so their counts are kept unless prune is set, when all packages were scanned,
+// and they are left out.
+func (r *Ratchet) Check(messages []godox.Message, prune bool) (*Ratchet, []Increase) {
+	current := New(messages)
+	lowered := &Ratchet{SchemaVersion: SchemaVersion, Packages: make(map[string]int, len(r.Packages))}
+
+	var increases []Increase
+
+	for pkg, max := range r.Packages {
+		if count, ok := current.Packages[pkg]; (ok || prune) && count < max {
+			max = count
+		}
+
+		if max > 0 {
+			lowered.Packages[pkg] = max
+		}
+	}
+
+	for pkg, count := range current.Packages {
+		if max := r.Packages[pkg]; count > max {
+			increases = append(increases, Increase{Package: pkg, Max: max, Count: count})
+		}
+	}
+
+	sort.Slice(increases, func(i, j int) bool { return increases[i].Package < increases[j].Package })
+
+	return lowered, increases
+}
+
+// Total returns the number of findings of all packages.
+func (r *Ratchet) Total() int {
+	var total int
+	for _, count := range r.Packages {
+		total += count
+	}
+
+	return total
+}
+
+// Read reads ratchet from the reader.
+func Read(rd io.Reader) (*Ratchet, error) {
+	var r Ratchet
+	if err := json.NewDecoder(rd).Decode(&r); err != nil {
+		return nil, fmt.Errorf("decode ratchet: %w", err)
+	}
+
+	if r.SchemaVersion != SchemaVersion {
+		return nil, fmt.Errorf("unsupported ratchet schema version %d", r.SchemaVersion)
+	}
+
+	if r.Packages == nil {
+		r.Packages = make(map[string]int)
+	}
+
+	return &r, nil
+}
+
+// Load reads ratchet from the file.
+func Load(path string) (*Ratchet, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Read(f)
+}
+
+// Write writes the ratchet to the writer, the packages are sorted.
+func (r *Ratchet) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(r)
+}
+
+// Save writes the ratchet to the file.
+func (r *Ratchet) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := r.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,88 @@
+package ratchet_test
+
+import (
+	"bytes"
+	"go/token"
+	"reflect"
+	"testing"
+
+	"github.com/matoous/godox"
+	"github.com/matoous/godox/ratchet"
+)
+
+func messages(files ...string) []godox.Message {
+	var list []godox.Message
+	for _, file := range files {
+		list = append(list, godox.Message{Pos: token.Position{Filename: file}, Keyword: "TODO"})
+	}
+
+	return list
+}
+
+func TestRatchet(t *testing.T) {
+	t.Parallel()
+
+	r := ratchet.New(append(messages("a/a.go", "a/b.go", "b/a.go", "c/a.go"),
+		godox.Message{Pos: token.Position{Filename: "c/a.go"}, Keyword: "TODO", Suppressed: true}))
+
+	var buf bytes.Buffer
+	if err := r.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := ratchet.Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := map[string]int{"a": 2, "b": 1, "c": 1}; !reflect.DeepEqual(r.Packages, expected) {
+		t.Fatalf("not equal\nexpected: %v\nactual: %v", expected, r.Packages)
+	}
+
+	// a finding of a was resolved, b got a new finding, c has no findings left and d is a new package
+	lowered, increases := r.Check(messages("a/a.go", "b/a.go", "b/b.go", "d/a.go"), true)
+
+	if expected := map[string]int{"a": 1, "b": 1}; !reflect.DeepEqual(lowered.Packages, expected) {
+		t.Errorf("not equal\nexpected: %v\nactual: %v", expected, lowered.Packages)
+	}
+
+	expected := []ratchet.Increase{
+		{Package: "b", Max: 1, Count: 2},
+		{Package: "d", Max: 0, Count: 1},
+	}
+
+	if !reflect.DeepEqual(increases, expected) {
+		t.Errorf("not equal\nexpected: %v\nactual: %v", expected, increases)
+	}
+
+	if total := lowered.Total(); total != 2 {
+		t.Errorf("unexpected total %d", total)
+	}
+}
+
+func TestRatchetPartial(t *testing.T) {
+	t.Parallel()
+
+	r := ratchet.New(messages("a/a.go", "a/b.go", "b/a.go", "c/a.go"))
+
+	// only a was scanned, the other packages keep their counts
+	lowered, increases := r.Check(messages("a/a.go"), false)
+
+	if expected := map[string]int{"a": 1, "b": 1, "c": 1}; !reflect.DeepEqual(lowered.Packages, expected) {
+		t.Errorf("not equal\nexpected: %v\nactual: %v", expected, lowered.Packages)
+	}
+
+	if len(increases) != 0 {
+		t.Errorf("unexpected increases %v", increases)
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, input := range []string{`{`, `{"schema_version": 2, "packages": {}}`} {
+		if _, err := ratchet.Read(bytes.NewBufferString(input)); err == nil {
+			t.Errorf("expected error for %s", input)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package godox
+
+import (
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"strings"
+)
+
+// commentSyntax describes the comments of a language read by the raw scanner.
+type commentSyntax struct {
+	// line are the markers of the comments ending at the end of the line, e.g. #.
+	line []string
+	// block are the pairs of the markers starting and ending the block comments, e.g. /* and */.
+	block [][2]string
+}
+
+var (
+	hashSyntax   = commentSyntax{line: []string{"#"}}
+	cSyntax      = commentSyntax{line: []string{"//"}, block: [][2]string{{"/*", "*/"}}}
+	sqlSyntax    = commentSyntax{line: []string{"--"}, block: [][2]string{{"/*", "*/"}}}
+	markupSyntax = commentSyntax{block: [][2]string{{"<!--", "-->"}}}
+
+	// defaultSyntax is the comment syntax of the files not in the commentSyntaxes.
+	defaultSyntax = commentSyntax{line: []string{"#", "//"}, block: [][2]string{{"/*", "*/"}}}
+)
+
+// commentSyntaxes are the comment syntaxes by the extensions or the names of the files.
+var commentSyntaxes = map[string]commentSyntax{
+	".bash":      hashSyntax,
+	".cfg":       hashSyntax,
+	".conf":      hashSyntax,
+	".mk":        hashSyntax,
+	".pl":        hashSyntax,
+	".py":        hashSyntax,
+	".rb":        hashSyntax,
+	".sh":        hashSyntax,
+	".tf":        hashSyntax,
+	".toml":      hashSyntax,
+	".yaml":      hashSyntax,
+	".yml":       hashSyntax,
+	"Dockerfile": hashSyntax,
+	"Makefile":   hashSyntax,
+	".c":         cSyntax,
+	".cc":        cSyntax,
+	".cpp":       cSyntax,
+	".cs":        cSyntax,
+	".css":       cSyntax,
+	".h":         cSyntax,
+	".hpp":       cSyntax,
+	".java":      cSyntax,
+	".js":        cSyntax,
+	".jsx":       cSyntax,
+	".kt":        cSyntax,
+	".proto":     cSyntax,
+	".rs":        cSyntax,
+	".scala":     cSyntax,
+	".scss":      cSyntax,
+	".swift":     cSyntax,
+	".ts":        cSyntax,
+	".tsx":       cSyntax,
+	".lua":       {line: []string{"--"}},
+	".sql":       sqlSyntax,
+	".htm":       markupSyntax,
+	".html":      markupSyntax,
+	".md":        markupSyntax,
+	".svg":       markupSyntax,
+	".xml":       markupSyntax,
+}
+
+// rawMarkers are the markers of the comments found by the raw scanner other than the Go comment markers,
+// with the markers ending the block comments.
+var rawMarkers = [][2]string{{"<!--", "-->"}, {"#", ""}, {"--", ""}}
+
+// isGoSource reports whether the file is a Go source file, read by the engine of the settings.
+func isGoSource(filename string) bool {
+	return filepath.Ext(filename) == ".go"
+}
+
+// fileSyntax returns the comment syntax of the file by its name or extension.
+func fileSyntax(filename string) commentSyntax {
+	base := filepath.Base(filename)
+	if syntax, ok := commentSyntaxes[base]; ok {
+		return syntax
+	}
+
+	if syntax, ok := commentSyntaxes[filepath.Ext(base)]; ok {
+		return syntax
+	}
+
+	return defaultSyntax
+}
+
+// rawComments returns file containing only the comments of the source of other than Go file, found using
+// the comment syntax of the file without tokenizing it, so markers in string literals are read as comments too.
+// Line comments on consecutive lines, not following any code, are grouped together.
+func rawComments(fset *token.FileSet, filename string, src []byte) *ast.File {
+	var (
+		f       ast.File
+		syntax  = fileSyntax(filename)
+		text    = string(src)
+		tf      = fset.AddFile(filename, -1, len(src))
+		endline = -1
+		group   bool
+	)
+
+	tf.SetLinesForContent(src)
+
+	for i := 0; i < len(text); {
+		end, line := commentEnd(text, i, syntax)
+		if end < 0 {
+			i++
+			continue
+		}
+
+		c := &ast.Comment{Slash: tf.Pos(i), Text: text[i:end]}
+
+		// only white space precedes the comment on its line
+		first := strings.TrimSpace(text[strings.LastIndexByte(text[:i], '\n')+1:i]) == ""
+
+		if n := len(f.Comments); group && line && first && tf.Line(c.Slash) == endline+1 {
+			f.Comments[n-1].List = append(f.Comments[n-1].List, c)
+		} else {
+			f.Comments = append(f.Comments, &ast.CommentGroup{List: []*ast.Comment{c}})
+		}
+
+		group = line && first
+		endline = tf.Line(tf.Pos(end - 1))
+		i = end
+	}
+
+	return &f
+}
+
+// commentEnd returns the end of the comment of the syntax starting at the index, -1 if no comment starts there,
+// and whether it is a line comment. Block comments which aren't closed end at the end of the text.
+func commentEnd(text string, start int, syntax commentSyntax) (int, bool) {
+	rest := text[start:]
+
+	for _, block := range syntax.block {
+		if !strings.HasPrefix(rest, block[0]) {
+			continue
+		}
+
+		end := strings.Index(rest[len(block[0]):], block[1])
+		if end < 0 {
+			return len(text), false
+		}
+
+		return start + len(block[0]) + end + len(block[1]), false
+	}
+
+	for _, marker := range syntax.line {
+		if !strings.HasPrefix(rest, marker) {
+			continue
+		}
+
+		end := strings.IndexByte(rest, '\n')
+		if end < 0 {
+			return len(text), true
+		}
+
+		// the carriage returns are not part of the comments, like in Go
+		return start + len(strings.TrimRight(rest[:end], "\r")), true
+	}
+
+	return -1, false
+}
@@ -0,0 +1,48 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/matoous/godox"
+)
+
+var (
+	azureDataEscaper     = strings.NewReplacer("%", "%AZP25", "\r", "%0D", "\n", "%0A")
+	azurePropertyEscaper = strings.NewReplacer("%", "%AZP25", "\r", "%0D", "\n", "%0A", "]", "%5D", ";", "%3B")
+)
+
+// Azure writes messages as Azure Pipelines logging commands so they are shown in the run summary
+// and as pull request annotations. Suppressed messages are left out.
+func Azure(w io.Writer, messages []godox.Message) error {
+	for _, m := range messages {
+		if m.Suppressed {
+			continue
+		}
+
+		_, err := fmt.Fprintf(w, "##vso[task.logissue type=%s;sourcepath=%s;linenumber=%d;columnnumber=%d;code=%s]%s\n",
+			azureType(m.Severity),
+			azurePropertyEscaper.Replace(filepath.ToSlash(filepath.Clean(m.Pos.Filename))),
+			m.Line,
+			m.Column,
+			azurePropertyEscaper.Replace("godox."+m.RuleID),
+			azureDataEscaper.Replace(m.Description()),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// azureType returns the issue type, Azure Pipelines supports only errors and warnings.
+func azureType(s godox.Severity) string {
+	if s == godox.SeverityError {
+		return "error"
+	}
+
+	return "warning"
+}
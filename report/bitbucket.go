@@ -0,0 +1,251 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/matoous/godox"
+)
+
+// Bitbucket Code Insights defaults.
+const (
+	// DefaultBitbucketAPIURL is used with credentials, requests without them go through the Bitbucket Pipelines proxy.
+	DefaultBitbucketAPIURL = "https://api.bitbucket.org/2.0"
+	// DefaultBitbucketReportID identifies the report of the commit, reports are replaced on every run.
+	DefaultBitbucketReportID = "godox"
+
+	bitbucketPipelinesAPIURL = "http://api.bitbucket.org/2.0"
+	bitbucketPipelinesProxy  = "http://localhost:29418"
+
+	// Bitbucket accepts up to 1000 annotations per report, in batches of 100.
+	bitbucketMaxAnnotations = 1000
+	bitbucketBatchSize      = 100
+)
+
+// BitbucketReporter creates a Bitbucket Code Insights report with annotations of the findings for a commit,
+// so they are shown in the pull requests, and writes the messages in the text format. Empty fields are read
+// from the environment variables:
+//
+//	BITBUCKET_WORKSPACE, BITBUCKET_REPO_SLUG, BITBUCKET_COMMIT   repository and commit, set in Bitbucket Pipelines
+//	BITBUCKET_API_URL                                            API URL, DefaultBitbucketAPIURL by default
+//	BITBUCKET_ACCESS_TOKEN                                       access token
+//	BITBUCKET_USERNAME, BITBUCKET_APP_PASSWORD                   app password, used without access token
+//
+// Without credentials the requests are sent through the Bitbucket Pipelines authentication proxy.
+type BitbucketReporter struct {
+	APIURL      string
+	Workspace   string
+	Repository  string
+	Commit      string
+	ReportID    string
+	Token       string
+	Username    string
+	AppPassword string
+	// Client sends the requests, http.DefaultClient or the Pipelines proxy client is used if it is nil.
+	Client *http.Client
+}
+
+type bitbucketReport struct {
+	Title      string          `json:"title"`
+	Details    string          `json:"details"`
+	ReportType string          `json:"report_type"`
+	Reporter   string          `json:"reporter"`
+	Link       string          `json:"link"`
+	Result     string          `json:"result"`
+	Data       []bitbucketData `json:"data"`
+}
+
+type bitbucketData struct {
+	Title string `json:"title"`
+	Type  string `json:"type"`
+	Value int    `json:"value"`
+}
+
+type bitbucketAnnotation struct {
+	ExternalID     string `json:"external_id"`
+	AnnotationType string `json:"annotation_type"`
+	Summary        string `json:"summary"`
+	Path           string `json:"path"`
+	Line           int    `json:"line"`
+	Severity       string `json:"severity"`
+}
+
+// Report implements Reporter, suppressed messages are left out of the report.
+func (r BitbucketReporter) Report(w io.Writer, messages []godox.Message) error {
+	r = r.fromEnv()
+
+	if r.Workspace == "" || r.Repository == "" || r.Commit == "" {
+		return fmt.Errorf("bitbucket: workspace, repository and commit are required, " +
+			"set BITBUCKET_WORKSPACE, BITBUCKET_REPO_SLUG and BITBUCKET_COMMIT")
+	}
+
+	var annotations []bitbucketAnnotation
+
+	for _, m := range messages {
+		if m.Suppressed {
+			continue
+		}
+
+		annotations = append(annotations, bitbucketAnnotation{
+			ExternalID:     fmt.Sprintf("%s-%d", r.ReportID, len(annotations)+1),
+			AnnotationType: "CODE_SMELL",
+			Summary:        m.Description(),
+			Path:           filepath.ToSlash(filepath.Clean(m.Pos.Filename)),
+			Line:           m.Line,
+			Severity:       bitbucketSeverity(m.Severity),
+		})
+	}
+
+	report := bitbucketReport{
+		Title:      "godox",
+		Details:    fmt.Sprintf("%d comments containing TODO/BUG/FIXME", len(annotations)),
+		ReportType: "BUG",
+		Reporter:   "godox",
+		Link:       "https://github.com/matoous/godox",
+		Result:     "PASSED",
+		Data:       []bitbucketData{{Title: "Findings", Type: "NUMBER", Value: len(annotations)}},
+	}
+
+	if len(annotations) > 0 {
+		report.Result = "FAILED"
+	}
+
+	if len(annotations) > bitbucketMaxAnnotations {
+		report.Details += fmt.Sprintf(", the first %d are annotated", bitbucketMaxAnnotations)
+		annotations = annotations[:bitbucketMaxAnnotations]
+	}
+
+	endpoint := fmt.Sprintf("%s/repositories/%s/%s/commit/%s/reports/%s", strings.TrimSuffix(r.APIURL, "/"),
+		url.PathEscape(r.Workspace), url.PathEscape(r.Repository), url.PathEscape(r.Commit), url.PathEscape(r.ReportID))
+
+	// the annotations of the previous report of the commit are deleted with it
+	if err := r.send(http.MethodDelete, endpoint, nil, http.StatusNotFound); err != nil {
+		return err
+	}
+
+	if err := r.send(http.MethodPut, endpoint, report); err != nil {
+		return err
+	}
+
+	for start := 0; start < len(annotations); start += bitbucketBatchSize {
+		end := start + bitbucketBatchSize
+		if end > len(annotations) {
+			end = len(annotations)
+		}
+
+		if err := r.send(http.MethodPost, endpoint+"/annotations", annotations[start:end]); err != nil {
+			return err
+		}
+	}
+
+	return Text(w, messages)
+}
+
+// fromEnv returns the reporter with empty fields set from the environment variables.
+func (r BitbucketReporter) fromEnv() BitbucketReporter {
+	set := func(field *string, name, fallback string) {
+		if *field == "" {
+			*field = os.Getenv(name)
+		}
+
+		if *field == "" {
+			*field = fallback
+		}
+	}
+
+	set(&r.Workspace, "BITBUCKET_WORKSPACE", "")
+	set(&r.Repository, "BITBUCKET_REPO_SLUG", "")
+	set(&r.Commit, "BITBUCKET_COMMIT", "")
+	set(&r.ReportID, "BITBUCKET_REPORT_ID", DefaultBitbucketReportID)
+	set(&r.Token, "BITBUCKET_ACCESS_TOKEN", "")
+	set(&r.Username, "BITBUCKET_USERNAME", "")
+	set(&r.AppPassword, "BITBUCKET_APP_PASSWORD", "")
+
+	proxied := r.Token == "" && r.Username == ""
+	if proxied {
+		set(&r.APIURL, "BITBUCKET_API_URL", bitbucketPipelinesAPIURL)
+	} else {
+		set(&r.APIURL, "BITBUCKET_API_URL", DefaultBitbucketAPIURL)
+	}
+
+	if r.Client == nil {
+		r.Client = http.DefaultClient
+
+		if proxied {
+			proxy, _ := url.Parse(bitbucketPipelinesProxy)
+			r.Client = &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxy)}}
+		}
+	}
+
+	return r
+}
+
+// send sends the JSON body, responses other than 2xx and the accepted statuses are returned as errors.
+func (r BitbucketReporter) send(method, endpoint string, body interface{}, accepted ...int) error {
+	var data io.Reader
+
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+
+		data = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), method, endpoint, data)
+	if err != nil {
+		return err
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	switch {
+	case r.Token != "":
+		req.Header.Set("Authorization", "Bearer "+r.Token)
+	case r.Username != "":
+		req.SetBasicAuth(r.Username, r.AppPassword)
+	}
+
+	resp, err := r.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("bitbucket: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 == 2 {
+		return nil
+	}
+
+	for _, status := range accepted {
+		if resp.StatusCode == status {
+			return nil
+		}
+	}
+
+	msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1<<10))
+
+	return fmt.Errorf("bitbucket: %s %s: %s: %s", method, endpoint, resp.Status, bytes.TrimSpace(msg))
+}
+
+func bitbucketSeverity(s godox.Severity) string {
+	switch s {
+	case godox.SeverityError:
+		return "HIGH"
+	case godox.SeverityInfo:
+		return "LOW"
+	default:
+		return "MEDIUM"
+	}
+}
@@ -0,0 +1,82 @@
+package report
+
+import (
+	"encoding/xml"
+	"io"
+	"path/filepath"
+
+	"github.com/matoous/godox"
+)
+
+const checkstyleVersion = "5.0"
+
+// CheckstyleReport is the root element of the checkstyle XML report.
+type CheckstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []CheckstyleFile `xml:"file"`
+}
+
+// CheckstyleFile contains errors found in a file.
+type CheckstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []CheckstyleError `xml:"error"`
+}
+
+// CheckstyleError is a single finding.
+type CheckstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// NewCheckstyleReport converts messages to a checkstyle report, suppressed messages are left out.
+func NewCheckstyleReport(messages []godox.Message) CheckstyleReport {
+	r := CheckstyleReport{Version: checkstyleVersion}
+	index := make(map[string]int)
+
+	for _, m := range messages {
+		if m.Suppressed {
+			continue
+		}
+
+		name := filepath.ToSlash(filepath.Clean(m.Pos.Filename))
+
+		i, ok := index[name]
+		if !ok {
+			i = len(r.Files)
+			index[name] = i
+			r.Files = append(r.Files, CheckstyleFile{Name: name})
+		}
+
+		r.Files[i].Errors = append(r.Files[i].Errors, CheckstyleError{
+			Line:     m.Line,
+			Column:   m.Column,
+			Severity: string(m.Severity),
+			Message:  m.Description(),
+			Source:   "godox." + m.RuleID,
+		})
+	}
+
+	return r
+}
+
+// Checkstyle writes messages as a checkstyle XML report.
+func Checkstyle(w io.Writer, messages []godox.Message) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(NewCheckstyleReport(messages)); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
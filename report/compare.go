@@ -0,0 +1,173 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+
+	"github.com/matoous/godox"
+)
+
+// Comparison is the difference between the findings of two runs, e.g. the JSON reports of two sprints.
+// Findings are matched by their fingerprints, so findings moved to other lines are unchanged.
+type Comparison struct {
+	// Old and New are the numbers of findings of the runs.
+	Old int `json:"old"`
+	New int `json:"new"`
+	// Added are the findings of the new run missing in the old one, Removed the other way around.
+	Added   []godox.Message `json:"added"`
+	Removed []godox.Message `json:"removed"`
+	// Unchanged is the number of findings of both runs.
+	Unchanged int `json:"unchanged"`
+	// Keywords are the numbers of findings per canonical keyword, sorted by the keywords.
+	Keywords []KeywordDelta `json:"keywords"`
+}
+
+// KeywordDelta is the number of findings with the keyword in both runs.
+type KeywordDelta struct {
+	Keyword string `json:"keyword"`
+	Old     int    `json:"old"`
+	New     int    `json:"new"`
+	Delta   int    `json:"delta"`
+}
+
+// Compare compares the findings of the run before and after, suppressed messages are left out.
+func Compare(before, after []godox.Message) *Comparison {
+	oldStats, newStats := godox.Stats(before), godox.Stats(after)
+
+	c := &Comparison{
+		Old:      oldStats.Total,
+		New:      newStats.Total,
+		Added:    []godox.Message{},
+		Removed:  []godox.Message{},
+		Keywords: []KeywordDelta{},
+	}
+
+	// remaining are the numbers of the old findings not matched by the new ones, by fingerprints
+	remaining := make(map[string]int)
+
+	for _, m := range before {
+		if !m.Suppressed {
+			remaining[m.Fingerprint()]++
+		}
+	}
+
+	for _, m := range after {
+		if m.Suppressed {
+			continue
+		}
+
+		fp := m.Fingerprint()
+		if remaining[fp] == 0 {
+			c.Added = append(c.Added, m)
+			continue
+		}
+
+		remaining[fp]--
+		c.Unchanged++
+	}
+
+	// the last old findings with the same fingerprint are the removed ones
+	for i := len(before) - 1; i >= 0; i-- {
+		if fp := before[i].Fingerprint(); !before[i].Suppressed && remaining[fp] > 0 {
+			remaining[fp]--
+			c.Removed = append(c.Removed, before[i])
+		}
+	}
+
+	for i, j := 0, len(c.Removed)-1; i < j; i, j = i+1, j-1 {
+		c.Removed[i], c.Removed[j] = c.Removed[j], c.Removed[i]
+	}
+
+	keywords := make(map[string]bool)
+	for k := range oldStats.Keywords {
+		keywords[k] = true
+	}
+
+	for k := range newStats.Keywords {
+		keywords[k] = true
+	}
+
+	for k := range keywords {
+		o, n := oldStats.Keywords[k], newStats.Keywords[k]
+		c.Keywords = append(c.Keywords, KeywordDelta{Keyword: k, Old: o, New: n, Delta: n - o})
+	}
+
+	sort.Slice(c.Keywords, func(i, j int) bool { return c.Keywords[i].Keyword < c.Keywords[j].Keyword })
+
+	return c
+}
+
+// Delta is the change of the number of findings, positive when the debt went up.
+func (c *Comparison) Delta() int {
+	return c.New - c.Old
+}
+
+// Summary returns a sentence describing the change, e.g. "Debt went up by 12 findings".
+func (c *Comparison) Summary() string {
+	switch d := c.Delta(); {
+	case d > 0:
+		return fmt.Sprintf("Debt went up by %d findings", d)
+	case d < 0:
+		return fmt.Sprintf("Debt went down by %d findings", -d)
+	default:
+		return "Debt didn't change"
+	}
+}
+
+// WriteJSON writes the comparison as JSON.
+func (c *Comparison) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(struct {
+		SchemaVersion int `json:"schema_version"`
+		Delta         int `json:"delta"`
+		*Comparison
+	}{SchemaVersion: JSONSchemaVersion, Delta: c.Delta(), Comparison: c})
+}
+
+// WriteMarkdown writes the comparison as Markdown summary with the deltas per keyword followed by
+// collapsible lists of the added and removed findings.
+func (c *Comparison) WriteMarkdown(w io.Writer) error {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "## godox\n\n**%s**: %d → %d findings (%d added, %d removed, %d unchanged)\n\n",
+		c.Summary(), c.Old, c.New, len(c.Added), len(c.Removed), c.Unchanged)
+
+	if len(c.Keywords) > 0 {
+		buf.WriteString("| Keyword | Old | New | Delta |\n|---|---:|---:|---:|\n")
+
+		for _, k := range c.Keywords {
+			fmt.Fprintf(&buf, "| %s | %d | %d | %+d |\n", markdownEscaper.Replace(k.Keyword), k.Old, k.New, k.Delta)
+		}
+
+		buf.WriteString("\n")
+	}
+
+	writeFindings(&buf, "Added findings", c.Added)
+	writeFindings(&buf, "Removed findings", c.Removed)
+
+	_, err := w.Write(buf.Bytes())
+
+	return err
+}
+
+// writeFindings writes collapsible table of the findings, nothing if there are none.
+func writeFindings(buf *bytes.Buffer, summary string, messages []godox.Message) {
+	if len(messages) == 0 {
+		return
+	}
+
+	fmt.Fprintf(buf, "<details>\n<summary>%s</summary>\n\n| Location | Message |\n|---|---|\n", summary)
+
+	for _, m := range messages {
+		location := fmt.Sprintf("%s:%d", filepath.ToSlash(filepath.Clean(m.Pos.Filename)), m.Line)
+		fmt.Fprintf(buf, "| %s | %s |\n", markdownEscaper.Replace(location), markdownEscaper.Replace(m.Description()))
+	}
+
+	buf.WriteString("\n</details>\n\n")
+}
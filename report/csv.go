@@ -0,0 +1,138 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/matoous/godox"
+)
+
+// DefaultCSVColumns are the columns written by the CSVReporter when none are configured.
+var DefaultCSVColumns = []string{"file", "line", "keyword", "owner", "issue", "age", "severity", "text"}
+
+// csvColumns returns the values of the columns, age is the number of days since the finding was introduced.
+var csvColumns = map[string]func(m godox.Message, now time.Time) string{
+	"file":       func(m godox.Message, _ time.Time) string { return filepath.ToSlash(filepath.Clean(m.Pos.Filename)) },
+	"line":       func(m godox.Message, _ time.Time) string { return strconv.Itoa(m.Line) },
+	"column":     func(m godox.Message, _ time.Time) string { return strconv.Itoa(m.Column) },
+	"keyword":    func(m godox.Message, _ time.Time) string { return m.Keyword },
+	"canonical":  func(m godox.Message, _ time.Time) string { return m.Canonical },
+	"severity":   func(m godox.Message, _ time.Time) string { return string(m.Severity) },
+	"rule":       func(m godox.Message, _ time.Time) string { return m.RuleID },
+	"owner":      func(m godox.Message, _ time.Time) string { return m.Owner },
+	"priority":   func(m godox.Message, _ time.Time) string { return m.Priority },
+	"labels":     func(m godox.Message, _ time.Time) string { return strings.Join(m.Labels, " ") },
+	"effort":     func(m godox.Message, _ time.Time) string { return formatHours(m.Effort) },
+	"issue":      func(m godox.Message, _ time.Time) string { return m.Issue },
+	"deadline":   func(m godox.Message, _ time.Time) string { return formatDate(m.Deadline) },
+	"snoozed":    func(m godox.Message, _ time.Time) string { return formatDate(m.Snoozed) },
+	"author":     func(m godox.Message, _ time.Time) string { return m.Author },
+	"introduced": func(m godox.Message, _ time.Time) string { return formatDate(m.Introduced) },
+	"age": func(m godox.Message, now time.Time) string {
+		if m.Introduced.IsZero() {
+			return ""
+		}
+
+		return strconv.Itoa(int(now.Sub(m.Introduced).Hours() / 24))
+	},
+	"declaration": func(m godox.Message, _ time.Time) string { return m.Declaration },
+	"text":        func(m godox.Message, _ time.Time) string { return m.Text },
+	"message":     func(m godox.Message, _ time.Time) string { return m.Description() },
+}
+
+// CSVReporter writes messages as comma or tab separated values with a header row, e.g. for spreadsheets.
+// Suppressed messages are left out.
+type CSVReporter struct {
+	// Comma is the field delimiter, comma is used when zero.
+	Comma rune
+	// Columns are the names of the columns, DefaultCSVColumns are used when empty. The columns are file, line,
+	// column, keyword, canonical, severity, rule, owner, priority, labels separated by spaces, effort in hours, issue,
+	// deadline, snoozed, author, introduced, age in days, declaration, text and message.
+	Columns []string
+	// Now returns the time the ages are computed at, time.Now is used when nil.
+	Now func() time.Time
+}
+
+// CSV writes messages as comma separated values with the default columns.
+func CSV(w io.Writer, messages []godox.Message) error {
+	return CSVReporter{}.Report(w, messages)
+}
+
+func (r CSVReporter) withOptions(opts Options) Reporter {
+	r.Columns = opts.Columns
+	return r
+}
+
+// Report implements Reporter.
+func (r CSVReporter) Report(w io.Writer, messages []godox.Message) error {
+	columns := r.Columns
+	if len(columns) == 0 {
+		columns = DefaultCSVColumns
+	}
+
+	values := make([]func(godox.Message, time.Time) string, len(columns))
+
+	for i, column := range columns {
+		value, ok := csvColumns[strings.ToLower(column)]
+		if !ok {
+			return fmt.Errorf("unknown column %q", column)
+		}
+
+		values[i] = value
+	}
+
+	now := time.Now()
+	if r.Now != nil {
+		now = r.Now()
+	}
+
+	cw := csv.NewWriter(w)
+	if r.Comma != 0 {
+		cw.Comma = r.Comma
+	}
+
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+
+	record := make([]string, len(columns))
+
+	for _, m := range messages {
+		if m.Suppressed {
+			continue
+		}
+
+		for i, value := range values {
+			record[i] = value(m, now)
+		}
+
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format("2006-01-02")
+}
+
+func formatHours(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(int(d / time.Hour))
+}
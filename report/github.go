@@ -0,0 +1,50 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/matoous/godox"
+)
+
+var (
+	githubDataEscaper     = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	githubPropertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+)
+
+// GitHub writes messages as GitHub Actions workflow commands so they are shown as annotations
+// on the pull request diff. Suppressed messages are left out.
+func GitHub(w io.Writer, messages []godox.Message) error {
+	for _, m := range messages {
+		if m.Suppressed {
+			continue
+		}
+
+		_, err := fmt.Fprintf(w, "::%s file=%s,line=%d,col=%d,title=%s::%s\n",
+			githubCommand(m.Severity),
+			githubPropertyEscaper.Replace(filepath.ToSlash(filepath.Clean(m.Pos.Filename))),
+			m.Line,
+			m.Column,
+			githubPropertyEscaper.Replace("godox ("+m.RuleID+")"),
+			githubDataEscaper.Replace(m.Description()),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func githubCommand(s godox.Severity) string {
+	switch s {
+	case godox.SeverityError:
+		return "error"
+	case godox.SeverityInfo:
+		return "notice"
+	default:
+		return "warning"
+	}
+}
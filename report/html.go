@@ -0,0 +1,196 @@
+package report
+
+import (
+	"html/template"
+	"io"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/matoous/godox"
+)
+
+// HTMLReporter writes messages as a self-contained HTML page with the findings grouped by package
+// and file, which can be filtered by keyword and severity.
+type HTMLReporter struct {
+	// SourceURL is the template of links to the source lines, {path}, {line} and {column} are replaced
+	// by the position of the finding, e.g. https://github.com/owner/repo/blob/main/{path}#L{line}.
+	// Lines are not linked if it is empty.
+	SourceURL string
+}
+
+// HTML writes messages as HTML page without links to the source lines.
+func HTML(w io.Writer, messages []godox.Message) error {
+	return HTMLReporter{}.Report(w, messages)
+}
+
+func (r HTMLReporter) withOptions(opts Options) Reporter {
+	r.SourceURL = opts.SourceURL
+	return r
+}
+
+type htmlPackage struct {
+	Name  string
+	Count int
+	Files []*htmlFile
+}
+
+type htmlFile struct {
+	Name     string
+	Findings []htmlFinding
+}
+
+type htmlFinding struct {
+	godox.Message
+
+	URL         string
+	Description string
+}
+
+type htmlReport struct {
+	Total      int
+	Keywords   []string
+	Severities []string
+	Packages   []*htmlPackage
+}
+
+// Report implements Reporter, suppressed messages are left out.
+func (r HTMLReporter) Report(w io.Writer, messages []godox.Message) error {
+	report := htmlReport{Severities: []string{
+		string(godox.SeverityError), string(godox.SeverityWarning), string(godox.SeverityInfo),
+	}}
+
+	packages := make(map[string]*htmlPackage)
+	files := make(map[string]*htmlFile)
+	keywords := make(map[string]struct{})
+
+	for _, m := range messages {
+		if m.Suppressed {
+			continue
+		}
+
+		name := filepath.ToSlash(filepath.Clean(m.Pos.Filename))
+		dir := path.Dir(name)
+
+		pkg, ok := packages[dir]
+		if !ok {
+			pkg = &htmlPackage{Name: dir}
+			packages[dir] = pkg
+			report.Packages = append(report.Packages, pkg)
+		}
+
+		file, ok := files[name]
+		if !ok {
+			file = &htmlFile{Name: name}
+			files[name] = file
+			pkg.Files = append(pkg.Files, file)
+		}
+
+		file.Findings = append(file.Findings, htmlFinding{
+			Message:     m,
+			URL:         SourceURL(r.SourceURL, name, m.Line, m.Column),
+			Description: m.Description(),
+		})
+
+		pkg.Count++
+		report.Total++
+		keywords[keyword(m)] = struct{}{}
+	}
+
+	for kw := range keywords {
+		report.Keywords = append(report.Keywords, kw)
+	}
+
+	sort.Strings(report.Keywords)
+	sort.SliceStable(report.Packages, func(i, j int) bool { return report.Packages[i].Name < report.Packages[j].Name })
+
+	return htmlTemplate.Execute(w, report)
+}
+
+// keyword returns the canonical keyword of the message.
+func keyword(m godox.Message) string {
+	if m.Canonical != "" {
+		return m.Canonical
+	}
+
+	return m.Keyword
+}
+
+// SourceURL returns link to the source line of the slash separated file name using the template,
+// see Options.SourceURL, or empty if there is no template.
+func SourceURL(template, name string, line, column int) string {
+	if template == "" {
+		return ""
+	}
+
+	return strings.NewReplacer(
+		"{path}", name,
+		"{line}", strconv.Itoa(line),
+		"{column}", strconv.Itoa(column),
+	).Replace(template)
+}
+
+var htmlTemplate = template.Must(template.New("html").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>godox report</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292e; }
+h1 { font-size: 1.6em; }
+h2 { font-size: 1.2em; margin-top: 1.5em; }
+h3 { font-size: 1em; font-family: monospace; }
+table { border-collapse: collapse; width: 100%; }
+td, th { border-bottom: 1px solid #e1e4e8; padding: 4px 8px; text-align: left; vertical-align: top; }
+td.line { font-family: monospace; white-space: nowrap; }
+.severity-error { color: #cb2431; }
+.severity-warning { color: #b08800; }
+.severity-info { color: #0366d6; }
+.filters { margin: 1em 0; }
+</style>
+</head>
+<body>
+<h1>godox report</h1>
+<p>{{.Total}} findings in {{len .Packages}} packages.</p>
+<div class="filters">
+<label>Keyword <select id="keyword"><option value="">all</option>{{range .Keywords}}<option>{{.}}</option>{{end}}</select></label>
+<label>Severity <select id="severity"><option value="">all</option>{{range .Severities}}<option>{{.}}</option>{{end}}</select></label>
+</div>
+{{range .Packages}}<section class="package">
+<h2>{{.Name}} ({{.Count}})</h2>
+{{range .Files}}<h3>{{.Name}}</h3>
+<table>
+<tr><th>Line</th><th>Keyword</th><th>Severity</th><th>Rule</th><th>Owner</th><th>Introduced</th><th>Message</th></tr>
+{{range .Findings}}<tr class="finding" data-keyword="{{if .Canonical}}{{.Canonical}}{{else}}{{.Keyword}}{{end}}" data-severity="{{.Severity}}">
+<td class="line">{{if .URL}}<a href="{{.URL}}">{{.Line}}:{{.Column}}</a>{{else}}{{.Line}}:{{.Column}}{{end}}</td>
+<td>{{.Keyword}}</td>
+<td class="severity-{{.Severity}}">{{.Severity}}</td>
+<td>{{.RuleID}}</td>
+<td>{{.Owner}}</td>
+<td>{{if .Author}}{{.Author}}, {{.Introduced.Format "2006-01-02"}}{{end}}</td>
+<td>{{.Description}}</td>
+</tr>
+{{end}}</table>
+{{end}}</section>
+{{end}}<script>
+(function () {
+  var keyword = document.getElementById("keyword");
+  var severity = document.getElementById("severity");
+
+  function filter() {
+    document.querySelectorAll("tr.finding").forEach(function (row) {
+      var visible = (!keyword.value || row.dataset.keyword === keyword.value) &&
+        (!severity.value || row.dataset.severity === severity.value);
+      row.style.display = visible ? "" : "none";
+    });
+  }
+
+  keyword.addEventListener("change", filter);
+  severity.addEventListener("change", filter);
+})();
+</script>
+</body>
+</html>
+`))
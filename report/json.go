@@ -0,0 +1,80 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/matoous/godox"
+)
+
+// JSONSchemaVersion is the version of the JSON output schema.
+// It is incremented whenever a backward incompatible change is made to the output.
+const JSONSchemaVersion = 1
+
+// JSONReport is the JSON output document.
+type JSONReport struct {
+	SchemaVersion int             `json:"schema_version"`
+	Findings      []godox.Message `json:"findings"`
+}
+
+// JSON writes messages as a JSON document.
+func JSON(w io.Writer, messages []godox.Message) error {
+	if messages == nil {
+		messages = []godox.Message{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(JSONReport{
+		SchemaVersion: JSONSchemaVersion,
+		Findings:      messages,
+	})
+}
+
+// ReadJSON reads the messages of the JSON report, or of the JSON Lines written by JSONL.
+func ReadJSON(r io.Reader) ([]godox.Message, error) {
+	dec := json.NewDecoder(r)
+	messages := []godox.Message{}
+
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err == io.EOF {
+			return messages, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("decode findings: %w", err)
+		}
+
+		var probe struct {
+			SchemaVersion *int `json:"schema_version"`
+		}
+
+		if err := json.Unmarshal(raw, &probe); err != nil {
+			return nil, fmt.Errorf("decode findings: %w", err)
+		}
+
+		// the lines of the JSON Lines are the findings themselves
+		if probe.SchemaVersion == nil {
+			var m godox.Message
+			if err := json.Unmarshal(raw, &m); err != nil {
+				return nil, fmt.Errorf("decode finding: %w", err)
+			}
+
+			messages = append(messages, m)
+
+			continue
+		}
+
+		if *probe.SchemaVersion != JSONSchemaVersion {
+			return nil, fmt.Errorf("unsupported JSON report schema version %d", *probe.SchemaVersion)
+		}
+
+		var report JSONReport
+		if err := json.Unmarshal(raw, &report); err != nil {
+			return nil, fmt.Errorf("decode findings: %w", err)
+		}
+
+		messages = append(messages, report.Findings...)
+	}
+}
@@ -0,0 +1,22 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/matoous/godox"
+)
+
+// JSONL writes messages as JSON Lines, one JSON object of the same schema as the findings of the JSON report
+// per line, so the messages can be written as soon as they are found.
+func JSONL(w io.Writer, messages []godox.Message) error {
+	enc := json.NewEncoder(w)
+
+	for _, m := range messages {
+		if err := enc.Encode(m); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+
+	"github.com/matoous/godox"
+)
+
+// JUnitReport is the root element of the JUnit XML report.
+type JUnitReport struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Suites   []JUnitSuite `xml:"testsuite"`
+}
+
+// JUnitSuite contains the findings in a package.
+type JUnitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Cases    []JUnitCase `xml:"testcase"`
+}
+
+// JUnitCase is a single finding, failed or skipped if the finding is suppressed.
+type JUnitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr"`
+	Line      int           `xml:"line,attr"`
+	Failure   *JUnitFailure `xml:"failure"`
+	Skipped   *JUnitSkipped `xml:"skipped"`
+}
+
+// JUnitFailure describes the finding.
+type JUnitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// JUnitSkipped marks suppressed findings.
+type JUnitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// NewJUnitReport converts messages to a JUnit report with a test suite per package and a test case per finding.
+func NewJUnitReport(messages []godox.Message) JUnitReport {
+	r := JUnitReport{Name: "godox"}
+	index := make(map[string]int)
+
+	for _, m := range messages {
+		name := filepath.ToSlash(filepath.Clean(m.Pos.Filename))
+		pkg := path.Dir(name)
+
+		i, ok := index[pkg]
+		if !ok {
+			i = len(r.Suites)
+			index[pkg] = i
+			r.Suites = append(r.Suites, JUnitSuite{Name: pkg})
+		}
+
+		c := JUnitCase{
+			Name:      fmt.Sprintf("%s:%d:%d", name, m.Line, m.Column),
+			ClassName: "godox." + m.RuleID,
+			File:      name,
+			Line:      m.Line,
+		}
+
+		suite := &r.Suites[i]
+		suite.Tests++
+		r.Tests++
+
+		if m.Suppressed {
+			c.Skipped = &JUnitSkipped{Message: "suppressed"}
+			suite.Skipped++
+			r.Skipped++
+		} else {
+			c.Failure = &JUnitFailure{Message: m.Description(), Type: string(m.Severity), Text: m.Text}
+			suite.Failures++
+			r.Failures++
+		}
+
+		suite.Cases = append(suite.Cases, c)
+	}
+
+	return r
+}
+
+// JUnit writes messages as a JUnit XML report, suppressed messages are reported as skipped test cases.
+func JUnit(w io.Writer, messages []godox.Message) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(NewJUnitReport(messages)); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
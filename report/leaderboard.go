@@ -0,0 +1,154 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/matoous/godox"
+)
+
+// Person is the open debt of an owner, or of the author of the findings without owners.
+type Person struct {
+	// Name is the owner or author, empty for the findings without both.
+	Name     string `json:"name"`
+	Findings int    `json:"findings"`
+	// Keywords are the numbers of findings per canonical keyword.
+	Keywords map[string]int `json:"keywords"`
+	// Oldest is the finding introduced first, nil if the introduction of none of the findings is known.
+	Oldest *godox.Message `json:"oldest,omitempty"`
+	// Packages are the sorted, slash separated, directories of the files with the findings.
+	Packages []string `json:"packages"`
+}
+
+// Leaderboard are the people sorted by the number of findings in descending order, the findings without
+// owner and author are last.
+type Leaderboard []Person
+
+// NewLeaderboard groups the findings by the owners parsed from the comments, e.g. alice for TODO(alice),
+// or by the authors from git blame for the findings without owners. Suppressed messages are left out.
+func NewLeaderboard(messages []godox.Message) Leaderboard {
+	var (
+		board    Leaderboard
+		index    = make(map[string]int)
+		packages = make(map[string]map[string]bool)
+	)
+
+	for _, m := range messages {
+		if m.Suppressed {
+			continue
+		}
+
+		name := m.Owner
+		if name == "" {
+			name = m.Author
+		}
+
+		i, ok := index[name]
+		if !ok {
+			i = len(board)
+			index[name] = i
+			packages[name] = make(map[string]bool)
+			board = append(board, Person{Name: name, Keywords: make(map[string]int)})
+		}
+
+		keyword := m.Canonical
+		if keyword == "" {
+			keyword = m.Keyword
+		}
+
+		p := &board[i]
+		p.Findings++
+		p.Keywords[keyword]++
+
+		if !m.Introduced.IsZero() && (p.Oldest == nil || m.Introduced.Before(p.Oldest.Introduced)) {
+			oldest := m
+			p.Oldest = &oldest
+		}
+
+		packages[name][path.Dir(filepath.ToSlash(filepath.Clean(m.Pos.Filename)))] = true
+	}
+
+	for i := range board {
+		board[i].Packages = make([]string, 0, len(packages[board[i].Name]))
+		for pkg := range packages[board[i].Name] {
+			board[i].Packages = append(board[i].Packages, pkg)
+		}
+
+		sort.Strings(board[i].Packages)
+	}
+
+	sort.SliceStable(board, func(i, j int) bool {
+		if (board[i].Name == "") != (board[j].Name == "") {
+			return board[j].Name == ""
+		}
+
+		if board[i].Findings != board[j].Findings {
+			return board[i].Findings > board[j].Findings
+		}
+
+		return board[i].Name < board[j].Name
+	})
+
+	return board
+}
+
+// WriteJSON writes the leaderboard as JSON.
+func (b Leaderboard) WriteJSON(w io.Writer) error {
+	people := []Person(b)
+	if people == nil {
+		people = []Person{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(struct {
+		SchemaVersion int      `json:"schema_version"`
+		People        []Person `json:"people"`
+	}{SchemaVersion: JSONSchemaVersion, People: people})
+}
+
+// WriteMarkdown writes the leaderboard as Markdown table.
+func (b Leaderboard) WriteMarkdown(w io.Writer) error {
+	var buf bytes.Buffer
+
+	buf.WriteString("## godox\n\n")
+
+	if len(b) == 0 {
+		buf.WriteString("No findings.\n")
+	} else {
+		buf.WriteString("| Person | Findings | Keywords | Oldest | Packages |\n|---|---:|---|---|---|\n")
+	}
+
+	for _, p := range b {
+		name := p.Name
+		if name == "" {
+			name = "(none)"
+		}
+
+		keywords := make([]string, 0, len(p.Keywords))
+		for _, k := range sortedByCount(p.Keywords) {
+			keywords = append(keywords, fmt.Sprintf("%s %d", k, p.Keywords[k]))
+		}
+
+		var oldest string
+		if p.Oldest != nil {
+			oldest = fmt.Sprintf("%s %s:%d", p.Oldest.Introduced.Format("2006-01-02"),
+				filepath.ToSlash(filepath.Clean(p.Oldest.Pos.Filename)), p.Oldest.Line)
+		}
+
+		fmt.Fprintf(&buf, "| %s | %d | %s | %s | %s |\n", markdownEscaper.Replace(name), p.Findings,
+			markdownEscaper.Replace(strings.Join(keywords, ", ")), markdownEscaper.Replace(oldest),
+			markdownEscaper.Replace(strings.Join(p.Packages, ", ")))
+	}
+
+	_, err := w.Write(buf.Bytes())
+
+	return err
+}
@@ -0,0 +1,112 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/matoous/godox"
+)
+
+// DefaultMarkdownSize is the maximal size of the Markdown report, the size limit of GitHub comments.
+const DefaultMarkdownSize = 65536
+
+// MarkdownReporter writes messages as a Markdown summary, e.g. for pull request comments. The summary
+// contains number of findings per keyword and package followed by a collapsible list of the findings.
+type MarkdownReporter struct {
+	// SourceURL is the template of links to the source lines, see HTMLReporter.
+	SourceURL string
+	// MaxSize is the maximal size of the report in bytes, findings which wouldn't fit are left out.
+	// DefaultMarkdownSize is used when zero.
+	MaxSize int
+}
+
+// Markdown writes messages as Markdown summary without links to the source lines.
+func Markdown(w io.Writer, messages []godox.Message) error {
+	return MarkdownReporter{}.Report(w, messages)
+}
+
+func (r MarkdownReporter) withOptions(opts Options) Reporter {
+	r.SourceURL = opts.SourceURL
+	return r
+}
+
+var markdownEscaper = strings.NewReplacer("|", `\|`, "\r", "", "\n", " ", "<", "&lt;", ">", "&gt;")
+
+// Report implements Reporter, suppressed messages are left out.
+func (r MarkdownReporter) Report(w io.Writer, messages []godox.Message) error {
+	maxSize := r.MaxSize
+	if maxSize == 0 {
+		maxSize = DefaultMarkdownSize
+	}
+
+	var findings []godox.Message
+
+	for _, m := range messages {
+		if !m.Suppressed {
+			findings = append(findings, m)
+		}
+	}
+
+	stats := godox.Stats(findings)
+
+	var buf bytes.Buffer
+
+	buf.WriteString("## godox\n\n")
+
+	if len(findings) == 0 {
+		buf.WriteString("No findings.\n")
+
+		_, err := w.Write(buf.Bytes())
+
+		return err
+	}
+
+	fmt.Fprintf(&buf, "**%d findings** (%d errors, %d warnings, %d info)\n\n", stats.Total,
+		stats.Severities[godox.SeverityError], stats.Severities[godox.SeverityWarning], stats.Severities[godox.SeverityInfo])
+
+	writeCounts(&buf, "Keyword", stats.Keywords)
+	writeCounts(&buf, "Package", stats.Packages)
+
+	buf.WriteString("<details>\n<summary>Findings</summary>\n\n| Location | Severity | Message |\n|---|---|---|\n")
+
+	const footer = "\n</details>\n"
+
+	for i, m := range findings {
+		name := filepath.ToSlash(filepath.Clean(m.Pos.Filename))
+
+		location := fmt.Sprintf("%s:%d", markdownEscaper.Replace(name), m.Line)
+		if url := SourceURL(r.SourceURL, name, m.Line, m.Column); url != "" {
+			location = fmt.Sprintf("[%s](%s)", location, url)
+		}
+
+		row := fmt.Sprintf("| %s | %s | %s |\n", location, m.Severity, markdownEscaper.Replace(m.Description()))
+		more := fmt.Sprintf("\n_%d more findings are not listed._\n", len(findings)-i)
+
+		if buf.Len()+len(row)+len(more)+len(footer) > maxSize {
+			buf.WriteString(more)
+			break
+		}
+
+		buf.WriteString(row)
+	}
+
+	buf.WriteString(footer)
+
+	_, err := w.Write(buf.Bytes())
+
+	return err
+}
+
+// writeCounts writes table of the counts sorted by the count in descending order.
+func writeCounts(buf *bytes.Buffer, name string, counts map[string]int) {
+	fmt.Fprintf(buf, "| %s | Findings |\n|---|---:|\n", name)
+
+	for _, k := range sortedByCount(counts) {
+		fmt.Fprintf(buf, "| %s | %d |\n", markdownEscaper.Replace(k), counts[k])
+	}
+
+	buf.WriteString("\n")
+}
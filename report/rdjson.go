@@ -0,0 +1,124 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"path/filepath"
+
+	"github.com/matoous/godox"
+)
+
+// RDJSONResult is the root of the Reviewdog Diagnostic Format report,
+// see https://github.com/reviewdog/reviewdog/tree/master/proto/rdf.
+type RDJSONResult struct {
+	Source      RDJSONSource       `json:"source"`
+	Diagnostics []RDJSONDiagnostic `json:"diagnostics"`
+}
+
+// RDJSONSource describes the tool producing the diagnostics.
+type RDJSONSource struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+// RDJSONDiagnostic is a single finding.
+type RDJSONDiagnostic struct {
+	Message     string             `json:"message"`
+	Location    RDJSONLocation     `json:"location"`
+	Severity    string             `json:"severity"`
+	Code        *RDJSONCode        `json:"code,omitempty"`
+	Suggestions []RDJSONSuggestion `json:"suggestions,omitempty"`
+}
+
+// RDJSONLocation is the file and range of the finding.
+type RDJSONLocation struct {
+	Path  string      `json:"path"`
+	Range RDJSONRange `json:"range"`
+}
+
+// RDJSONRange is range of positions, the end is optional.
+type RDJSONRange struct {
+	Start RDJSONPosition  `json:"start"`
+	End   *RDJSONPosition `json:"end,omitempty"`
+}
+
+// RDJSONPosition is a position in a file, the column is in bytes starting at 1.
+type RDJSONPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column,omitempty"`
+}
+
+// RDJSONCode identifies the rule of the finding.
+type RDJSONCode struct {
+	Value string `json:"value"`
+	URL   string `json:"url,omitempty"`
+}
+
+// RDJSONSuggestion is a replacement of the text in the range fixing the finding.
+type RDJSONSuggestion struct {
+	Range RDJSONRange `json:"range"`
+	Text  string      `json:"text"`
+}
+
+// NewRDJSONResult converts messages to a Reviewdog Diagnostic Format report, suppressed messages are left out.
+func NewRDJSONResult(messages []godox.Message) RDJSONResult {
+	r := RDJSONResult{
+		Source:      RDJSONSource{Name: "godox", URL: "https://github.com/matoous/godox"},
+		Diagnostics: []RDJSONDiagnostic{},
+	}
+
+	for _, m := range messages {
+		if m.Suppressed {
+			continue
+		}
+
+		// the range covers the keyword
+		var end *RDJSONPosition
+		if m.End.Column > m.Column {
+			end = &RDJSONPosition{Line: m.Line, Column: m.End.Column}
+		}
+
+		d := RDJSONDiagnostic{
+			Message: m.Description(),
+			Location: RDJSONLocation{
+				Path:  filepath.ToSlash(filepath.Clean(m.Pos.Filename)),
+				Range: RDJSONRange{Start: RDJSONPosition{Line: m.Line, Column: m.Column}, End: end},
+			},
+			Severity: rdjsonSeverity(m.Severity),
+			Code:     &RDJSONCode{Value: m.RuleID},
+		}
+
+		if m.Fix != "" {
+			d.Suggestions = []RDJSONSuggestion{{
+				Range: RDJSONRange{
+					Start: RDJSONPosition{Line: m.Line, Column: m.Column},
+					End:   &RDJSONPosition{Line: m.Line, Column: m.Column + len(m.Text)},
+				},
+				Text: m.Fix,
+			}}
+		}
+
+		r.Diagnostics = append(r.Diagnostics, d)
+	}
+
+	return r
+}
+
+// RDJSON writes messages in the Reviewdog Diagnostic Format, use it with reviewdog -f=rdjson.
+func RDJSON(w io.Writer, messages []godox.Message) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(NewRDJSONResult(messages))
+}
+
+func rdjsonSeverity(s godox.Severity) string {
+	switch s {
+	case godox.SeverityError:
+		return "ERROR"
+	case godox.SeverityInfo:
+		return "INFO"
+	default:
+		return "WARNING"
+	}
+}
@@ -0,0 +1,256 @@
+// Package report provides reporters writing godox messages in various output formats.
+package report
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/matoous/godox"
+)
+
+// Reporter writes messages to the writer in a specific format.
+type Reporter interface {
+	Report(w io.Writer, messages []godox.Message) error
+}
+
+// ReporterFunc is an adapter allowing usage of ordinary functions as reporters.
+type ReporterFunc func(w io.Writer, messages []godox.Message) error
+
+// Report calls f(w, messages).
+func (f ReporterFunc) Report(w io.Writer, messages []godox.Message) error {
+	return f(w, messages)
+}
+
+var reporters = map[string]Reporter{
+	"text":       TextReporter{},
+	"azure":      ReporterFunc(Azure),
+	"bitbucket":  BitbucketReporter{},
+	"checkstyle": ReporterFunc(Checkstyle),
+	"csv":        CSVReporter{},
+	"github":     ReporterFunc(GitHub),
+	"html":       HTMLReporter{},
+	"json":       ReporterFunc(JSON),
+	"jsonl":      ReporterFunc(JSONL),
+	"junit":      ReporterFunc(JUnit),
+	"markdown":   MarkdownReporter{},
+	"rdjson":     ReporterFunc(RDJSON),
+	"sarif":      ReporterFunc(SARIF),
+	"sqlite":     SQLiteReporter{},
+	"teamcity":   ReporterFunc(TeamCity),
+	"top":        TopReporter{},
+	"tsv":        CSVReporter{Comma: '\t'},
+}
+
+// New returns reporter for given format.
+func New(format string) (Reporter, error) {
+	r, ok := reporters[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown format %q, available formats: %s", format, strings.Join(Formats(), ", "))
+	}
+
+	return r, nil
+}
+
+// Options configure the reporters supporting them, other reporters ignore the options.
+type Options struct {
+	// SourceURL is the template of links to the source lines in HTML and Markdown reports, see HTMLReporter.
+	SourceURL string
+	// Columns are the columns of the CSV and TSV reports, see CSVReporter.
+	Columns []string
+	// GroupBy is the property the text output is grouped by, see TextReporter.
+	GroupBy string
+	// Top is the number of files and packages of the top format, see TopReporter.
+	Top int
+}
+
+// configurable is implemented by reporters supporting the options.
+type configurable interface {
+	withOptions(opts Options) Reporter
+}
+
+// NewWithOptions returns reporter for given format configured by the options.
+func NewWithOptions(format string, opts Options) (Reporter, error) {
+	r, err := New(format)
+	if err != nil {
+		return nil, err
+	}
+
+	if c, ok := r.(configurable); ok {
+		r = c.withOptions(opts)
+	}
+
+	return r, nil
+}
+
+// Formats returns sorted list of available formats.
+func Formats() []string {
+	formats := make([]string, 0, len(reporters))
+	for f := range reporters {
+		formats = append(formats, f)
+	}
+
+	sort.Strings(formats)
+
+	return formats
+}
+
+// Groups are the properties the TextReporter groups the messages by.
+var Groups = []string{"file", "package", "keyword", "owner", "issue", "label"}
+
+// groupKeys return the groups of the message, none for messages without the property. A message is listed
+// in all of its groups, e.g. under every label.
+var groupKeys = map[string]func(m godox.Message) []string{
+	"file": func(m godox.Message) []string {
+		return []string{filepath.ToSlash(filepath.Clean(m.Pos.Filename))}
+	},
+	"package": func(m godox.Message) []string {
+		return []string{path.Dir(filepath.ToSlash(filepath.Clean(m.Pos.Filename)))}
+	},
+	"keyword": func(m godox.Message) []string {
+		if m.Canonical != "" {
+			return []string{m.Canonical}
+		}
+
+		return []string{m.Keyword}
+	},
+	"owner": func(m godox.Message) []string { return single(m.Owner) },
+	"issue": func(m godox.Message) []string { return single(m.Issue) },
+	"label": func(m godox.Message) []string { return m.Labels },
+}
+
+// single returns the group, none if it is empty.
+func single(group string) []string {
+	if group == "" {
+		return nil
+	}
+
+	return []string{group}
+}
+
+// TextReporter writes one formatted message per line, suppressed messages are listed after the others.
+type TextReporter struct {
+	// GroupBy is one of the Groups, the messages are listed under the headings of their groups sorted by the groups,
+	// followed by the subtotals of the groups. Messages without the property, e.g. without an owner, are listed last,
+	// messages with multiple labels are listed under each of them.
+	// The messages are not grouped if it is empty.
+	GroupBy string
+}
+
+// Text writes one formatted message per line, suppressed messages are listed after the others.
+func Text(w io.Writer, messages []godox.Message) error {
+	return TextReporter{}.Report(w, messages)
+}
+
+func (r TextReporter) withOptions(opts Options) Reporter {
+	r.GroupBy = opts.GroupBy
+	return r
+}
+
+// Report implements Reporter.
+func (r TextReporter) Report(w io.Writer, messages []godox.Message) error {
+	if r.GroupBy == "" {
+		for _, suppressed := range []bool{false, true} {
+			for _, m := range messages {
+				if m.Suppressed != suppressed {
+					continue
+				}
+
+				if _, err := fmt.Fprintln(w, textLine(m)); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	}
+
+	key, ok := groupKeys[r.GroupBy]
+	if !ok {
+		return fmt.Errorf("unknown group %q, available groups: %s", r.GroupBy, strings.Join(Groups, ", "))
+	}
+
+	var (
+		groups     = make(map[string][]godox.Message)
+		names      []string
+		suppressed []godox.Message
+	)
+
+	for _, m := range messages {
+		if m.Suppressed {
+			suppressed = append(suppressed, m)
+			continue
+		}
+
+		keys := key(m)
+		if len(keys) == 0 {
+			keys = []string{""}
+		}
+
+		for _, k := range keys {
+			if _, ok := groups[k]; !ok {
+				names = append(names, k)
+			}
+
+			groups[k] = append(groups[k], m)
+		}
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		if names[i] == "" || names[j] == "" {
+			return names[j] == ""
+		}
+
+		return names[i] < names[j]
+	})
+
+	var b strings.Builder
+
+	for _, name := range names {
+		heading := name
+		if heading == "" {
+			heading = "(no " + r.GroupBy + ")"
+		}
+
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+
+		fmt.Fprintf(&b, "%s\n", heading)
+
+		for _, m := range groups[name] {
+			fmt.Fprintf(&b, "  %s\n", textLine(m))
+		}
+
+		fmt.Fprintf(&b, "  %d findings\n", len(groups[name]))
+	}
+
+	for i, m := range suppressed {
+		if i == 0 && b.Len() > 0 {
+			b.WriteString("\n")
+		}
+
+		fmt.Fprintf(&b, "%s\n", textLine(m))
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// textLine returns the formatted message with the author and the suppression.
+func textLine(m godox.Message) string {
+	line := m.String()
+	if m.Author != "" {
+		line += fmt.Sprintf(" (%s, %s)", m.Author, m.Introduced.Format("2006-01-02"))
+	}
+
+	if m.Suppressed {
+		line += " (suppressed)"
+	}
+
+	return line
+}
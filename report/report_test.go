@@ -0,0 +1,984 @@
+package report_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matoous/godox"
+	"github.com/matoous/godox/config"
+	"github.com/matoous/godox/report"
+)
+
+const src = `package main
+
+// TODO: first thing
+func main() {
+	// FIXME: second thing
+}
+`
+
+func messages(t *testing.T) []godox.Message {
+	t.Helper()
+
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, "pkg/main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return godox.Run(f, fset, &config.GoDoxSettings{})
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	for _, format := range report.Formats() {
+		if _, err := report.New(format); err != nil {
+			t.Errorf("format %q: %v", format, err)
+		}
+	}
+
+	if _, err := report.New("unknown"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestText(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := report.Text(&buf, messages(t)); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `pkg/main.go:3: Line contains TODO/BUG/FIXME: "TODO: first thing"
+pkg/main.go:5: Line contains TODO/BUG/FIXME: "FIXME: second thing"
+`
+	if buf.String() != expected {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expected, buf.String())
+	}
+
+	annotated := messages(t)[:1]
+	annotated[0].Author = "Alice"
+	annotated[0].Introduced = time.Date(2020, 9, 13, 12, 26, 40, 0, time.UTC)
+
+	buf.Reset()
+
+	if err := report.Text(&buf, annotated); err != nil {
+		t.Fatal(err)
+	}
+
+	expected = `pkg/main.go:3: Line contains TODO/BUG/FIXME: "TODO: first thing" (Alice, 2020-09-13)
+`
+	if buf.String() != expected {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expected, buf.String())
+	}
+}
+
+func TestTextGroupBy(t *testing.T) {
+	t.Parallel()
+
+	found := messages(t)
+	found[1].Owner = "alice"
+	suppressed := found[0]
+	suppressed.Suppressed = true
+
+	var buf bytes.Buffer
+	if err := (report.TextReporter{GroupBy: "owner"}).Report(&buf, append(found, suppressed)); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `alice
+  pkg/main.go:5: Line contains TODO/BUG/FIXME: "FIXME: second thing"
+  1 findings
+
+(no owner)
+  pkg/main.go:3: Line contains TODO/BUG/FIXME: "TODO: first thing"
+  1 findings
+
+pkg/main.go:3: Line contains TODO/BUG/FIXME: "TODO: first thing" (suppressed)
+`
+	if buf.String() != expected {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expected, buf.String())
+	}
+
+	found[0].Labels = []string{"perf", "v2"}
+	found[1].Labels = []string{"perf"}
+
+	buf.Reset()
+
+	if err := (report.TextReporter{GroupBy: "label"}).Report(&buf, found); err != nil {
+		t.Fatal(err)
+	}
+
+	expected = `perf
+  pkg/main.go:3: Line contains TODO/BUG/FIXME: "TODO: first thing"
+  pkg/main.go:5: Line contains TODO/BUG/FIXME: "FIXME: second thing"
+  2 findings
+
+v2
+  pkg/main.go:3: Line contains TODO/BUG/FIXME: "TODO: first thing"
+  1 findings
+`
+	if buf.String() != expected {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expected, buf.String())
+	}
+
+	if err := (report.TextReporter{GroupBy: "author"}).Report(&buf, found); err == nil {
+		t.Error("expected error for unknown group")
+	}
+}
+
+func TestSnoozed(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	found := messages(t)
+	found = append(found, found[0])
+	found[0].Snoozed = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	found[0].Owner = "alice"
+	found[1].Snoozed = time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	found[2].Snoozed = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	if err := report.Snoozed(&buf, found, now); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `2024-02-01  pkg/main.go:5: Line contains TODO/BUG/FIXME: "FIXME: second thing"
+2024-03-01  pkg/main.go:3: Line contains TODO/BUG/FIXME: "TODO: first thing" (alice)
+`
+	if buf.String() != expected {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expected, buf.String())
+	}
+}
+
+func TestTop(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	found := messages(t)
+	found = append(found, found[0], found[0])
+	found[2].Pos.Filename = "pkg/other.go"
+	found[3].Pos.Filename = "cmd/main.go"
+	found[0].Introduced = now.Add(-30 * 24 * time.Hour)
+	found[3].Introduced = now.Add(-10 * 24 * time.Hour)
+
+	var buf bytes.Buffer
+	if err := (report.TopReporter{N: 2, Now: func() time.Time { return now }}).Report(&buf, found); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `Files by findings:
+  pkg/main.go  2
+  cmd/main.go  1
+
+Packages by findings:
+  pkg  3
+  cmd  1
+
+Files by age of findings in days:
+  pkg/main.go  30
+  cmd/main.go  10
+
+Packages by age of findings in days:
+  pkg  30
+  cmd  10
+`
+	if buf.String() != expected {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expected, buf.String())
+	}
+}
+
+func TestLeaderboard(t *testing.T) {
+	t.Parallel()
+
+	found := messages(t)
+	found = append(found, found[0], found[1])
+	found[0].Owner = "alice"
+	found[1].Author, found[1].Introduced = "bob", time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	found[2].Author, found[2].Introduced = "bob", time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	found[2].Pos.Filename = "cmd/main.go"
+
+	board := report.NewLeaderboard(found)
+
+	if len(board) != 3 || board[0].Name != "bob" || board[1].Name != "alice" || board[2].Name != "" {
+		t.Fatalf("unexpected leaderboard %+v", board)
+	}
+
+	if board[0].Oldest == nil || board[0].Oldest.Line != found[2].Line || !reflect.DeepEqual(board[0].Packages, []string{"cmd", "pkg"}) {
+		t.Errorf("unexpected person %+v", board[0])
+	}
+
+	var buf bytes.Buffer
+	if err := board.WriteMarkdown(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `## godox
+
+| Person | Findings | Keywords | Oldest | Packages |
+|---|---:|---|---|---|
+| bob | 2 | FIXME 1, TODO 1 | 2020-01-01 cmd/main.go:3 | cmd, pkg |
+| alice | 1 | TODO 1 |  | pkg |
+| (none) | 1 | FIXME 1 |  | pkg |
+`
+	if buf.String() != expected {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expected, buf.String())
+	}
+}
+
+func TestSARIF(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := report.SARIF(&buf, messages(t)); err != nil {
+		t.Fatal(err)
+	}
+
+	var log report.SARIFLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatal(err)
+	}
+
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("unexpected log %+v", log)
+	}
+
+	results := log.Runs[0].Results
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+
+	r := results[1]
+	loc := r.Locations[0].PhysicalLocation
+
+	if loc.ArtifactLocation.URI != "pkg/main.go" || loc.ArtifactLocation.URIBaseID != "%SRCROOT%" {
+		t.Errorf("unexpected artifact location %+v", loc.ArtifactLocation)
+	}
+
+	if loc.Region.StartLine != 5 || loc.Region.StartColumn != 5 || loc.Region.EndColumn != 10 {
+		t.Errorf("unexpected region %+v", loc.Region)
+	}
+
+	if r.RuleID != godox.RuleKeyword || log.Runs[0].Tool.Driver.Rules[r.RuleIndex].ID != r.RuleID {
+		t.Errorf("unexpected rule %q (index %d)", r.RuleID, r.RuleIndex)
+	}
+
+	if r.Level != "warning" {
+		t.Errorf("unexpected level %q", r.Level)
+	}
+
+	if r.Message.Text != `Line contains TODO/BUG/FIXME: "FIXME: second thing"` {
+		t.Errorf("unexpected message %q", r.Message.Text)
+	}
+
+	if r.PartialFingerprints["godox/v1"] == "" || r.PartialFingerprints["godox/v1"] == results[0].PartialFingerprints["godox/v1"] {
+		t.Errorf("unexpected fingerprints %v", r.PartialFingerprints)
+	}
+}
+
+func TestSARIFRules(t *testing.T) {
+	t.Parallel()
+
+	ids := []string{
+		godox.RuleKeyword, godox.RuleFormat, godox.RuleIssue, godox.RuleDeadline, godox.RuleOwner,
+		godox.RuleUnknownOwner, godox.RuleClosedIssue, godox.RuleMissingIssue, godox.RuleDescriptionLength,
+		godox.RuleForbiddenPattern, godox.RuleForbiddenKeyword, godox.RuleExportedDoc, godox.RuleStringLiteral,
+	}
+
+	rules := report.NewSARIFLog(nil).Runs[0].Tool.Driver.Rules
+	if len(rules) != len(ids) {
+		t.Fatalf("expected %d rules, got %d", len(ids), len(rules))
+	}
+
+	for i, id := range ids {
+		// the rules without a descriptor are described by their IDs
+		if rules[i].ID != id || rules[i].ShortDescription.Text == id {
+			t.Errorf("unexpected rule %+v, expected %q", rules[i], id)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := report.JSON(&buf, messages(t)[:1]); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{
+  "schema_version": 1,
+  "findings": [
+    {
+      "file": "pkg/main.go",
+      "line": 3,
+      "column": 4,
+      "end_column": 8,
+      "keyword": "TODO",
+      "canonical_keyword": "TODO",
+      "rule": "keyword",
+      "severity": "warning",
+      "text": "TODO: first thing",
+      "message": "Line contains TODO/BUG/FIXME: \"TODO: first thing\"",
+      "declaration": "main",
+      "declaration_kind": "func"
+    }
+  ]
+}
+`
+	if buf.String() != expected {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expected, buf.String())
+	}
+
+	buf.Reset()
+
+	if err := report.JSON(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var r report.JSONReport
+	if err := json.Unmarshal(buf.Bytes(), &r); err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Findings == nil || len(r.Findings) != 0 {
+		t.Errorf("expected empty list of findings, got %v", r.Findings)
+	}
+}
+
+func TestJSONL(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := report.JSONL(&buf, messages(t)); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d:\n%s", len(lines), buf.String())
+	}
+
+	for i, text := range []string{"TODO: first thing", "FIXME: second thing"} {
+		var finding map[string]interface{}
+		if err := json.Unmarshal([]byte(lines[i]), &finding); err != nil {
+			t.Fatal(err)
+		}
+
+		if finding["text"] != text || finding["file"] != "pkg/main.go" {
+			t.Errorf("unexpected finding %s", lines[i])
+		}
+	}
+}
+
+func TestReadJSON(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := report.JSON(&buf, messages(t)[:1]); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := report.JSONL(&buf, messages(t)[1:]); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := report.ReadJSON(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(read) != 2 {
+		t.Fatalf("expected 2 findings, got %v", read)
+	}
+
+	for i, m := range messages(t) {
+		if read[i].String() != m.String() || read[i].Fingerprint() != m.Fingerprint() || read[i].Declaration != m.Declaration {
+			t.Errorf("not equal\nexpected: %+v\nactual: %+v", m, read[i])
+		}
+	}
+
+	if _, err := report.ReadJSON(strings.NewReader(`{"schema_version": 2, "findings": []}`)); err == nil {
+		t.Error("expected error for unsupported schema version")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	found := messages(t)
+
+	// the first finding moved to another line and is reported twice
+	moved := found[0]
+	moved.Line += 10
+
+	c := report.Compare(found, []godox.Message{moved, moved})
+
+	if c.Old != 2 || c.New != 2 || c.Unchanged != 1 || c.Delta() != 0 {
+		t.Errorf("unexpected comparison %+v", c)
+	}
+
+	if len(c.Added) != 1 || c.Added[0].Line != 13 || len(c.Removed) != 1 || c.Removed[0].Keyword != "FIXME" {
+		t.Errorf("unexpected findings\nadded: %v\nremoved: %v", c.Added, c.Removed)
+	}
+
+	expected := []report.KeywordDelta{{Keyword: "FIXME", Old: 1, New: 0, Delta: -1}, {Keyword: "TODO", Old: 1, New: 2, Delta: 1}}
+	if !reflect.DeepEqual(c.Keywords, expected) {
+		t.Errorf("not equal\nexpected: %v\nactual: %v", expected, c.Keywords)
+	}
+
+	var buf bytes.Buffer
+	if err := report.Compare(found, found).WriteMarkdown(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedMarkdown := `## godox
+
+**Debt didn't change**: 2 → 2 findings (0 added, 0 removed, 2 unchanged)
+
+| Keyword | Old | New | Delta |
+|---|---:|---:|---:|
+| FIXME | 1 | 1 | +0 |
+| TODO | 1 | 1 | +0 |
+
+`
+	if buf.String() != expectedMarkdown {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expectedMarkdown, buf.String())
+	}
+}
+
+func TestGitHub(t *testing.T) {
+	t.Parallel()
+
+	msgs := messages(t)
+	msgs[1].Severity = godox.SeverityError
+
+	var buf bytes.Buffer
+	if err := report.GitHub(&buf, msgs); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `::warning file=pkg/main.go,line=3,col=4,title=godox (keyword)::Line contains TODO/BUG/FIXME: "TODO: first thing"
+::error file=pkg/main.go,line=5,col=5,title=godox (keyword)::Line contains TODO/BUG/FIXME: "FIXME: second thing"
+`
+	if buf.String() != expected {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expected, buf.String())
+	}
+}
+
+func TestAzure(t *testing.T) {
+	t.Parallel()
+
+	msgs := messages(t)
+	msgs[1].Severity = godox.SeverityError
+	msgs = append(msgs, godox.Message{Line: 7, Pos: token.Position{Filename: "a;b.go"}, RuleID: "keyword", Message: "100% done\n]"})
+
+	var buf bytes.Buffer
+	if err := report.Azure(&buf, msgs); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `##vso[task.logissue type=warning;sourcepath=pkg/main.go;linenumber=3;columnnumber=4;code=godox.keyword]Line contains TODO/BUG/FIXME: "TODO: first thing"
+##vso[task.logissue type=error;sourcepath=pkg/main.go;linenumber=5;columnnumber=5;code=godox.keyword]Line contains TODO/BUG/FIXME: "FIXME: second thing"
+##vso[task.logissue type=warning;sourcepath=a%3Bb.go;linenumber=7;columnnumber=0;code=godox.keyword]100%AZP25 done%0A]
+`
+	if buf.String() != expected {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expected, buf.String())
+	}
+}
+
+func TestTeamCity(t *testing.T) {
+	t.Parallel()
+
+	msgs := messages(t)
+	msgs[0].Severity = godox.SeverityError
+	msgs[1].Canonical = "TODO"
+	msgs = append(msgs, godox.Message{Keyword: "BUG", Line: 7, Pos: token.Position{Filename: "main.go"}, Message: "BUG: it's [broken]"})
+
+	var buf bytes.Buffer
+	if err := report.TeamCity(&buf, msgs); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `##teamcity[inspectionType id='godox.TODO' name='TODO' description='Comments containing TODO' category='godox']
+##teamcity[inspection typeId='godox.TODO' message='Line contains TODO/BUG/FIXME: "TODO: first thing"' file='pkg/main.go' line='3' SEVERITY='ERROR']
+##teamcity[inspection typeId='godox.TODO' message='Line contains TODO/BUG/FIXME: "FIXME: second thing"' file='pkg/main.go' line='5' SEVERITY='WARNING']
+##teamcity[inspectionType id='godox.BUG' name='BUG' description='Comments containing BUG' category='godox']
+##teamcity[inspection typeId='godox.BUG' message='BUG: it|'s |[broken|]' file='main.go' line='7' SEVERITY='WARNING']
+`
+	if buf.String() != expected {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expected, buf.String())
+	}
+}
+
+func TestBitbucket(t *testing.T) {
+	t.Parallel()
+
+	var requests []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+r.Header.Get("Authorization")+" "+string(body))
+
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	msgs := messages(t)
+	msgs[0].Severity = godox.SeverityError
+	msgs[1].Suppressed = true
+
+	r := report.BitbucketReporter{
+		APIURL:     server.URL,
+		Workspace:  "team",
+		Repository: "repo",
+		Commit:     "abc123",
+		ReportID:   "godox",
+		Token:      "secret",
+	}
+
+	var buf bytes.Buffer
+	if err := r.Report(&buf, msgs); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"DELETE /repositories/team/repo/commit/abc123/reports/godox Bearer secret ",
+		"PUT /repositories/team/repo/commit/abc123/reports/godox Bearer secret " +
+			`{"title":"godox","details":"1 comments containing TODO/BUG/FIXME","report_type":"BUG","reporter":"godox",` +
+			`"link":"https://github.com/matoous/godox","result":"FAILED","data":[{"title":"Findings","type":"NUMBER","value":1}]}`,
+		"POST /repositories/team/repo/commit/abc123/reports/godox/annotations Bearer secret " +
+			`[{"external_id":"godox-1","annotation_type":"CODE_SMELL","summary":"Line contains TODO/BUG/FIXME: \"TODO: first thing\"",` +
+			`"path":"pkg/main.go","line":3,"severity":"HIGH"}]`,
+	}
+	if strings.Join(requests, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", strings.Join(expected, "\n"), strings.Join(requests, "\n"))
+	}
+
+	if !strings.HasPrefix(buf.String(), "pkg/main.go:3: ") {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+
+	r.Commit = ""
+
+	if err := r.Report(&buf, msgs); err == nil || !strings.Contains(err.Error(), "BITBUCKET_COMMIT") {
+		t.Errorf("expected error without commit, got %v", err)
+	}
+}
+
+func TestCheckstyle(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := report.Checkstyle(&buf, messages(t)); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<checkstyle version="5.0">
+  <file name="pkg/main.go">
+    <error line="3" column="4" severity="warning" message="Line contains TODO/BUG/FIXME: &#34;TODO: first thing&#34;" source="godox.keyword"></error>
+    <error line="5" column="5" severity="warning" message="Line contains TODO/BUG/FIXME: &#34;FIXME: second thing&#34;" source="godox.keyword"></error>
+  </file>
+</checkstyle>
+`
+	if buf.String() != expected {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expected, buf.String())
+	}
+}
+
+func TestJUnit(t *testing.T) {
+	t.Parallel()
+
+	msgs := messages(t)
+	msgs[1].Suppressed = true
+
+	var buf bytes.Buffer
+	if err := report.JUnit(&buf, msgs); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="godox" tests="2" failures="1" skipped="1">
+  <testsuite name="pkg" tests="2" failures="1" skipped="1">
+    <testcase name="pkg/main.go:3:4" classname="godox.keyword" file="pkg/main.go" line="3">
+      <failure message="Line contains TODO/BUG/FIXME: &#34;TODO: first thing&#34;" type="warning">TODO: first thing</failure>
+    </testcase>
+    <testcase name="pkg/main.go:5:5" classname="godox.keyword" file="pkg/main.go" line="5">
+      <skipped message="suppressed"></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+	if buf.String() != expected {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expected, buf.String())
+	}
+}
+
+func TestRDJSON(t *testing.T) {
+	t.Parallel()
+
+	msgs := messages(t)
+	msgs[0].Severity = godox.SeverityError
+	msgs[1].Suppressed = true
+
+	var buf bytes.Buffer
+	if err := report.RDJSON(&buf, msgs); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{
+  "source": {
+    "name": "godox",
+    "url": "https://github.com/matoous/godox"
+  },
+  "diagnostics": [
+    {
+      "message": "Line contains TODO/BUG/FIXME: \"TODO: first thing\"",
+      "location": {
+        "path": "pkg/main.go",
+        "range": {
+          "start": {
+            "line": 3,
+            "column": 4
+          },
+          "end": {
+            "line": 3,
+            "column": 8
+          }
+        }
+      },
+      "severity": "ERROR",
+      "code": {
+        "value": "keyword"
+      }
+    }
+  ]
+}
+`
+	if buf.String() != expected {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expected, buf.String())
+	}
+
+	msgs[0].Fix = "TODO: First thing"
+
+	suggestions := report.NewRDJSONResult(msgs).Diagnostics[0].Suggestions
+	if len(suggestions) != 1 || suggestions[0].Text != "TODO: First thing" ||
+		suggestions[0].Range.Start.Column != 4 || suggestions[0].Range.End.Column != 21 {
+		t.Errorf("unexpected suggestions: %+v", suggestions)
+	}
+}
+
+func TestHTML(t *testing.T) {
+	t.Parallel()
+
+	msgs := messages(t)
+	msgs[1].Severity = godox.SeverityError
+
+	r, err := report.NewWithOptions("html", report.Options{SourceURL: "https://example.com/{path}?line={line}&col={column}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := r.Report(&buf, msgs); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, part := range []string{
+		"<p>2 findings in 1 packages.</p>",
+		"<option>FIXME</option><option>TODO</option>",
+		"<h2>pkg (2)</h2>",
+		"<h3>pkg/main.go</h3>",
+		`<a href="https://example.com/pkg/main.go?line=5&amp;col=5">5:5</a>`,
+		`<tr class="finding" data-keyword="FIXME" data-severity="error">`,
+		`Line contains TODO/BUG/FIXME: &#34;TODO: first thing&#34;`,
+	} {
+		if !strings.Contains(buf.String(), part) {
+			t.Errorf("expected report to contain %s", part)
+		}
+	}
+
+	buf.Reset()
+
+	if err := report.HTML(&buf, msgs); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(buf.String(), "<a href") {
+		t.Error("expected no links without source URL")
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	t.Parallel()
+
+	msgs := messages(t)
+	msgs[1].Severity = godox.SeverityError
+	msgs = append(msgs, msgs[0])
+	msgs[2].Pos.Filename = "cmd/tool/main.go"
+	msgs[2].Message = "cmd/tool/main.go:3: Line contains TODO/BUG/FIXME: \"TODO: a | b\""
+
+	r, err := report.NewWithOptions("markdown", report.Options{SourceURL: "https://example.com/{path}#L{line}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := r.Report(&buf, msgs); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `## godox
+
+**3 findings** (1 errors, 2 warnings, 0 info)
+
+| Keyword | Findings |
+|---|---:|
+| TODO | 2 |
+| FIXME | 1 |
+
+| Package | Findings |
+|---|---:|
+| pkg | 2 |
+| cmd/tool | 1 |
+
+<details>
+<summary>Findings</summary>
+
+| Location | Severity | Message |
+|---|---|---|
+| [pkg/main.go:3](https://example.com/pkg/main.go#L3) | warning | Line contains TODO/BUG/FIXME: "TODO: first thing" |
+| [pkg/main.go:5](https://example.com/pkg/main.go#L5) | error | Line contains TODO/BUG/FIXME: "FIXME: second thing" |
+| [cmd/tool/main.go:3](https://example.com/cmd/tool/main.go#L3) | warning | Line contains TODO/BUG/FIXME: "TODO: a \| b" |
+
+</details>
+`
+	if buf.String() != expected {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expected, buf.String())
+	}
+
+	buf.Reset()
+
+	// the findings which don't fit are left out
+	if err := (report.MarkdownReporter{MaxSize: 400}).Report(&buf, msgs); err != nil {
+		t.Fatal(err)
+	}
+
+	if buf.Len() > 400 || !strings.Contains(buf.String(), "_2 more findings are not listed._") {
+		t.Errorf("unexpected report of size %d:\n%s", buf.Len(), buf.String())
+	}
+}
+
+func TestCSV(t *testing.T) {
+	t.Parallel()
+
+	annotated := messages(t)
+	annotated[0].Owner = "alice"
+	annotated[0].Introduced = time.Date(2020, 9, 13, 12, 26, 40, 0, time.UTC)
+	annotated[1].Text = `FIXME: "quoted", second`
+
+	now := func() time.Time { return time.Date(2020, 10, 13, 12, 0, 0, 0, time.UTC) }
+
+	var buf bytes.Buffer
+	if err := (report.CSVReporter{Now: now}).Report(&buf, annotated); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `file,line,keyword,owner,issue,age,severity,text
+pkg/main.go,3,TODO,alice,,29,warning,TODO: first thing
+pkg/main.go,5,FIXME,,,,warning,"FIXME: ""quoted"", second"
+`
+	if buf.String() != expected {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expected, buf.String())
+	}
+
+	tsv, err := report.NewWithOptions("tsv", report.Options{Columns: []string{"File", "line", "rule"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf.Reset()
+
+	if err := tsv.Report(&buf, annotated); err != nil {
+		t.Fatal(err)
+	}
+
+	expected = "File\tline\trule\npkg/main.go\t3\tkeyword\npkg/main.go\t5\tkeyword\n"
+	if buf.String() != expected {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expected, buf.String())
+	}
+
+	if err := (report.CSVReporter{Columns: []string{"file", "color"}}).Report(&buf, annotated); err == nil {
+		t.Error("expected error for unknown column")
+	}
+}
+
+func TestSQLite(t *testing.T) {
+	t.Parallel()
+
+	annotated := messages(t)
+	annotated[0].Author = "Alice"
+	annotated[0].Introduced = time.Date(2020, 9, 13, 12, 26, 40, 0, time.UTC)
+	annotated[1].Text = "FIXME: it's second"
+
+	r := report.SQLiteReporter{Now: func() time.Time { return time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC) }}
+
+	var buf bytes.Buffer
+	if err := r.Report(&buf, annotated); err != nil {
+		t.Fatal(err)
+	}
+
+	var inserts []string
+
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if strings.HasPrefix(line, "INSERT") {
+			inserts = append(inserts, line)
+		}
+	}
+
+	expected := []string{
+		"INSERT INTO runs (started_at, findings, suppressed) VALUES ('2021-01-02T03:04:05Z', 2, 0);",
+		"INSERT INTO findings VALUES ((SELECT max(id) FROM runs), 'pkg/main.go', 3, 4, 'TODO', 'TODO', 'keyword', 'warning', " +
+			"'TODO: first thing', NULL, NULL, NULL, NULL, 'main', 'Alice', NULL, '2020-09-13T12:26:40Z', '" +
+			annotated[0].Fingerprint() + "', 0);",
+		"INSERT INTO findings VALUES ((SELECT max(id) FROM runs), 'pkg/main.go', 5, 5, 'FIXME', 'FIXME', 'keyword', 'warning', " +
+			"'FIXME: it''s second', NULL, NULL, NULL, NULL, 'main', NULL, NULL, NULL, '" + annotated[1].Fingerprint() + "', 0);",
+	}
+
+	if strings.Join(inserts, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", strings.Join(expected, "\n"), strings.Join(inserts, "\n"))
+	}
+
+	if !strings.HasPrefix(buf.String(), "BEGIN;\nCREATE TABLE IF NOT EXISTS runs") || !strings.HasSuffix(buf.String(), "COMMIT;\n") {
+		t.Errorf("statements are not in a transaction:\n%s", buf.String())
+	}
+
+	// the database is written by the SQLite shell, replaced by a script saving the statements
+	dir, err := ioutil.TempDir("", "godox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	shell := filepath.Join(dir, "sqlite3")
+	if err := ioutil.WriteFile(shell, []byte("#!/bin/sh\ncat > \"$2\"\n"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	r.Command = shell
+	database := filepath.Join(dir, "debt.db")
+
+	if err := r.ReportFile(database, annotated); err != nil {
+		t.Fatal(err)
+	}
+
+	if data, err := ioutil.ReadFile(database); err != nil || string(data) != buf.String() {
+		t.Errorf("unexpected statements %q: %v", data, err)
+	}
+
+	r.Command = filepath.Join(dir, "nonexistent")
+	if err := r.ReportFile(database, annotated); err == nil {
+		t.Error("expected error for missing shell")
+	}
+}
+
+func TestSummary(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := report.Summary(&buf, godox.Stats(messages(t))); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `Total: 2 (0 errors, 2 warnings, 0 info), 0 suppressed
+
+Keywords:
+  FIXME  1
+  TODO   1
+
+Packages:
+  pkg  2
+
+Files:
+  pkg/main.go  2
+
+Owners:
+  (none)  2
+`
+	if buf.String() != expected {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expected, buf.String())
+	}
+
+	buf.Reset()
+
+	if err := report.SummaryJSON(&buf, godox.Stats(messages(t))); err != nil {
+		t.Fatal(err)
+	}
+
+	var stats godox.Statistics
+	if err := json.Unmarshal(buf.Bytes(), &stats); err != nil {
+		t.Fatal(err)
+	}
+
+	if stats.Total != 2 || stats.Keywords["TODO"] != 1 || stats.Unowned != 2 {
+		t.Errorf("unexpected statistics: %+v", stats)
+	}
+
+	found := messages(t)
+	found[0].Effort = 3 * godox.EffortDay
+	found[1].Effort = 4 * time.Hour
+	found[1].Owner = "alice"
+
+	buf.Reset()
+
+	if err := report.Summary(&buf, godox.Stats(found)); err != nil {
+		t.Fatal(err)
+	}
+
+	expected = `Total: 2 (0 errors, 2 warnings, 0 info), 0 suppressed
+Effort: 3d4h
+
+Keywords:
+  FIXME  1
+  TODO   1
+
+Packages:
+  pkg  2
+
+Files:
+  pkg/main.go  2
+
+Owners:
+  (none)  1
+  alice   1
+
+Effort by packages:
+  pkg  3d4h
+
+Effort by owners:
+  (none)  3d
+  alice   4h
+`
+	if buf.String() != expected {
+		t.Errorf("not equal\nexpected:\n%s\nactual:\n%s", expected, buf.String())
+	}
+}
@@ -0,0 +1,208 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/matoous/godox"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	// sarifSrcRoot is the base URI identifier used for relative file paths.
+	sarifSrcRoot = "%SRCROOT%"
+	// sarifFingerprint is the name of the partial fingerprint godox reports.
+	sarifFingerprint = "godox/v1"
+)
+
+// SARIFLog is the root object of the SARIF 2.1.0 log.
+type SARIFLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+// SARIFRun describes single run of the tool.
+type SARIFRun struct {
+	Tool    SARIFTool     `json:"tool"`
+	Results []SARIFResult `json:"results"`
+}
+
+// SARIFTool describes the tool which produced the results.
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+// SARIFDriver describes the tool component and rules it provides.
+type SARIFDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []SARIFRule `json:"rules"`
+}
+
+// SARIFRule is the rule metadata.
+type SARIFRule struct {
+	ID               string       `json:"id"`
+	ShortDescription SARIFMessage `json:"shortDescription"`
+}
+
+// SARIFMessage is a plain text message.
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+// SARIFResult is a single finding.
+type SARIFResult struct {
+	RuleID              string             `json:"ruleId"`
+	RuleIndex           int                `json:"ruleIndex"`
+	Level               string             `json:"level"`
+	Message             SARIFMessage       `json:"message"`
+	Locations           []SARIFLocation    `json:"locations"`
+	PartialFingerprints map[string]string  `json:"partialFingerprints"`
+	Suppressions        []SARIFSuppression `json:"suppressions,omitempty"`
+}
+
+// SARIFSuppression describes suppression of a result.
+type SARIFSuppression struct {
+	Kind string `json:"kind"`
+}
+
+// SARIFLocation is the location of a finding.
+type SARIFLocation struct {
+	PhysicalLocation SARIFPhysicalLocation `json:"physicalLocation"`
+}
+
+// SARIFPhysicalLocation is a location within a file.
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           SARIFRegion           `json:"region"`
+}
+
+// SARIFArtifactLocation identifies a file.
+type SARIFArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+// SARIFRegion is a region within a file.
+type SARIFRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+var sarifRules = []SARIFRule{
+	{ID: godox.RuleKeyword, ShortDescription: SARIFMessage{Text: "Comment contains a keyword"}},
+	{ID: godox.RuleFormat, ShortDescription: SARIFMessage{Text: "Comment does not match the expected format"}},
+	{ID: godox.RuleIssue, ShortDescription: SARIFMessage{Text: "Comment does not reference an issue"}},
+	{ID: godox.RuleDeadline, ShortDescription: SARIFMessage{Text: "Comment deadline has passed"}},
+	{ID: godox.RuleOwner, ShortDescription: SARIFMessage{Text: "Comment does not specify an owner"}},
+	{ID: godox.RuleUnknownOwner, ShortDescription: SARIFMessage{Text: "Comment owner is not in the roster"}},
+	{ID: godox.RuleClosedIssue, ShortDescription: SARIFMessage{Text: "Comment references a closed issue"}},
+	{ID: godox.RuleMissingIssue, ShortDescription: SARIFMessage{Text: "Comment references an issue which does not exist"}},
+	{ID: godox.RuleDescriptionLength, ShortDescription: SARIFMessage{Text: "Comment description is too short"}},
+	{ID: godox.RuleForbiddenPattern, ShortDescription: SARIFMessage{Text: "Comment matches a forbidden pattern"}},
+	{ID: godox.RuleForbiddenKeyword, ShortDescription: SARIFMessage{Text: "Comment contains a forbidden keyword"}},
+	{ID: godox.RuleExportedDoc, ShortDescription: SARIFMessage{Text: "Doc comment of an exported declaration contains a keyword"}},
+	{ID: godox.RuleStringLiteral, ShortDescription: SARIFMessage{Text: "String literal contains a keyword"}},
+}
+
+// NewSARIFLog converts messages to a SARIF log.
+func NewSARIFLog(messages []godox.Message) SARIFLog {
+	rules := append([]SARIFRule(nil), sarifRules...)
+	index := make(map[string]int, len(rules))
+
+	for i, r := range rules {
+		index[r.ID] = i
+	}
+
+	results := make([]SARIFResult, 0, len(messages))
+
+	for _, m := range messages {
+		i, ok := index[m.RuleID]
+		if !ok {
+			i = len(rules)
+			index[m.RuleID] = i
+			rules = append(rules, SARIFRule{ID: m.RuleID, ShortDescription: SARIFMessage{Text: m.RuleID}})
+		}
+
+		var suppressions []SARIFSuppression
+		if m.Suppressed {
+			suppressions = []SARIFSuppression{{Kind: "inSource"}}
+		}
+
+		results = append(results, SARIFResult{
+			RuleID:    m.RuleID,
+			RuleIndex: i,
+			Level:     sarifLevel(m.Severity),
+			Message:   SARIFMessage{Text: m.Description()},
+			Locations: []SARIFLocation{{
+				PhysicalLocation: SARIFPhysicalLocation{
+					ArtifactLocation: sarifArtifactLocation(m.Pos.Filename),
+					Region: SARIFRegion{
+						StartLine:   m.Line,
+						StartColumn: m.Column,
+						EndColumn:   m.End.Column,
+					},
+				},
+			}},
+			PartialFingerprints: map[string]string{
+				sarifFingerprint: m.Fingerprint(),
+			},
+			Suppressions: suppressions,
+		})
+	}
+
+	return SARIFLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs: []SARIFRun{{
+			Tool: SARIFTool{
+				Driver: SARIFDriver{
+					Name:           "godox",
+					InformationURI: "https://github.com/matoous/godox",
+					Rules:          rules,
+				},
+			},
+			Results: results,
+		}},
+	}
+}
+
+// SARIF writes messages as a SARIF 2.1.0 log.
+func SARIF(w io.Writer, messages []godox.Message) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(NewSARIFLog(messages))
+}
+
+func sarifLevel(s godox.Severity) string {
+	switch s {
+	case godox.SeverityError:
+		return "error"
+	case godox.SeverityInfo:
+		return "note"
+	default:
+		return "warning"
+	}
+}
+
+func sarifArtifactLocation(filename string) SARIFArtifactLocation {
+	if filepath.IsAbs(filename) {
+		path := filepath.ToSlash(filepath.Clean(filename))
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+
+		return SARIFArtifactLocation{URI: (&url.URL{Scheme: "file", Path: path}).String()}
+	}
+
+	path := filepath.ToSlash(filepath.Clean(filename))
+
+	return SARIFArtifactLocation{URI: (&url.URL{Path: path}).String(), URIBaseID: sarifSrcRoot}
+}
@@ -0,0 +1,38 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/matoous/godox"
+)
+
+// Snoozed writes the messages snoozed at the time, which are suppressed until their snooze dates, one per line
+// after the date, sorted by the dates, so the snoozed findings don't get forgotten. The other messages are left out.
+func Snoozed(w io.Writer, messages []godox.Message, now time.Time) error {
+	var snoozed []godox.Message
+
+	for _, m := range messages {
+		if m.Snoozed.After(now) {
+			snoozed = append(snoozed, m)
+		}
+	}
+
+	sort.SliceStable(snoozed, func(i, j int) bool { return snoozed[i].Snoozed.Before(snoozed[j].Snoozed) })
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	for _, m := range snoozed {
+		line := m.String()
+		if m.Owner != "" {
+			line += " (" + m.Owner + ")"
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\n", m.Snoozed.Format("2006-01-02"), line)
+	}
+
+	return tw.Flush()
+}
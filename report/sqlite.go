@@ -0,0 +1,175 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/matoous/godox"
+)
+
+// FileReporter is implemented by reporters writing the reports to files by themselves, e.g. databases,
+// instead of writing them to the writers.
+type FileReporter interface {
+	ReportFile(path string, messages []godox.Message) error
+}
+
+// DefaultSQLiteCommand is the command line shell of SQLite the SQLiteReporter writes the databases with.
+const DefaultSQLiteCommand = "sqlite3"
+
+// sqliteSchema creates the tables of the runs and their findings, the tables are shared by all runs
+// written to the same database so the findings can be compared across the runs.
+const sqliteSchema = `CREATE TABLE IF NOT EXISTS runs (
+  id INTEGER PRIMARY KEY,
+  started_at TEXT NOT NULL,
+  findings INTEGER NOT NULL,
+  suppressed INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS findings (
+  run_id INTEGER NOT NULL REFERENCES runs (id),
+  file TEXT NOT NULL,
+  line INTEGER NOT NULL,
+  column INTEGER NOT NULL,
+  keyword TEXT NOT NULL,
+  canonical TEXT NOT NULL,
+  rule_id TEXT NOT NULL,
+  severity TEXT NOT NULL,
+  text TEXT NOT NULL,
+  owner TEXT,
+  issue TEXT,
+  issue_tracker TEXT,
+  deadline TEXT,
+  declaration TEXT,
+  author TEXT,
+  commit_hash TEXT,
+  introduced TEXT,
+  fingerprint TEXT NOT NULL,
+  suppressed INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS findings_run_id ON findings (run_id);
+`
+
+// SQLiteReporter writes the messages and the metadata of the run as SQL statements inserting them into
+// the runs and findings tables, creating the tables if they don't exist. ReportFile adds them to the database
+// using the SQLite command line shell.
+type SQLiteReporter struct {
+	// Command is the SQLite shell, DefaultSQLiteCommand is used when empty.
+	Command string
+	// Now returns the start of the run, time.Now is used when nil.
+	Now func() time.Time
+}
+
+// SQLite writes the SQL statements inserting the messages.
+func SQLite(w io.Writer, messages []godox.Message) error {
+	return SQLiteReporter{}.Report(w, messages)
+}
+
+// Report implements Reporter, the statements are run in a single transaction.
+func (r SQLiteReporter) Report(w io.Writer, messages []godox.Message) error {
+	now := time.Now
+	if r.Now != nil {
+		now = r.Now
+	}
+
+	stats := godox.Stats(messages)
+
+	var b strings.Builder
+
+	b.WriteString("BEGIN;\n")
+	b.WriteString(sqliteSchema)
+	fmt.Fprintf(&b, "INSERT INTO runs (started_at, findings, suppressed) VALUES (%s, %d, %d);\n",
+		sqlTime(now()), stats.Total, stats.Suppressed)
+
+	for _, m := range messages {
+		canonical := m.Canonical
+		if canonical == "" {
+			canonical = m.Keyword
+		}
+
+		suppressed := 0
+		if m.Suppressed {
+			suppressed = 1
+		}
+
+		values := []string{
+			"(SELECT max(id) FROM runs)",
+			sqlString(filepath.ToSlash(filepath.Clean(m.Pos.Filename))),
+			strconv.Itoa(m.Line),
+			strconv.Itoa(m.Column),
+			sqlString(m.Keyword),
+			sqlString(canonical),
+			sqlString(m.RuleID),
+			sqlString(string(m.Severity)),
+			sqlString(m.Text),
+			sqlNullString(m.Owner),
+			sqlNullString(m.Issue),
+			sqlNullString(m.IssueTracker),
+			sqlTime(m.Deadline),
+			sqlNullString(m.Declaration),
+			sqlNullString(m.Author),
+			sqlNullString(m.Commit),
+			sqlTime(m.Introduced),
+			sqlString(m.Fingerprint()),
+			strconv.Itoa(suppressed),
+		}
+
+		fmt.Fprintf(&b, "INSERT INTO findings VALUES (%s);\n", strings.Join(values, ", "))
+	}
+
+	b.WriteString("COMMIT;\n")
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// ReportFile implements FileReporter, the database is created if it doesn't exist.
+func (r SQLiteReporter) ReportFile(path string, messages []godox.Message) error {
+	var script bytes.Buffer
+	if err := r.Report(&script, messages); err != nil {
+		return err
+	}
+
+	command := r.Command
+	if command == "" {
+		command = DefaultSQLiteCommand
+	}
+
+	cmd := exec.CommandContext(context.Background(), command, "-bail", path)
+	cmd.Stdin = &script
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("sqlite: %s %s: %w: %s", command, path, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
+
+func sqlString(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func sqlNullString(s string) string {
+	if s == "" {
+		return "NULL"
+	}
+
+	return sqlString(s)
+}
+
+func sqlTime(t time.Time) string {
+	if t.IsZero() {
+		return "NULL"
+	}
+
+	return sqlString(t.UTC().Format(time.RFC3339))
+}
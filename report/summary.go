@@ -0,0 +1,114 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/matoous/godox"
+)
+
+// Summary writes the statistics as text, the numbers in each group are sorted in descending order.
+// The effort estimates are written as working days of godox.EffortDay and hours, e.g. 1d4h.
+func Summary(w io.Writer, stats godox.Statistics) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Total: %d (%d errors, %d warnings, %d info), %d suppressed\n", stats.Total,
+		stats.Severities[godox.SeverityError], stats.Severities[godox.SeverityWarning],
+		stats.Severities[godox.SeverityInfo], stats.Suppressed)
+
+	if stats.Effort.Hours > 0 {
+		fmt.Fprintf(tw, "Effort: %s\n", formatEffort(stats.Effort.Hours))
+	}
+
+	owners := make(map[string]int, len(stats.Owners)+1)
+	for owner, count := range stats.Owners {
+		owners[owner] = count
+	}
+
+	if stats.Unowned > 0 {
+		owners["(none)"] = stats.Unowned
+	}
+
+	efforts := make(map[string]int, len(stats.Effort.Owners))
+	for owner, hours := range stats.Effort.Owners {
+		if owner == "" {
+			owner = "(none)"
+		}
+
+		efforts[owner] = hours
+	}
+
+	for _, group := range []struct {
+		name   string
+		counts map[string]int
+		format func(n int) string
+	}{
+		{name: "Keywords", counts: stats.Keywords},
+		{name: "Packages", counts: stats.Packages},
+		{name: "Files", counts: stats.Files},
+		{name: "Owners", counts: owners},
+		{name: "Labels", counts: stats.Labels},
+		{name: "Effort by packages", counts: stats.Effort.Packages, format: formatEffort},
+		{name: "Effort by owners", counts: efforts, format: formatEffort},
+	} {
+		if len(group.counts) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(tw, "\n%s:\n", group.name)
+
+		for _, key := range sortedByCount(group.counts) {
+			if group.format != nil {
+				fmt.Fprintf(tw, "  %s\t%s\n", key, group.format(group.counts[key]))
+			} else {
+				fmt.Fprintf(tw, "  %s\t%d\n", key, group.counts[key])
+			}
+		}
+	}
+
+	return tw.Flush()
+}
+
+// formatEffort formats the hours as working days of godox.EffortDay and hours, e.g. 1d4h for 12 hours.
+func formatEffort(hours int) string {
+	day := int(godox.EffortDay / time.Hour)
+
+	switch days := hours / day; {
+	case days == 0:
+		return fmt.Sprintf("%dh", hours)
+	case hours%day == 0:
+		return fmt.Sprintf("%dd", days)
+	default:
+		return fmt.Sprintf("%dd%dh", days, hours%day)
+	}
+}
+
+// SummaryJSON writes the statistics as JSON.
+func SummaryJSON(w io.Writer, stats godox.Statistics) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(stats)
+}
+
+// sortedByCount returns keys of the counts sorted by the count in descending order, and by the key.
+func sortedByCount(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+
+		return keys[i] < keys[j]
+	})
+
+	return keys
+}
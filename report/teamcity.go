@@ -0,0 +1,71 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/matoous/godox"
+)
+
+var teamcityEscaper = strings.NewReplacer(
+	"|", "||", "'", "|'", "\n", "|n", "\r", "|r", "[", "|[", "]", "|]",
+	"\u0085", "|x", "\u2028", "|l", "\u2029", "|p",
+)
+
+// TeamCity writes messages as TeamCity service messages shown in the Code Inspections tab of the build.
+// An inspection type is registered per keyword before its first finding. Suppressed messages are left out.
+func TeamCity(w io.Writer, messages []godox.Message) error {
+	registered := make(map[string]bool)
+
+	for _, m := range messages {
+		if m.Suppressed {
+			continue
+		}
+
+		keyword := m.Canonical
+		if keyword == "" {
+			keyword = m.Keyword
+		}
+
+		typeID := teamcityEscaper.Replace("godox." + keyword)
+
+		if !registered[typeID] {
+			registered[typeID] = true
+
+			_, err := fmt.Fprintf(w, "##teamcity[inspectionType id='%s' name='%s' description='%s' category='godox']\n",
+				typeID,
+				teamcityEscaper.Replace(keyword),
+				teamcityEscaper.Replace("Comments containing "+keyword),
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err := fmt.Fprintf(w, "##teamcity[inspection typeId='%s' message='%s' file='%s' line='%d' SEVERITY='%s']\n",
+			typeID,
+			teamcityEscaper.Replace(m.Description()),
+			teamcityEscaper.Replace(filepath.ToSlash(filepath.Clean(m.Pos.Filename))),
+			m.Line,
+			teamcitySeverity(m.Severity),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func teamcitySeverity(s godox.Severity) string {
+	switch s {
+	case godox.SeverityError:
+		return "ERROR"
+	case godox.SeverityInfo:
+		return "INFO"
+	default:
+		return "WARNING"
+	}
+}
@@ -0,0 +1,110 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/matoous/godox"
+)
+
+// DefaultTop is the number of files and packages listed by the TopReporter when none is configured.
+const DefaultTop = 10
+
+// TopReporter writes the files and packages with the most findings, and with the highest aggregate age of
+// the findings in days, to direct the refactoring effort. The ages are known only for the messages annotated
+// by git blame, the age tables are left out if there are none. Suppressed messages are left out.
+type TopReporter struct {
+	// N is the number of listed files and packages, DefaultTop is used when zero.
+	N int
+	// Now returns the time the ages are computed at, time.Now is used when nil.
+	Now func() time.Time
+}
+
+// Top writes the top offenders with the default number of files and packages.
+func Top(w io.Writer, messages []godox.Message) error {
+	return TopReporter{}.Report(w, messages)
+}
+
+func (r TopReporter) withOptions(opts Options) Reporter {
+	r.N = opts.Top
+	return r
+}
+
+// Report implements Reporter.
+func (r TopReporter) Report(w io.Writer, messages []godox.Message) error {
+	n := r.N
+	if n <= 0 {
+		n = DefaultTop
+	}
+
+	now := time.Now
+	if r.Now != nil {
+		now = r.Now
+	}
+
+	var (
+		files, packages       = make(map[string]int), make(map[string]int)
+		fileAges, packageAges = make(map[string]int), make(map[string]int)
+		aged                  bool
+	)
+
+	for _, m := range messages {
+		if m.Suppressed {
+			continue
+		}
+
+		name := filepath.ToSlash(filepath.Clean(m.Pos.Filename))
+		files[name]++
+		packages[path.Dir(name)]++
+
+		if m.Introduced.IsZero() {
+			continue
+		}
+
+		days := int(now().Sub(m.Introduced).Hours() / 24)
+		fileAges[name] += days
+		packageAges[path.Dir(name)] += days
+		aged = true
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	type table struct {
+		name   string
+		counts map[string]int
+	}
+
+	tables := []table{
+		{name: "Files by findings", counts: files},
+		{name: "Packages by findings", counts: packages},
+	}
+
+	if aged {
+		tables = append(tables,
+			table{name: "Files by age of findings in days", counts: fileAges},
+			table{name: "Packages by age of findings in days", counts: packageAges},
+		)
+	}
+
+	for i, t := range tables {
+		if i > 0 {
+			fmt.Fprintln(tw)
+		}
+
+		fmt.Fprintf(tw, "%s:\n", t.name)
+
+		for j, key := range sortedByCount(t.counts) {
+			if j == n {
+				break
+			}
+
+			fmt.Fprintf(tw, "  %s\t%d\n", key, t.counts[key])
+		}
+	}
+
+	return tw.Flush()
+}
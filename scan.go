@@ -0,0 +1,137 @@
+package godox
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"path/filepath"
+	"strings"
+
+	"github.com/matoous/godox/config"
+)
+
+// parseFile returns the file with its comments read by the engine of the settings, the comments of assembly
+// files are always scanned and those of other than Go files are read by the raw scanner.
+func parseFile(fset *token.FileSet, filename string, src []byte, settings *config.CompiledSettings) (*ast.File, error) {
+	switch {
+	case isAssembly(filename) || isGoSource(filename) && settings.Engine == config.EngineFast:
+		return scanComments(fset, filename, src), nil
+	case !isGoSource(filename):
+		return rawComments(fset, filename, src), nil
+	}
+
+	return parser.ParseFile(fset, filename, src, parser.ParseComments)
+}
+
+// isAssembly reports whether the file is a Go assembly file, its comments have the same syntax as in Go.
+func isAssembly(filename string) bool {
+	return filepath.Ext(filename) == ".s"
+}
+
+// commentScanner collects the comments of a file grouped the same way as by go/parser.
+type commentScanner struct {
+	scanner scanner.Scanner
+	file    *token.File
+
+	pos token.Pos
+	tok token.Token
+	lit string
+}
+
+// scanComments returns file containing only the package clause, the imports and the comments of the source,
+// syntax errors are ignored. The comments of other than Go sources, e.g. assembly, are read as well.
+func scanComments(fset *token.FileSet, filename string, src []byte) *ast.File {
+	s := &commentScanner{file: fset.AddFile(filename, -1, len(src))}
+	s.scanner.Init(s.file, src, nil, scanner.ScanComments)
+
+	var (
+		f    ast.File
+		prev token.Pos
+		imp  *ast.GenDecl
+	)
+
+	s.next()
+
+	for s.tok != token.EOF {
+		if s.tok == token.COMMENT {
+			// a comment on the line of the previous token is a group of its own, such as a line comment
+			if s.file.Line(s.pos) == s.file.Line(prev) {
+				f.Comments = append(f.Comments, s.group(0))
+			}
+
+			for s.tok == token.COMMENT {
+				f.Comments = append(f.Comments, s.group(1))
+			}
+
+			continue
+		}
+
+		switch {
+		case s.tok == token.PACKAGE && !f.Package.IsValid():
+			f.Package = s.pos
+		case s.tok == token.IDENT && f.Name == nil && f.Package.IsValid():
+			f.Name = &ast.Ident{NamePos: s.pos, Name: s.lit}
+		case s.tok == token.IMPORT:
+			imp = &ast.GenDecl{Doc: s.lead(&f, prev), TokPos: s.pos, Tok: token.IMPORT}
+			f.Decls = append(f.Decls, imp)
+		case imp != nil && s.tok == token.LPAREN && !imp.Lparen.IsValid():
+			imp.Lparen = s.pos
+		case imp != nil && s.tok == token.STRING:
+			spec := &ast.ImportSpec{Path: &ast.BasicLit{ValuePos: s.pos, Kind: token.STRING, Value: s.lit}}
+			if imp.Lparen.IsValid() {
+				spec.Doc = s.lead(&f, prev)
+			}
+
+			imp.Specs = append(imp.Specs, spec)
+			f.Imports = append(f.Imports, spec)
+		case imp != nil && (s.tok == token.RPAREN || s.tok == token.SEMICOLON && !imp.Lparen.IsValid()):
+			imp = nil
+		}
+
+		prev = s.pos
+		s.next()
+	}
+
+	return &f
+}
+
+// lead returns the last comment group of the file if it ends on the line before the current token
+// and starts after the previous token, like the doc comments of go/parser.
+func (s *commentScanner) lead(f *ast.File, prev token.Pos) *ast.CommentGroup {
+	if len(f.Comments) == 0 {
+		return nil
+	}
+
+	g := f.Comments[len(f.Comments)-1]
+	if g.Pos() <= prev || s.file.Line(g.End()) != s.file.Line(s.pos)-1 {
+		return nil
+	}
+
+	return g
+}
+
+func (s *commentScanner) next() {
+	s.pos, s.tok, s.lit = s.scanner.Scan()
+}
+
+// group consumes the comments starting at most n lines after the end of the previous comment.
+func (s *commentScanner) group(n int) *ast.CommentGroup {
+	var (
+		list    []*ast.Comment
+		endline = s.file.Line(s.pos)
+	)
+
+	for s.tok == token.COMMENT && s.file.Line(s.pos) <= endline+n {
+		list = append(list, &ast.Comment{Slash: s.pos, Text: s.lit})
+
+		endline = s.file.Line(s.pos)
+		if s.lit[1] == '*' {
+			endline += strings.Count(s.lit, "\n")
+		}
+
+		s.next()
+	}
+
+	return &ast.CommentGroup{List: list}
+}
@@ -0,0 +1,57 @@
+package godox
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/matoous/godox/config"
+)
+
+// SortMessages sorts the messages by file name, line and column, messages at the same position keep their order.
+func SortMessages(messages []Message) {
+	sort.SliceStable(messages, func(i, j int) bool {
+		a, b := messages[i], messages[j]
+
+		if fa, fb := filepath.Clean(a.Pos.Filename), filepath.Clean(b.Pos.Filename); fa != fb {
+			return fa < fb
+		}
+
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+
+		return a.Column < b.Column
+	})
+}
+
+// SortByPriority sorts the messages by priority, the messages without priorities are last.
+// Messages with the same priority keep their order.
+func SortByPriority(messages []Message) {
+	sort.SliceStable(messages, func(i, j int) bool {
+		return config.PriorityRank(messages[i].Priority) < config.PriorityRank(messages[j].Priority)
+	})
+}
+
+// Deduplicate returns the sorted messages without the identical messages reported by the same rule
+// at the same position, e.g. when the same file is scanned with different build tags.
+func Deduplicate(messages []Message) []Message {
+	SortMessages(messages)
+
+	var (
+		unique []Message
+		seen   = make(map[string]struct{})
+	)
+
+	for _, m := range messages {
+		key := fmt.Sprintf("%s:%d:%d:%s:%s", filepath.Clean(m.Pos.Filename), m.Line, m.Column, m.RuleID, m.Message)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+
+		seen[key] = struct{}{}
+		unique = append(unique, m)
+	}
+
+	return unique
+}
@@ -0,0 +1,89 @@
+package godox
+
+import (
+	"path"
+	"path/filepath"
+	"time"
+)
+
+// Statistics are numbers of messages grouped by their properties, suppressed messages are counted
+// only in Suppressed.
+type Statistics struct {
+	Total      int `json:"total"`
+	Suppressed int `json:"suppressed"`
+	// Keywords are keyed by the canonical keywords.
+	Keywords   map[string]int   `json:"keywords"`
+	Severities map[Severity]int `json:"severities"`
+	// Packages are keyed by the slash separated directories of the files.
+	Packages map[string]int `json:"packages"`
+	Files    map[string]int `json:"files"`
+	Owners   map[string]int `json:"owners"`
+	// Labels are counted for every label of the messages.
+	Labels map[string]int `json:"labels"`
+	// Unowned is the number of messages without an owner.
+	Unowned int `json:"unowned"`
+	// Effort are the sums of the effort estimates of the messages.
+	Effort EffortStatistics `json:"effort"`
+}
+
+// EffortStatistics are sums of the effort estimates in hours, a working day being EffortDay and a working week
+// EffortWeek, messages without estimates are left out.
+type EffortStatistics struct {
+	Hours int `json:"hours"`
+	// Packages are keyed by the slash separated directories of the files.
+	Packages map[string]int `json:"packages"`
+	// Owners are keyed by the owners, the estimates of the messages without owners are keyed by an empty string.
+	Owners map[string]int `json:"owners"`
+}
+
+// Stats counts the messages per keyword, severity, package, file, owner and label, and sums their effort
+// estimates per package and owner.
+func Stats(messages []Message) Statistics {
+	s := Statistics{
+		Keywords:   make(map[string]int),
+		Severities: make(map[Severity]int),
+		Packages:   make(map[string]int),
+		Files:      make(map[string]int),
+		Owners:     make(map[string]int),
+		Labels:     make(map[string]int),
+		Effort:     EffortStatistics{Packages: make(map[string]int), Owners: make(map[string]int)},
+	}
+
+	for _, m := range messages {
+		if m.Suppressed {
+			s.Suppressed++
+			continue
+		}
+
+		keyword := m.Canonical
+		if keyword == "" {
+			keyword = m.Keyword
+		}
+
+		name := filepath.ToSlash(filepath.Clean(m.Pos.Filename))
+
+		s.Total++
+		s.Keywords[keyword]++
+		s.Severities[m.Severity]++
+		s.Packages[path.Dir(name)]++
+		s.Files[name]++
+
+		for _, label := range m.Labels {
+			s.Labels[label]++
+		}
+
+		if m.Owner != "" {
+			s.Owners[m.Owner]++
+		} else {
+			s.Unowned++
+		}
+
+		if hours := int(m.Effort / time.Hour); hours > 0 {
+			s.Effort.Hours += hours
+			s.Effort.Packages[path.Dir(name)] += hours
+			s.Effort.Owners[m.Owner] += hours
+		}
+	}
+
+	return s
+}
@@ -0,0 +1,169 @@
+package godox
+
+import (
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strings"
+)
+
+// directiveRe matches the nolint and godox:ignore directives anywhere in the comment, followed by white space
+// or the end of the comment, the first group contains the comma separated list of linters or keywords.
+var directiveRe = regexp.MustCompile(`//\s?(?:nolint|godox:ignore)(?::([\w,-]+))?(?:\s|$)`)
+
+// ruleDirectiveRe matches the godox:disable and godox:enable directives, the second group contains the comma
+// separated list of rule IDs.
+var ruleDirectiveRe = regexp.MustCompile(`//\s?godox:(disable|enable) ([\w,-]+)`)
+
+// suppression describes which keywords are suppressed by a directive.
+type suppression struct {
+	all      bool
+	keywords []string
+}
+
+// add merges the other suppression into s.
+func (s *suppression) add(other suppression) {
+	s.all = s.all || other.all
+	s.keywords = append(s.keywords, other.keywords...)
+}
+
+// suppresses reports whether findings for any of the keywords are suppressed.
+func (s suppression) suppresses(keywords ...string) bool {
+	if s.all {
+		return true
+	}
+
+	for _, kw := range s.keywords {
+		for _, keyword := range keywords {
+			if strings.EqualFold(kw, keyword) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// parseDirectives returns suppression described by the directives in the comment text.
+func parseDirectives(text string) (suppression, bool) {
+	var (
+		s     suppression
+		found bool
+	)
+
+	for _, m := range directiveRe.FindAllStringSubmatch(text, -1) {
+		list := strings.Split(m[1], ",")
+
+		if strings.Contains(m[0], "nolint") {
+			// nolint without a list of linters suppresses all of them
+			if m[1] == "" || contains(list, "godox") {
+				s.all, found = true, true
+			}
+
+			continue
+		}
+
+		found = true
+
+		if m[1] == "" {
+			s.all = true
+		} else {
+			s.keywords = append(s.keywords, list...)
+		}
+	}
+
+	return s, found
+}
+
+// suppressions collects the directives in the file, grouped by the comment groups containing them
+// and by the lines they are on.
+func suppressions(file *ast.File, fset *token.FileSet) (map[*ast.CommentGroup]suppression, map[int]suppression) {
+	groups := make(map[*ast.CommentGroup]suppression)
+	lines := make(map[int]suppression)
+
+	for _, c := range file.Comments {
+		for _, ci := range c.List {
+			s, ok := parseDirectives(ci.Text)
+			if !ok {
+				continue
+			}
+
+			gs := groups[c]
+			gs.add(s)
+			groups[c] = gs
+
+			line := fset.Position(ci.Slash).Line
+			ls := lines[line]
+			ls.add(s)
+			lines[line] = ls
+		}
+	}
+
+	return groups, lines
+}
+
+// ruleDirective disables or enables the rules from its line to the end of the file or the next directive.
+type ruleDirective struct {
+	line   int
+	enable bool
+	rules  []string
+}
+
+// ruleDirectives are the godox:disable and godox:enable directives of a file in the order of their lines.
+type ruleDirectives []ruleDirective
+
+// disables reports whether any of the rules is disabled on the line.
+func (d ruleDirectives) disables(line int, rules ...string) bool {
+	for _, rule := range rules {
+		if rule == "" {
+			continue
+		}
+
+		disabled := false
+
+		for _, directive := range d {
+			if directive.line > line {
+				break
+			}
+
+			if contains(directive.rules, rule) {
+				disabled = !directive.enable
+			}
+		}
+
+		if disabled {
+			return true
+		}
+	}
+
+	return false
+}
+
+// disabledRules collects the godox:disable and godox:enable directives of the file.
+func disabledRules(file *ast.File, fset *token.FileSet) ruleDirectives {
+	var directives ruleDirectives
+
+	for _, c := range file.Comments {
+		for _, ci := range c.List {
+			for _, m := range ruleDirectiveRe.FindAllStringSubmatch(ci.Text, -1) {
+				directives = append(directives, ruleDirective{
+					line:   fset.Position(ci.Slash).Line,
+					enable: m[1] == "enable",
+					rules:  strings.Split(m[2], ","),
+				})
+			}
+		}
+	}
+
+	return directives
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if strings.TrimSpace(item) == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,103 @@
+package tracker
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// cache of the issue statuses, in memory and optionally on disk.
+type cache struct {
+	dir string
+	ttl time.Duration
+
+	mu       sync.Mutex
+	statuses map[string]Status
+}
+
+type cacheEntry struct {
+	Status  Status    `json:"status"`
+	Checked time.Time `json:"checked"`
+}
+
+func newCache(dir string, ttl time.Duration) *cache {
+	return &cache{dir: dir, ttl: ttl, statuses: make(map[string]Status)}
+}
+
+func (c *cache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// get returns the status cached in memory or on disk if it is not older than the TTL.
+func (c *cache) get(key string) (Status, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if status, ok := c.statuses[key]; ok {
+		return status, true
+	}
+
+	if c.dir == "" {
+		return "", false
+	}
+
+	data, err := ioutil.ReadFile(c.path(key))
+	if err != nil {
+		return "", false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || time.Since(entry.Checked) > c.ttl {
+		return "", false
+	}
+
+	c.statuses[key] = entry.Status
+
+	return entry.Status, true
+}
+
+// put caches the status, failures to write the cache are ignored as it only speeds up the lookups.
+func (c *cache) put(key string, status Status) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.statuses[key] = status
+
+	if c.dir == "" {
+		return
+	}
+
+	data, err := json.Marshal(cacheEntry{Status: status, Checked: time.Now()})
+	if err != nil {
+		return
+	}
+
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return
+	}
+
+	// write to a temporary file first so concurrent runs never read partially written entries
+	tmp, err := ioutil.TempFile(c.dir, "tmp-")
+	if err != nil {
+		return
+	}
+
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+
+	if err == nil {
+		err = os.Rename(tmp.Name(), c.path(key))
+	}
+
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+}
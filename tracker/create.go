@@ -0,0 +1,35 @@
+package tracker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/matoous/godox/config"
+)
+
+// Issue is an issue to create.
+type Issue struct {
+	Title  string
+	Body   string
+	Labels []string
+}
+
+// Creator creates issues in an issue tracker.
+type Creator interface {
+	// Create creates the issue and returns reference to it, such as #123 or PROJ-123.
+	Create(ctx context.Context, issue Issue) (string, error)
+}
+
+// NewCreator returns creator of issues in the github, gitlab or jira tracker configured by the settings.
+func NewCreator(tracker string, settings *config.CompiledSettings) (Creator, error) {
+	switch tracker {
+	case "github":
+		return newGitHub(settings), nil
+	case "gitlab":
+		return newGitLab(settings), nil
+	case "jira":
+		return newJira(settings), nil
+	default:
+		return nil, fmt.Errorf("unknown tracker %q, one of: github, gitlab, jira", tracker)
+	}
+}
@@ -0,0 +1,55 @@
+package tracker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Exec looks up issues of custom trackers by running a command with the reference as the last argument.
+// The command prints the status of the issue, open, closed or missing, or unsupported if it doesn't
+// recognize the reference.
+type Exec struct {
+	Command []string
+}
+
+// Lookup runs the command to get the status of the referenced issue.
+func (e *Exec) Lookup(ctx context.Context, ref string) (Status, error) {
+	if len(e.Command) == 0 {
+		return "", fmt.Errorf("issue %s: no command", ref)
+	}
+
+	args := append(append([]string(nil), e.Command[1:]...), ref)
+	cmd := exec.CommandContext(ctx, e.Command[0], args...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("issue %s: %s: %w: %s", ref, strings.Join(e.Command, " "), err, strings.TrimSpace(stderr.String()))
+	}
+
+	switch status := strings.TrimSpace(string(out)); status {
+	case string(StatusOpen), string(StatusClosed), string(StatusMissing):
+		return Status(status), nil
+	case "unsupported":
+		return "", ErrUnsupported
+	default:
+		return "", fmt.Errorf("issue %s: %s: unknown status %q", ref, strings.Join(e.Command, " "), status)
+	}
+}
+
+// Canonical returns the reference with the command and the directory it runs in, the commands might resolve
+// the references relative to the repository, e.g. #123.
+func (e *Exec) Canonical(ref string) (string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join(e.Command, " ") + " " + dir + " " + ref, nil
+}
@@ -0,0 +1,141 @@
+package tracker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	githubRefRe = regexp.MustCompile(`^(?:([\w.-]+/[\w.-]+))?#(\d+)$`)
+	githubURLRe = regexp.MustCompile(`^https?://([^/]+)/([\w.-]+/[\w.-]+)/(?:issues|pull)/(\d+)`)
+)
+
+// GitHub looks up GitHub issues and pull requests.
+type GitHub struct {
+	// APIURL is the URL of the GitHub API, e.g. https://api.github.com.
+	APIURL string
+	// Token authenticates the requests, anonymous requests have lower rate limit.
+	Token string
+	// Repository, owner/repo, of references without a repository, e.g. #123.
+	Repository string
+	Client     *http.Client
+	// MaxWait is the longest time to wait for the rate limit to reset, the lookups fail if they would have to wait longer.
+	MaxWait time.Duration
+}
+
+// Lookup returns status of the issue referenced as #123, owner/repo#123 or by URL of the issue or pull request.
+// URLs of other hosts than github.com or the host of the GitHub Enterprise API are not supported.
+func (g *GitHub) Lookup(ctx context.Context, ref string) (Status, error) {
+	repository, number, err := g.parse(ref)
+	if err != nil {
+		return "", err
+	}
+
+	endpoint := fmt.Sprintf("%s/repos/%s/issues/%s", strings.TrimSuffix(g.APIURL, "/"), repository, number)
+
+	var issue struct {
+		State string `json:"state"`
+	}
+
+	found, err := getJSON(ctx, g.Client, endpoint, g.header(), g.MaxWait, &issue)
+	if err != nil {
+		return "", fmt.Errorf("github issue %s: %w", ref, err)
+	}
+
+	switch {
+	case !found:
+		return StatusMissing, nil
+	case issue.State == "closed":
+		return StatusClosed, nil
+	default:
+		return StatusOpen, nil
+	}
+}
+
+// parse returns the repository and the number of the referenced issue.
+func (g *GitHub) parse(ref string) (repository, number string, err error) {
+	if m := githubRefRe.FindStringSubmatch(ref); m != nil {
+		repository = m[1]
+		if repository == "" {
+			repository = g.Repository
+		}
+
+		if repository == "" {
+			return "", "", fmt.Errorf("github issue %s: repository is not configured", ref)
+		}
+
+		return repository, m[2], nil
+	}
+
+	if m := githubURLRe.FindStringSubmatch(ref); m != nil && g.isWebHost(m[1]) {
+		return m[2], m[3], nil
+	}
+
+	return "", "", ErrUnsupported
+}
+
+// Canonical returns the reference with the host and the repository of the issue, e.g. github.com/owner/repo#123.
+func (g *GitHub) Canonical(ref string) (string, error) {
+	repository, number, err := g.parse(ref)
+	if err != nil {
+		return "", err
+	}
+
+	host := "github.com"
+	if api, err := url.Parse(g.APIURL); err == nil && api.Host != "" && api.Host != "api.github.com" {
+		host = api.Host
+	}
+
+	return host + "/" + repository + "#" + number, nil
+}
+
+// isWebHost reports whether the host serves the web pages of the API.
+func (g *GitHub) isWebHost(host string) bool {
+	if host == "github.com" || host == "www.github.com" {
+		return true
+	}
+
+	api, err := url.Parse(g.APIURL)
+
+	return err == nil && api.Host != "api.github.com" && api.Host == host
+}
+
+// Create creates the issue in the Repository and returns reference to it, e.g. #123.
+func (g *GitHub) Create(ctx context.Context, issue Issue) (string, error) {
+	if g.Repository == "" {
+		return "", errors.New("github: repository is not configured")
+	}
+
+	endpoint := fmt.Sprintf("%s/repos/%s/issues", strings.TrimSuffix(g.APIURL, "/"), g.Repository)
+
+	request := struct {
+		Title  string   `json:"title"`
+		Body   string   `json:"body"`
+		Labels []string `json:"labels,omitempty"`
+	}{Title: issue.Title, Body: issue.Body, Labels: issue.Labels}
+
+	var created struct {
+		Number int `json:"number"`
+	}
+
+	if err := postJSON(ctx, g.Client, endpoint, g.header(), request, &created); err != nil {
+		return "", fmt.Errorf("github: create issue: %w", err)
+	}
+
+	return fmt.Sprintf("#%d", created.Number), nil
+}
+
+func (g *GitHub) header() http.Header {
+	header := http.Header{"Accept": {"application/vnd.github+json"}}
+	if g.Token != "" {
+		header.Set("Authorization", "Bearer "+g.Token)
+	}
+
+	return header
+}
@@ -0,0 +1,150 @@
+package tracker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	gitlabRefRe = regexp.MustCompile(`^(?:([\w.-]+(?:/[\w.-]+)+))?#(\d+)$`)
+	gitlabURLRe = regexp.MustCompile(`^https?://([^/]+)/([\w.-]+(?:/[\w.-]+)+)/-/(?:issues|merge_requests)/(\d+)`)
+)
+
+// GitLab looks up GitLab issues.
+type GitLab struct {
+	// URL of the GitLab instance, e.g. https://gitlab.com.
+	URL string
+	// Token authenticates the requests, only public issues can be looked up without it.
+	Token string
+	// Project, group/project, of references without a project, e.g. #123.
+	Project string
+	Client  *http.Client
+	// MaxWait is the longest time to wait for the rate limit to reset, the lookups fail if they would have to wait longer.
+	MaxWait time.Duration
+}
+
+// Lookup returns status of the issue referenced as #123, group/project#123 or by URL of the issue on the instance.
+func (g *GitLab) Lookup(ctx context.Context, ref string) (Status, error) {
+	project, number, err := g.parse(ref)
+	if err != nil {
+		return "", err
+	}
+
+	kind := "issues"
+	if isMergeRequest(ref) {
+		kind = "merge_requests"
+	}
+
+	endpoint := fmt.Sprintf("%s/api/v4/projects/%s/%s/%s", strings.TrimSuffix(g.URL, "/"), url.PathEscape(project), kind, number)
+
+	var issue struct {
+		State string `json:"state"`
+	}
+
+	found, err := getJSON(ctx, g.Client, endpoint, g.header(), g.MaxWait, &issue)
+	if err != nil {
+		return "", fmt.Errorf("gitlab issue %s: %w", ref, err)
+	}
+
+	switch {
+	case !found:
+		return StatusMissing, nil
+	case issue.State == "closed" || issue.State == "merged":
+		return StatusClosed, nil
+	default:
+		return StatusOpen, nil
+	}
+}
+
+// parse returns the project and the number of the referenced issue.
+func (g *GitLab) parse(ref string) (project, number string, err error) {
+	if m := gitlabRefRe.FindStringSubmatch(ref); m != nil {
+		project = m[1]
+		if project == "" {
+			project = g.Project
+		}
+
+		if project == "" {
+			return "", "", fmt.Errorf("gitlab issue %s: project is not configured", ref)
+		}
+
+		return project, m[2], nil
+	}
+
+	instance, err := url.Parse(g.URL)
+	if err != nil {
+		return "", "", err
+	}
+
+	if m := gitlabURLRe.FindStringSubmatch(ref); m != nil && m[1] == instance.Host {
+		return m[2], m[3], nil
+	}
+
+	return "", "", ErrUnsupported
+}
+
+// Canonical returns the reference with the host and the project of the issue, e.g. gitlab.com/group/project#123,
+// or of the merge request, e.g. gitlab.com/group/project!123.
+func (g *GitLab) Canonical(ref string) (string, error) {
+	project, number, err := g.parse(ref)
+	if err != nil {
+		return "", err
+	}
+
+	instance, err := url.Parse(g.URL)
+	if err != nil {
+		return "", err
+	}
+
+	kind := "#"
+	if isMergeRequest(ref) {
+		kind = "!"
+	}
+
+	return instance.Host + "/" + project + kind + number, nil
+}
+
+// isMergeRequest reports whether the reference is the URL of a merge request.
+func isMergeRequest(ref string) bool {
+	return strings.Contains(ref, "/-/merge_requests/")
+}
+
+// Create creates the issue in the Project and returns reference to it, e.g. #123.
+func (g *GitLab) Create(ctx context.Context, issue Issue) (string, error) {
+	if g.Project == "" {
+		return "", errors.New("gitlab: project is not configured")
+	}
+
+	endpoint := fmt.Sprintf("%s/api/v4/projects/%s/issues", strings.TrimSuffix(g.URL, "/"), url.PathEscape(g.Project))
+
+	request := struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Labels      string `json:"labels,omitempty"`
+	}{Title: issue.Title, Description: issue.Body, Labels: strings.Join(issue.Labels, ",")}
+
+	var created struct {
+		IID int `json:"iid"`
+	}
+
+	if err := postJSON(ctx, g.Client, endpoint, g.header(), request, &created); err != nil {
+		return "", fmt.Errorf("gitlab: create issue: %w", err)
+	}
+
+	return fmt.Sprintf("#%d", created.IID), nil
+}
+
+func (g *GitLab) header() http.Header {
+	header := http.Header{"Accept": {"application/json"}}
+	if g.Token != "" {
+		header.Set("Private-Token", g.Token)
+	}
+
+	return header
+}
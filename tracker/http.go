@@ -0,0 +1,120 @@
+package tracker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultMaxWait is the longest time lookups wait for the rate limit to reset.
+const DefaultMaxWait = time.Minute
+
+// getJSON decodes the JSON response of the endpoint to v, it returns false if the resource doesn't exist.
+// Rate limited requests are retried once if the limit resets within maxWait.
+func getJSON(ctx context.Context, client *http.Client, endpoint string, header http.Header, maxWait time.Duration, v interface{}) (bool, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return false, err
+		}
+
+		for k, values := range header {
+			req.Header[k] = values
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return false, err
+		}
+
+		if resp.StatusCode == http.StatusOK {
+			err := json.NewDecoder(resp.Body).Decode(v)
+			resp.Body.Close()
+
+			return err == nil, err
+		}
+
+		resp.Body.Close()
+
+		if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone {
+			return false, nil
+		}
+
+		wait, limited := rateLimitWait(resp, time.Now())
+		if !limited {
+			return false, fmt.Errorf("unexpected status %s", resp.Status)
+		}
+
+		if attempt > 0 || wait > maxWait {
+			return false, fmt.Errorf("rate limit exceeded, retry in %s", wait.Round(time.Second))
+		}
+
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// rateLimitWait returns how long to wait before retrying the rate limited request.
+func rateLimitWait(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return 0, false
+	}
+
+	reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	if wait := time.Unix(reset, 0).Sub(now); wait > 0 {
+		return wait, true
+	}
+
+	return 0, true
+}
+
+// postJSON posts v as JSON to the endpoint and decodes the JSON response to result.
+// The requests aren't retried since they are not idempotent.
+func postJSON(ctx context.Context, client *http.Client, endpoint string, header http.Header, v, result interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	for k, values := range header {
+		req.Header[k] = values
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
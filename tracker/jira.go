@@ -0,0 +1,146 @@
+package tracker
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var jiraKeyRe = regexp.MustCompile(`^[A-Z][A-Z0-9]+-\d+$`)
+
+// Jira looks up Jira issues.
+type Jira struct {
+	// URL of the Jira instance, e.g. https://example.atlassian.net.
+	URL string
+	// User authenticates the requests together with the Token using basic authentication,
+	// the Token is sent as a bearer token if there is no user.
+	User  string
+	Token string
+	// Project and IssueType of the created issues, e.g. PROJ and Task.
+	Project   string
+	IssueType string
+	Client    *http.Client
+	// MaxWait is the longest time to wait for the rate limit to reset, the lookups fail if they would have to wait longer.
+	MaxWait time.Duration
+}
+
+// Lookup returns status of the issue referenced by its key, e.g. PROJ-123, or its browse URL.
+// Issues in the done status category are closed.
+func (j *Jira) Lookup(ctx context.Context, ref string) (Status, error) {
+	base := strings.TrimSuffix(j.URL, "/")
+
+	key, err := j.key(ref)
+	if err != nil {
+		return "", err
+	}
+
+	var issue struct {
+		Fields struct {
+			Status struct {
+				StatusCategory struct {
+					Key string `json:"key"`
+				} `json:"statusCategory"`
+			} `json:"status"`
+		} `json:"fields"`
+	}
+
+	endpoint := fmt.Sprintf("%s/rest/api/2/issue/%s?fields=status", base, url.PathEscape(key))
+
+	found, err := getJSON(ctx, j.Client, endpoint, j.header(), j.MaxWait, &issue)
+	if err != nil {
+		return "", fmt.Errorf("jira issue %s: %w", ref, err)
+	}
+
+	switch {
+	case !found:
+		return StatusMissing, nil
+	case issue.Fields.Status.StatusCategory.Key == "done":
+		return StatusClosed, nil
+	default:
+		return StatusOpen, nil
+	}
+}
+
+// Canonical returns the reference with the host of the instance and the key of the issue, e.g. example.atlassian.net/PROJ-123.
+func (j *Jira) Canonical(ref string) (string, error) {
+	key, err := j.key(ref)
+	if err != nil {
+		return "", err
+	}
+
+	instance, err := url.Parse(j.URL)
+	if err != nil {
+		return "", err
+	}
+
+	return instance.Host + "/" + key, nil
+}
+
+// key returns the key of the issue referenced by its key or its browse URL.
+func (j *Jira) key(ref string) (string, error) {
+	key := ref
+	if browse := strings.TrimSuffix(j.URL, "/") + "/browse/"; strings.HasPrefix(ref, browse) {
+		key = strings.TrimPrefix(ref, browse)
+	}
+
+	if !jiraKeyRe.MatchString(key) {
+		return "", ErrUnsupported
+	}
+
+	return key, nil
+}
+
+// Create creates the issue in the Project and returns its key, e.g. PROJ-123.
+func (j *Jira) Create(ctx context.Context, issue Issue) (string, error) {
+	if j.URL == "" || j.Project == "" {
+		return "", errors.New("jira: url and project are not configured")
+	}
+
+	type key struct {
+		Key  string `json:"key,omitempty"`
+		Name string `json:"name,omitempty"`
+	}
+
+	var request struct {
+		Fields struct {
+			Project     key      `json:"project"`
+			IssueType   key      `json:"issuetype"`
+			Summary     string   `json:"summary"`
+			Description string   `json:"description"`
+			Labels      []string `json:"labels,omitempty"`
+		} `json:"fields"`
+	}
+
+	request.Fields.Project.Key = j.Project
+	request.Fields.IssueType.Name = j.IssueType
+	request.Fields.Summary = issue.Title
+	request.Fields.Description = issue.Body
+	request.Fields.Labels = issue.Labels
+
+	var created key
+
+	if err := postJSON(ctx, j.Client, strings.TrimSuffix(j.URL, "/")+"/rest/api/2/issue", j.header(), request, &created); err != nil {
+		return "", fmt.Errorf("jira: create issue: %w", err)
+	}
+
+	return created.Key, nil
+}
+
+func (j *Jira) header() http.Header {
+	header := http.Header{"Accept": {"application/json"}}
+
+	switch {
+	case j.User != "":
+		header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(j.User+":"+j.Token)))
+	case j.Token != "":
+		header.Set("Authorization", "Bearer "+j.Token)
+	}
+
+	return header
+}
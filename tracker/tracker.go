@@ -0,0 +1,223 @@
+// Package tracker looks up the issues referenced by the comments in their issue trackers,
+// reporting comments which reference closed or missing issues.
+package tracker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/matoous/godox"
+	"github.com/matoous/godox/config"
+)
+
+// Status of an issue.
+type Status string
+
+// Issue statuses.
+const (
+	StatusOpen    Status = "open"
+	StatusClosed  Status = "closed"
+	StatusMissing Status = "missing"
+)
+
+// ErrUnsupported is returned for references the tracker doesn't recognize.
+var ErrUnsupported = errors.New("unsupported issue reference")
+
+// DefaultCacheTTL is how long the looked up statuses are cached for.
+const DefaultCacheTTL = time.Hour
+
+// Provider looks up issues in an issue tracker.
+type Provider interface {
+	// Lookup returns the status of the referenced issue, ErrUnsupported if the provider doesn't recognize the reference.
+	Lookup(ctx context.Context, ref string) (Status, error)
+	// Canonical returns the reference identifying the issue across the repositories and the instances of the tracker,
+	// e.g. github.com/owner/repo#123 for #123, ErrUnsupported if the provider doesn't recognize the reference.
+	Canonical(ref string) (string, error)
+}
+
+// Checker looks up the issues referenced by the messages.
+type Checker struct {
+	// Providers by the tracker names of the issue patterns, references found by the url pattern
+	// are looked up by all providers which recognize them.
+	Providers map[string]Provider
+	// ReportMissing enables reporting of references to issues which don't exist.
+	ReportMissing bool
+	// MaxMessageLength is the number of runes of the comment lines quoted in the messages, 0 disables the truncation.
+	MaxMessageLength int
+
+	cache *cache
+}
+
+// NewChecker returns checker with the GitHub and GitLab providers, the Jira provider if the URL of the Jira
+// instance is known, and the tracker commands configured by the settings. The looked up statuses are cached for
+// DefaultCacheTTL in the issues directory of the CacheDir, if set.
+func NewChecker(settings *config.CompiledSettings) *Checker {
+	providers := map[string]Provider{
+		"github": newGitHub(settings),
+		"gitlab": newGitLab(settings),
+	}
+
+	if jira := newJira(settings); jira.URL != "" {
+		providers["jira"] = jira
+	}
+
+	for _, c := range settings.TrackerCommands {
+		providers[c.Tracker] = &Exec{Command: c.Command}
+	}
+
+	var dir string
+	if settings.CacheDir != "" {
+		dir = filepath.Join(settings.CacheDir, "issues")
+	}
+
+	return &Checker{
+		Providers:        providers,
+		ReportMissing:    settings.ReportMissingIssues,
+		MaxMessageLength: settings.MessageLength(),
+		cache:            newCache(dir, DefaultCacheTTL),
+	}
+}
+
+// newGitHub returns GitHub configured by the settings and the environment.
+func newGitHub(settings *config.CompiledSettings) *GitHub {
+	return &GitHub{
+		APIURL:     settings.GitHub.APIURL,
+		Token:      os.Getenv(settings.GitHub.TokenEnv),
+		Repository: getenv(settings.GitHub.Repository, "GITHUB_REPOSITORY"),
+		Client:     http.DefaultClient,
+		MaxWait:    DefaultMaxWait,
+	}
+}
+
+// newGitLab returns GitLab configured by the settings and the environment.
+func newGitLab(settings *config.CompiledSettings) *GitLab {
+	return &GitLab{
+		URL:     settings.GitLab.URL,
+		Token:   os.Getenv(settings.GitLab.TokenEnv),
+		Project: getenv(settings.GitLab.Project, "CI_PROJECT_PATH"),
+		Client:  http.DefaultClient,
+		MaxWait: DefaultMaxWait,
+	}
+}
+
+// newJira returns Jira configured by the settings and the environment, its URL is empty if it isn't known.
+func newJira(settings *config.CompiledSettings) *Jira {
+	return &Jira{
+		URL:       getenv(settings.Jira.URL, "JIRA_URL"),
+		User:      os.Getenv(settings.Jira.UserEnv),
+		Token:     os.Getenv(settings.Jira.TokenEnv),
+		Project:   settings.Jira.Project,
+		IssueType: settings.Jira.IssueType,
+		Client:    http.DefaultClient,
+		MaxWait:   DefaultMaxWait,
+	}
+}
+
+// getenv returns the value, or the environment variable if the value is empty.
+func getenv(value, env string) string {
+	if value != "" {
+		return value
+	}
+
+	return os.Getenv(env)
+}
+
+// Check returns messages for the comments referencing closed issues, and issues which don't exist
+// if ReportMissing is set. Messages without references the checker recognizes are left out.
+func (c *Checker) Check(ctx context.Context, messages []godox.Message) ([]godox.Message, error) {
+	var (
+		checked []godox.Message
+		seen    = make(map[string]struct{})
+	)
+
+	for _, m := range messages {
+		if m.Issue == "" || m.Suppressed {
+			continue
+		}
+
+		// comments reported by multiple rules are checked once
+		key := fmt.Sprintf("%s:%d:%s", m.Pos.Filename, m.Line, m.Issue)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+
+		seen[key] = struct{}{}
+
+		status, err := c.lookup(ctx, m.IssueTracker, m.Issue)
+		if errors.Is(err, ErrUnsupported) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case status == StatusClosed:
+			checked = append(checked, m.RewriteLimit(godox.RuleClosedIssue,
+				fmt.Sprintf("Issue %s is closed: ", m.Issue), c.MaxMessageLength))
+		case status == StatusMissing && c.ReportMissing:
+			checked = append(checked, m.RewriteLimit(godox.RuleMissingIssue,
+				fmt.Sprintf("Issue %s does not exist: ", m.Issue), c.MaxMessageLength))
+		}
+	}
+
+	return checked, nil
+}
+
+// lookup returns the status of the referenced issue from the cache or its provider.
+func (c *Checker) lookup(ctx context.Context, tracker, ref string) (Status, error) {
+	names := []string{tracker}
+
+	if tracker == "url" {
+		names = make([]string, 0, len(c.Providers))
+		for name := range c.Providers {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+	}
+
+	for _, name := range names {
+		provider, ok := c.Providers[name]
+		if !ok {
+			continue
+		}
+
+		// the statuses are cached by the canonical references as the cache is shared by all repositories
+		canonical, err := provider.Canonical(ref)
+		if errors.Is(err, ErrUnsupported) {
+			continue
+		}
+
+		if err != nil {
+			return "", err
+		}
+
+		key := name + " " + canonical
+		if status, ok := c.cache.get(key); ok {
+			return status, nil
+		}
+
+		status, err := provider.Lookup(ctx, ref)
+		if errors.Is(err, ErrUnsupported) {
+			continue
+		}
+
+		if err != nil {
+			return "", err
+		}
+
+		c.cache.put(key, status)
+
+		return status, nil
+	}
+
+	return "", ErrUnsupported
+}
@@ -0,0 +1,588 @@
+package tracker_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/matoous/godox"
+	"github.com/matoous/godox/config"
+	"github.com/matoous/godox/tracker"
+)
+
+// newGitHub returns server with open issue 1, closed issue 2 and rate limited issue 3 of owner/repo.
+func newGitHub(t *testing.T, requests *int32) *httptest.Server {
+	t.Helper()
+
+	var limited int32
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/repos/owner/repo/issues/1":
+			fmt.Fprint(w, `{"state": "open"}`)
+		case "/repos/owner/repo/issues/2":
+			fmt.Fprint(w, `{"state": "closed"}`)
+		case "/repos/owner/repo/issues/3":
+			if atomic.AddInt32(&limited, 1) == 1 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusForbidden)
+
+				return
+			}
+
+			fmt.Fprint(w, `{"state": "closed"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestGitHubLookup(t *testing.T) {
+	t.Parallel()
+
+	var requests int32
+
+	server := newGitHub(t, &requests)
+	defer server.Close()
+
+	github := &tracker.GitHub{APIURL: server.URL, Token: "secret", Repository: "owner/repo", Client: server.Client()}
+
+	tests := []struct {
+		ref      string
+		expected tracker.Status
+		err      bool
+	}{
+		{ref: "#1", expected: tracker.StatusOpen},
+		{ref: "owner/repo#2", expected: tracker.StatusClosed},
+		{ref: "https://github.com/owner/repo/issues/2", expected: tracker.StatusClosed},
+		{ref: "https://github.com/owner/repo/pull/1#discussion", expected: tracker.StatusOpen},
+		{ref: "#3", expected: tracker.StatusClosed},
+		{ref: "#4", expected: tracker.StatusMissing},
+		{ref: "other/repo#1", expected: tracker.StatusMissing},
+		{ref: "https://gitlab.com/owner/repo/issues/1", err: true},
+		{ref: "PROJ-1", err: true},
+	}
+
+	for _, tt := range tests {
+		actual, err := github.Lookup(context.Background(), tt.ref)
+		if (err != nil) != tt.err {
+			t.Fatalf("%s: unexpected error: %v", tt.ref, err)
+		}
+
+		if actual != tt.expected {
+			t.Errorf("%s: not equal\nexpected: %v\nactual: %v", tt.ref, tt.expected, actual)
+		}
+	}
+
+	if _, err := (&tracker.GitHub{APIURL: server.URL, Client: server.Client()}).Lookup(context.Background(), "#1"); err == nil {
+		t.Error("expected error for reference without repository")
+	}
+}
+
+func TestGitHubRateLimit(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", "9999999999")
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	github := &tracker.GitHub{APIURL: server.URL, Repository: "owner/repo", Client: server.Client(), MaxWait: tracker.DefaultMaxWait}
+
+	_, err := github.Lookup(context.Background(), "#1")
+	if err == nil || !strings.Contains(err.Error(), "rate limit exceeded") {
+		t.Errorf("expected rate limit error, got %v", err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	const src = `package main
+
+// TODO(#1): open
+// TODO(#2): closed
+// TODO(#4): missing
+// TODO(PROJ-1): other tracker
+// TODO: no reference
+func main() {}
+`
+
+	var requests int32
+
+	server := newGitHub(t, &requests)
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "godox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages := godox.Run(f, fset, &config.GoDoxSettings{})
+
+	for _, tt := range []struct {
+		missing  bool
+		expected []string
+	}{
+		{expected: []string{`main.go:4: Issue #2 is closed: "TODO(#2): closed"`}},
+		{missing: true, expected: []string{
+			`main.go:4: Issue #2 is closed: "TODO(#2): closed"`,
+			`main.go:5: Issue #4 does not exist: "TODO(#4): missing"`,
+		}},
+	} {
+		settings, err := (&config.GoDoxSettings{
+			CheckIssues:         true,
+			ReportMissingIssues: tt.missing,
+			CacheDir:            dir,
+			GitHub:              config.GitHubSettings{Repository: "owner/repo", APIURL: server.URL, TokenEnv: "GODOX_TEST_TOKEN"},
+		}).Compile()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		checker := tracker.NewChecker(settings)
+		checker.Providers["github"].(*tracker.GitHub).Token = "secret"
+
+		checked, err := checker.Check(context.Background(), messages)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var actual []string
+		for _, m := range checked {
+			actual = append(actual, m.Message)
+		}
+
+		if fmt.Sprint(actual) != fmt.Sprint(tt.expected) {
+			t.Errorf("not equal\nexpected: %q\nactual: %q", tt.expected, actual)
+		}
+	}
+
+	// the second check uses the statuses cached by the first one
+	if requests != 3 {
+		t.Errorf("expected 3 requests, got %d", requests)
+	}
+}
+
+func TestCheckRepositories(t *testing.T) {
+	t.Parallel()
+
+	var requests int32
+
+	server := newGitHub(t, &requests)
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "godox")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, "main.go", "package main\n\n// TODO(#2): closed in owner/repo only\n", parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages := godox.Run(f, fset, &config.GoDoxSettings{})
+
+	// the repositories share the cache, #2 of the other repository doesn't exist
+	for _, tt := range []struct {
+		repository string
+		expected   []string
+	}{
+		{repository: "owner/repo", expected: []string{`main.go:3: Issue #2 is closed: "TODO(#2): closed in owner/repo only"`}},
+		{repository: "other/repo", expected: []string{`main.go:3: Issue #2 does not exist: "TODO(#2): closed in owner/repo only"`}},
+	} {
+		settings, err := (&config.GoDoxSettings{
+			CheckIssues:         true,
+			ReportMissingIssues: true,
+			CacheDir:            dir,
+			GitHub:              config.GitHubSettings{Repository: tt.repository, APIURL: server.URL},
+		}).Compile()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		checker := tracker.NewChecker(settings)
+		checker.Providers["github"].(*tracker.GitHub).Token = "secret"
+
+		checked, err := checker.Check(context.Background(), messages)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var actual []string
+		for _, m := range checked {
+			actual = append(actual, m.Message)
+		}
+
+		if fmt.Sprint(actual) != fmt.Sprint(tt.expected) {
+			t.Errorf("%s: not equal\nexpected: %q\nactual: %q", tt.repository, tt.expected, actual)
+		}
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		provider tracker.Provider
+		ref      string
+		expected string
+	}{
+		{provider: &tracker.GitHub{APIURL: "https://api.github.com", Repository: "owner/repo"}, ref: "#1", expected: "github.com/owner/repo#1"},
+		{
+			provider: &tracker.GitHub{APIURL: "https://github.example.com/api/v3"},
+			ref:      "https://github.example.com/owner/repo/pull/2",
+			expected: "github.example.com/owner/repo#2",
+		},
+		{provider: &tracker.GitLab{URL: "https://gitlab.com", Project: "group/project"}, ref: "#3", expected: "gitlab.com/group/project#3"},
+		{
+			provider: &tracker.GitLab{URL: "https://gitlab.com"},
+			ref:      "https://gitlab.com/group/project/-/merge_requests/4",
+			expected: "gitlab.com/group/project!4",
+		},
+		{provider: &tracker.Jira{URL: "https://jira.example.com"}, ref: "PROJ-5", expected: "jira.example.com/PROJ-5"},
+		{provider: &tracker.Jira{URL: "https://jira.example.com"}, ref: "https://jira.example.com/browse/PROJ-6", expected: "jira.example.com/PROJ-6"},
+	}
+
+	for _, tt := range tests {
+		actual, err := tt.provider.Canonical(tt.ref)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.ref, err)
+		}
+
+		if actual != tt.expected {
+			t.Errorf("%s: not equal\nexpected: %v\nactual: %v", tt.ref, tt.expected, actual)
+		}
+	}
+}
+
+func TestJiraLookup(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, token, ok := r.BasicAuth(); !ok || user != "alice@example.com" || token != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/rest/api/2/issue/PROJ-1":
+			fmt.Fprint(w, `{"fields": {"status": {"name": "In Progress", "statusCategory": {"key": "indeterminate"}}}}`)
+		case "/rest/api/2/issue/PROJ-2":
+			fmt.Fprint(w, `{"fields": {"status": {"name": "Resolved", "statusCategory": {"key": "done"}}}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	jira := &tracker.Jira{URL: server.URL + "/", User: "alice@example.com", Token: "secret", Client: server.Client()}
+
+	tests := []struct {
+		ref      string
+		expected tracker.Status
+		err      bool
+	}{
+		{ref: "PROJ-1", expected: tracker.StatusOpen},
+		{ref: "PROJ-2", expected: tracker.StatusClosed},
+		{ref: server.URL + "/browse/PROJ-2", expected: tracker.StatusClosed},
+		{ref: "PROJ-3", expected: tracker.StatusMissing},
+		{ref: "#1", err: true},
+		{ref: "https://github.com/owner/repo/issues/1", err: true},
+	}
+
+	for _, tt := range tests {
+		actual, err := jira.Lookup(context.Background(), tt.ref)
+		if (err != nil) != tt.err {
+			t.Fatalf("%s: unexpected error: %v", tt.ref, err)
+		}
+
+		if actual != tt.expected {
+			t.Errorf("%s: not equal\nexpected: %v\nactual: %v", tt.ref, tt.expected, actual)
+		}
+	}
+
+	jira.Token = "wrong"
+
+	if _, err := jira.Lookup(context.Background(), "PROJ-1"); err == nil {
+		t.Error("expected error for unauthorized request")
+	}
+}
+
+func TestCheckJira(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rest/api/2/issue/PROJ-2" {
+			fmt.Fprint(w, `{"fields": {"status": {"statusCategory": {"key": "done"}}}}`)
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	src := "package main\n\n// TODO(PROJ-2): done\n// TODO: see " + server.URL + "/browse/PROJ-3\n"
+
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	settings, err := (&config.GoDoxSettings{ReportMissingIssues: true, Jira: config.JiraSettings{URL: server.URL}}).Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checker := tracker.NewChecker(settings)
+	checker.Providers["jira"].(*tracker.Jira).Client = server.Client()
+
+	checked, err := checker.Check(context.Background(), godox.Run(f, fset, &config.GoDoxSettings{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(checked) != 2 || checked[0].RuleID != godox.RuleClosedIssue || checked[1].RuleID != godox.RuleMissingIssue {
+		t.Errorf("unexpected messages: %q", checked)
+	}
+}
+
+func TestGitLabLookup(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Private-Token") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.EscapedPath() {
+		case "/api/v4/projects/group%2Fproject/issues/1":
+			fmt.Fprint(w, `{"state": "opened"}`)
+		case "/api/v4/projects/group%2Fsub%2Fproject/issues/2":
+			fmt.Fprint(w, `{"state": "closed"}`)
+		case "/api/v4/projects/group%2Fproject/merge_requests/3":
+			fmt.Fprint(w, `{"state": "merged"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	gitlab := &tracker.GitLab{URL: server.URL, Token: "secret", Project: "group/project", Client: server.Client()}
+
+	tests := []struct {
+		ref      string
+		expected tracker.Status
+		err      bool
+	}{
+		{ref: "#1", expected: tracker.StatusOpen},
+		{ref: "group/sub/project#2", expected: tracker.StatusClosed},
+		{ref: server.URL + "/group/sub/project/-/issues/2", expected: tracker.StatusClosed},
+		{ref: server.URL + "/group/project/-/merge_requests/3", expected: tracker.StatusClosed},
+		{ref: "#4", expected: tracker.StatusMissing},
+		{ref: "https://gitlab.com/group/project/-/issues/1", err: true},
+		{ref: "PROJ-1", err: true},
+	}
+
+	for _, tt := range tests {
+		actual, err := gitlab.Lookup(context.Background(), tt.ref)
+		if (err != nil) != tt.err {
+			t.Fatalf("%s: unexpected error: %v", tt.ref, err)
+		}
+
+		if actual != tt.expected {
+			t.Errorf("%s: not equal\nexpected: %v\nactual: %v", tt.ref, tt.expected, actual)
+		}
+	}
+}
+
+func TestExecLookup(t *testing.T) {
+	t.Parallel()
+
+	provider := &tracker.Exec{Command: []string{"sh", "-c", `case "$1" in
+T-1) echo open ;;
+T-2) echo closed ;;
+T-3) echo missing ;;
+T-4) echo what ;;
+T-5) echo broken >&2; exit 1 ;;
+*) echo unsupported ;;
+esac`, "sh"}}
+
+	tests := []struct {
+		ref         string
+		expected    tracker.Status
+		err         bool
+		unsupported bool
+	}{
+		{ref: "T-1", expected: tracker.StatusOpen},
+		{ref: "T-2", expected: tracker.StatusClosed},
+		{ref: "T-3", expected: tracker.StatusMissing},
+		{ref: "T-4", err: true},
+		{ref: "T-5", err: true},
+		{ref: "X-1", err: true, unsupported: true},
+	}
+
+	for _, tt := range tests {
+		actual, err := provider.Lookup(context.Background(), tt.ref)
+		if (err != nil) != tt.err {
+			t.Fatalf("%s: unexpected error: %v", tt.ref, err)
+		}
+
+		if errors.Is(err, tracker.ErrUnsupported) != tt.unsupported {
+			t.Errorf("%s: unexpected unsupported reference error: %v", tt.ref, err)
+		}
+
+		if actual != tt.expected {
+			t.Errorf("%s: not equal\nexpected: %v\nactual: %v", tt.ref, tt.expected, actual)
+		}
+	}
+}
+
+func TestCheckCommand(t *testing.T) {
+	t.Parallel()
+
+	const src = `package main
+
+// TODO(T-1): open
+// TODO(T-2): closed
+func main() {}
+`
+
+	fset := token.NewFileSet()
+
+	f, err := parser.ParseFile(fset, "main.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	settings := &config.GoDoxSettings{
+		IssuePatterns:   []config.IssuePattern{{Tracker: "custom", RegularExpression: `\bT-\d+\b`}},
+		TrackerCommands: []config.TrackerCommand{{Tracker: "custom", Command: []string{"sh", "-c", `[ "$1" = T-2 ] && echo closed || echo open`, "sh"}}},
+	}
+
+	compiled, err := settings.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checked, err := tracker.NewChecker(compiled).Check(context.Background(), godox.Run(f, fset, settings))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(checked) != 1 || checked[0].Message != `main.go:4: Issue T-2 is closed: "TODO(T-2): closed"` {
+		t.Errorf("unexpected messages: %q", checked)
+	}
+}
+
+func TestCreate(t *testing.T) {
+	t.Parallel()
+
+	var requests []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.EscapedPath()+" "+strings.TrimSpace(string(body)))
+
+		w.WriteHeader(http.StatusCreated)
+
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/repos/"):
+			fmt.Fprint(w, `{"number": 7}`)
+		case strings.HasPrefix(r.URL.Path, "/api/v4/"):
+			fmt.Fprint(w, `{"iid": 8}`)
+		default:
+			fmt.Fprint(w, `{"key": "PROJ-9"}`)
+		}
+	}))
+	defer server.Close()
+
+	settings, err := (&config.GoDoxSettings{
+		GitHub: config.GitHubSettings{Repository: "owner/repo", APIURL: server.URL},
+		GitLab: config.GitLabSettings{Project: "group/project", URL: server.URL},
+		Jira:   config.JiraSettings{URL: server.URL, Project: "PROJ"},
+	}).Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issue := tracker.Issue{Title: "refactor", Body: "TODO: refactor", Labels: []string{"debt"}}
+
+	for _, tt := range []struct {
+		tracker  string
+		expected string
+		request  string
+	}{
+		{
+			tracker:  "github",
+			expected: "#7",
+			request:  `POST /repos/owner/repo/issues {"title":"refactor","body":"TODO: refactor","labels":["debt"]}`,
+		},
+		{
+			tracker:  "gitlab",
+			expected: "#8",
+			request:  `POST /api/v4/projects/group%2Fproject/issues {"title":"refactor","description":"TODO: refactor","labels":"debt"}`,
+		},
+		{
+			tracker:  "jira",
+			expected: "PROJ-9",
+			request: `POST /rest/api/2/issue {"fields":{"project":{"key":"PROJ"},"issuetype":{"name":"Task"},` +
+				`"summary":"refactor","description":"TODO: refactor","labels":["debt"]}}`,
+		},
+	} {
+		creator, err := tracker.NewCreator(tt.tracker, settings)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ref, err := creator.Create(context.Background(), issue)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.tracker, err)
+		}
+
+		if ref != tt.expected {
+			t.Errorf("%s: not equal\nexpected: %v\nactual: %v", tt.tracker, tt.expected, ref)
+		}
+
+		if actual := requests[len(requests)-1]; actual != tt.request {
+			t.Errorf("%s: not equal\nexpected: %v\nactual: %v", tt.tracker, tt.request, actual)
+		}
+	}
+
+	if _, err := tracker.NewCreator("redmine", settings); err == nil {
+		t.Error("expected error for unknown tracker")
+	}
+}